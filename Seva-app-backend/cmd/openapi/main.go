@@ -0,0 +1,999 @@
+// Command openapi generates the OpenAPI 3 document served at /openapi.json. It reads the source
+// rather than running the app: routes come from main.go and the Register functions it calls, and
+// each route is described from its handler — the "Name - METHOD /path?query (Role)" doc comment,
+// the c.Query* calls it makes, the DTO it parses the body into and the value it passes to c.JSON.
+// Schemas are generated from the structs in models (and the handler's own package).
+//
+// Re-run it after adding or changing routes:
+//
+//	go generate ./handlers/docs
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+func main() {
+	root := flag.String("root", ".", "module root (the directory holding go.mod)")
+	out := flag.String("o", "handlers/docs/openapi.json", "output file, relative to the working directory")
+	flag.Parse()
+
+	g, err := newGenerator(*root)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := g.collectRoutes(); err != nil {
+		log.Fatal(err)
+	}
+	doc := g.document()
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(doc); err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*out, buf.Bytes(), 0o644); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("wrote %s: %d operations, %d schemas", *out, len(g.routes), len(g.schemas))
+}
+
+// pkg is one parsed Go package directory.
+type pkg struct {
+	name    string
+	funcs   map[string]*ast.FuncDecl
+	types   map[string]*ast.TypeSpec
+	enums   map[string][]string // named string type -> its constant values
+	imports map[string]string   // alias -> directory, for module-local imports
+}
+
+type route struct {
+	method, path string
+	pkg          *pkg
+	handler      string
+	secured      bool
+	roles        []string
+	eventScoped  bool
+	idempotent   bool
+}
+
+type generator struct {
+	root, module string
+	pkgs         map[string]*pkg // by directory
+	models       *pkg
+	routes       []route
+	schemas      map[string]any
+}
+
+func newGenerator(root string) (*generator, error) {
+	mod, err := os.ReadFile(filepath.Join(root, "go.mod"))
+	if err != nil {
+		return nil, err
+	}
+	first, _, _ := strings.Cut(string(mod), "\n")
+	g := &generator{
+		root:    root,
+		module:  strings.TrimSpace(strings.TrimPrefix(first, "module")),
+		pkgs:    map[string]*pkg{},
+		schemas: map[string]any{},
+	}
+	if g.models, err = g.load(filepath.Join(root, "models")); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// load parses the non-test files of dir (once) and indexes its funcs, types, enums and imports.
+func (g *generator) load(dir string) (*pkg, error) {
+	if p, ok := g.pkgs[dir]; ok {
+		return p, nil
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	p := &pkg{funcs: map[string]*ast.FuncDecl{}, types: map[string]*ast.TypeSpec{}, enums: map[string][]string{}, imports: map[string]string{}}
+	fset := token.NewFileSet()
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, file, nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		p.name = f.Name.Name
+		for _, imp := range f.Imports {
+			path, _ := strconv.Unquote(imp.Path.Value)
+			if !strings.HasPrefix(path, g.module+"/") {
+				continue
+			}
+			alias := filepath.Base(path)
+			if imp.Name != nil {
+				alias = imp.Name.Name
+			}
+			p.imports[alias] = filepath.Join(g.root, strings.TrimPrefix(path, g.module+"/"))
+		}
+		for _, decl := range f.Decls {
+			switch d := decl.(type) {
+			case *ast.FuncDecl:
+				if d.Recv == nil {
+					p.funcs[d.Name.Name] = d
+				}
+			case *ast.GenDecl:
+				for _, spec := range d.Specs {
+					switch s := spec.(type) {
+					case *ast.TypeSpec:
+						p.types[s.Name.Name] = s
+					case *ast.ValueSpec:
+						if d.Tok != token.CONST || s.Type == nil || len(s.Values) != len(s.Names) {
+							continue
+						}
+						typ, ok := s.Type.(*ast.Ident)
+						if !ok {
+							continue
+						}
+						for _, v := range s.Values {
+							if lit, ok := v.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+								val, _ := strconv.Unquote(lit.Value)
+								p.enums[typ.Name] = append(p.enums[typ.Name], val)
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+	g.pkgs[dir] = p
+	return p, nil
+}
+
+// collectRoutes walks main() in main.go: app.Group assignments, direct app/group route registrations
+// and calls to a handler package's Register(group, ...).
+func (g *generator) collectRoutes() error {
+	mainPkg, err := g.load(g.root)
+	if err != nil {
+		return err
+	}
+	fn := mainPkg.funcs["main"]
+	if fn == nil {
+		return fmt.Errorf("no func main in %s", g.root)
+	}
+	groups := map[string]string{"app": ""}
+	for _, stmt := range fn.Body.List {
+		switch s := stmt.(type) {
+		case *ast.AssignStmt:
+			if len(s.Lhs) != 1 || len(s.Rhs) != 1 {
+				continue
+			}
+			call, ok := s.Rhs[0].(*ast.CallExpr)
+			if !ok {
+				continue
+			}
+			if recv, name := selector(call.Fun); name == "Group" && len(call.Args) > 0 {
+				if prefix, ok := groups[recv]; ok {
+					groups[ident(s.Lhs[0])] = prefix + stringLit(call.Args[0])
+				}
+			}
+		case *ast.ExprStmt:
+			call, ok := s.X.(*ast.CallExpr)
+			if !ok {
+				continue
+			}
+			recv, name := selector(call.Fun)
+			if name == "Register" && len(call.Args) > 0 {
+				dir, ok := mainPkg.imports[recv]
+				prefix, known := groups[ident(call.Args[0])]
+				if !ok || !known {
+					continue
+				}
+				p, err := g.load(dir)
+				if err != nil {
+					return err
+				}
+				g.collectRegister(p, prefix)
+				continue
+			}
+			if prefix, ok := groups[recv]; ok {
+				g.addRoute(mainPkg, prefix, name, call)
+			}
+		}
+	}
+	return nil
+}
+
+// collectRegister adds the routes registered on Register's router parameter.
+func (g *generator) collectRegister(p *pkg, prefix string) {
+	fn := p.funcs["Register"]
+	if fn == nil || len(fn.Type.Params.List) == 0 || len(fn.Type.Params.List[0].Names) == 0 {
+		return
+	}
+	router := fn.Type.Params.List[0].Names[0].Name
+	for _, stmt := range fn.Body.List {
+		es, ok := stmt.(*ast.ExprStmt)
+		if !ok {
+			continue
+		}
+		call, ok := es.X.(*ast.CallExpr)
+		if !ok {
+			continue
+		}
+		if recv, name := selector(call.Fun); recv == router {
+			g.addRoute(p, prefix, name, call)
+		}
+	}
+}
+
+var httpMethods = map[string]string{"Get": "get", "Post": "post", "Put": "put", "Patch": "patch", "Delete": "delete"}
+
+// addRoute records router.Method("/path", middleware..., Handler(...)) registered from package from.
+func (g *generator) addRoute(from *pkg, prefix, method string, call *ast.CallExpr) {
+	m, ok := httpMethods[method]
+	if !ok || len(call.Args) < 2 {
+		return
+	}
+	r := route{method: m, path: openAPIPath(prefix + stringLit(call.Args[0]))}
+
+	// The handler is the last argument: Handler(pool) in the package itself, or pkg.Handler(pool) from main
+	hcall, ok := call.Args[len(call.Args)-1].(*ast.CallExpr)
+	if !ok {
+		return
+	}
+	switch fun := hcall.Fun.(type) {
+	case *ast.Ident:
+		r.pkg, r.handler = from, fun.Name
+	case *ast.SelectorExpr:
+		dir, ok := from.imports[ident(fun.X)]
+		if !ok {
+			return
+		}
+		p, err := g.load(dir)
+		if err != nil {
+			log.Printf("skipping %s %s: %v", method, r.path, err)
+			return
+		}
+		r.pkg, r.handler = p, fun.Sel.Name
+	default:
+		return
+	}
+
+	for _, arg := range call.Args[1 : len(call.Args)-1] {
+		switch a := arg.(type) {
+		case *ast.Ident:
+			switch a.Name {
+			case "jwtGuard":
+				r.secured = true
+			case "requireAdmin":
+				r.roles = []string{"admin"}
+			case "requireFaculty":
+				r.roles = []string{"faculty", "admin"}
+			case "requireVolunteer":
+				r.roles = []string{"volunteer", "admin"}
+			case "eventScope", "attendanceScope":
+				r.eventScoped = true
+			case "idempotent":
+				r.idempotent = true
+			}
+		case *ast.CallExpr:
+			switch _, name := selector(a.Fun); name {
+			case "RequireRole":
+				r.roles = nil
+				for _, roleArg := range a.Args {
+					// string(models.UserRoleFaculty) -> "faculty"
+					if conv, ok := roleArg.(*ast.CallExpr); ok && len(conv.Args) == 1 {
+						if _, c := selector(conv.Args[0]); c != "" {
+							r.roles = append(r.roles, strings.ToLower(strings.TrimPrefix(c, "UserRole")))
+						}
+					}
+				}
+			case "RequireEventScope":
+				r.eventScoped = true
+			}
+		}
+	}
+	g.routes = append(g.routes, r)
+}
+
+var pathParam = regexp.MustCompile(`:(\w+)`)
+
+// openAPIPath turns a Fiber path ("/faculty/:id/") into an OpenAPI one ("/faculty/{id}").
+func openAPIPath(p string) string {
+	p = pathParam.ReplaceAllString(p, "{$1}")
+	if len(p) > 1 {
+		p = strings.TrimSuffix(p, "/")
+	}
+	return p
+}
+
+// document assembles the OpenAPI document from the collected routes.
+func (g *generator) document() map[string]any {
+	paths := map[string]map[string]any{}
+	opIDs := map[string]int{}
+	for _, r := range g.routes {
+		op := g.operation(r)
+		id := r.pkg.name + r.handler
+		if r.pkg.name == "main" {
+			id = r.handler
+		}
+		if opIDs[id]++; opIDs[id] > 1 {
+			id += strconv.Itoa(opIDs[id])
+		}
+		op["operationId"] = id
+		if paths[r.path] == nil {
+			paths[r.path] = map[string]any{}
+		}
+		paths[r.path][r.method] = op
+	}
+	return map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":       "Seva App API",
+			"version":     "1.0.0",
+			"description": "Generated by cmd/openapi from the route registrations and handler doc comments; do not edit by hand.",
+		},
+		"paths": paths,
+		"components": map[string]any{
+			"schemas": g.schemas,
+			"securitySchemes": map[string]any{
+				"bearerAuth": map[string]any{"type": "http", "scheme": "bearer", "bearerFormat": "JWT"},
+			},
+		},
+	}
+}
+
+// firstSentence splits a paragraph after its first full stop, not counting abbreviations
+// like "e.g." that are followed by more of the same sentence.
+func firstSentence(p string) (string, string) {
+	for i := 0; i+1 < len(p); i++ {
+		if p[i] != '.' || p[i+1] != ' ' {
+			continue
+		}
+		if word := p[strings.LastIndexByte(p[:i], ' ')+1 : i]; word == "e.g" || word == "i.e" || word == "(e.g" || word == "(i.e" {
+			continue
+		}
+		return p[:i+1], strings.TrimSpace(p[i+2:])
+	}
+	return p, ""
+}
+
+// routeLine matches the route line of a handler comment, normally "Name - METHOD /path?query (Role)".
+var routeLine = regexp.MustCompile(`\b(GET|POST|PUT|PATCH|DELETE) (/\S*)`)
+
+func (g *generator) operation(r route) map[string]any {
+	fn := r.pkg.funcs[r.handler]
+	op := map[string]any{"tags": []string{tag(r.path)}}
+
+	var summary string
+	var notes []string
+	inSummary := true
+	queryFromDoc := map[string]bool{}
+	if fn != nil && fn.Doc != nil {
+		for _, line := range strings.Split(strings.TrimSpace(fn.Doc.Text()), "\n") {
+			line = strings.TrimSpace(line)
+			if m := routeLine.FindStringSubmatch(line); m != nil {
+				if _, q, ok := strings.Cut(m[2], "?"); ok {
+					for _, kv := range strings.Split(strings.Trim(q, "[]"), "&") {
+						k, _, _ := strings.Cut(strings.TrimLeft(kv, "?"), "=")
+						if k != "" && !strings.ContainsAny(k, "<> ") {
+							queryFromDoc[k] = true
+						}
+					}
+				}
+				continue
+			}
+			// Skip banners ("---------- /auth/login ----------") and leftover "..." placeholders
+			if strings.HasPrefix(line, "---") || strings.HasPrefix(line, "...") {
+				continue
+			}
+			// The first paragraph is often wrapped over several lines; gather it whole and
+			// take its first sentence as the summary, the rest goes into the description.
+			switch {
+			case inSummary && line == "":
+				inSummary = summary == ""
+			case inSummary && strings.HasPrefix(line, "- ") && summary != "":
+				inSummary = false
+				notes = append(notes, line)
+			case inSummary:
+				summary = strings.TrimSpace(summary + " " + line)
+			default:
+				notes = append(notes, line)
+			}
+		}
+	}
+	if first, rest := firstSentence(summary); rest != "" {
+		summary = first
+		notes = append([]string{rest}, notes...)
+	}
+	if summary == "" {
+		summary = humanize(r.handler)
+	}
+	op["summary"] = summary
+
+	var desc []string
+	if len(notes) > 0 {
+		desc = append(desc, strings.TrimSpace(strings.Join(notes, "\n")))
+	}
+	if len(r.roles) > 0 {
+		desc = append(desc, "Roles: "+strings.Join(r.roles, ", ")+".")
+		op["x-roles"] = r.roles
+	}
+	if r.eventScoped {
+		desc = append(desc, "Faculty with event scopes may only access their own events.")
+	}
+	if len(desc) > 0 {
+		op["description"] = strings.Join(desc, "\n\n")
+	}
+	if r.secured {
+		op["security"] = []map[string][]string{{"bearerAuth": {}}}
+	}
+
+	// Parameters: path, then query (from the handler body and its doc line), then headers
+	var params []map[string]any
+	for _, m := range pathParam.FindAllStringSubmatch(strings.NewReplacer("{", ":", "}", "").Replace(r.path), -1) {
+		params = append(params, map[string]any{"name": m[1], "in": "path", "required": true, "schema": paramSchema(m[1], "")})
+	}
+	query := map[string]string{}
+	if fn != nil {
+		g.queryParams(r.pkg, fn, query, map[string]bool{})
+	}
+	for k := range queryFromDoc {
+		if _, ok := query[k]; !ok {
+			query[k] = "Query"
+		}
+	}
+	names := make([]string, 0, len(query))
+	for k := range query {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	for _, k := range names {
+		params = append(params, map[string]any{"name": k, "in": "query", "schema": paramSchema(k, query[k])})
+	}
+	if r.idempotent {
+		params = append(params, map[string]any{
+			"name": "Idempotency-Key", "in": "header", "schema": map[string]any{"type": "string"},
+			"description": "Retries with the same key replay the stored response instead of repeating the request.",
+		})
+	}
+	if len(params) > 0 {
+		op["parameters"] = params
+	}
+
+	responses := map[string]any{}
+	if fn != nil {
+		if body := g.requestBody(r.pkg, fn); body != nil {
+			op["requestBody"] = map[string]any{"required": true, "content": map[string]any{"application/json": map[string]any{"schema": body}}}
+		}
+		status, schema, contentType := g.response(r.pkg, fn)
+		resp := map[string]any{"description": "Success"}
+		if contentType != "" {
+			if schema == nil {
+				schema = map[string]any{"type": "string"}
+			}
+			resp["content"] = map[string]any{contentType: map[string]any{"schema": schema}}
+		}
+		responses[status] = resp
+	} else {
+		responses["200"] = map[string]any{"description": "Success"}
+	}
+	if r.secured {
+		responses["401"] = map[string]any{"description": "Missing or invalid token"}
+	}
+	if len(r.roles) > 0 || r.eventScoped {
+		responses["403"] = map[string]any{"description": "Not allowed for this role or event"}
+	}
+	op["responses"] = responses
+	return op
+}
+
+// queryParams collects c.Query/QueryInt/QueryBool/QueryFloat keys used by fn and by same-package
+// helpers it passes the context c to (e.g. buildAssignmentFilters(c)).
+func (g *generator) queryParams(p *pkg, fn *ast.FuncDecl, out map[string]string, seen map[string]bool) {
+	if seen[fn.Name.Name] {
+		return
+	}
+	seen[fn.Name.Name] = true
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		switch fun := call.Fun.(type) {
+		case *ast.SelectorExpr:
+			switch fun.Sel.Name {
+			case "Query", "QueryInt", "QueryBool", "QueryFloat":
+				if ident(fun.X) == "c" && len(call.Args) > 0 {
+					if k := stringLit(call.Args[0]); k != "" {
+						if _, ok := out[k]; !ok || out[k] == "Query" {
+							out[k] = fun.Sel.Name
+						}
+					}
+				}
+			}
+		case *ast.Ident:
+			helper := p.funcs[fun.Name]
+			if helper == nil {
+				return true
+			}
+			for _, a := range call.Args {
+				if ident(a) == "c" {
+					g.queryParams(p, helper, out, seen)
+					break
+				}
+			}
+		}
+		return true
+	})
+}
+
+// requestBody returns the schema of the value fn passes to BodyParser/StrictBodyParser, if any.
+func (g *generator) requestBody(p *pkg, fn *ast.FuncDecl) map[string]any {
+	var schema map[string]any
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || schema != nil {
+			return schema == nil
+		}
+		_, name := selector(call.Fun)
+		if name != "BodyParser" && name != "StrictBodyParser" || len(call.Args) == 0 {
+			return true
+		}
+		arg := call.Args[len(call.Args)-1]
+		if u, ok := arg.(*ast.UnaryExpr); ok {
+			arg = u.X
+		}
+		if t := declaredType(p, fn, ident(arg)); t != nil {
+			schema = g.schemaFor(p, t)
+		}
+		return false
+	})
+	return schema
+}
+
+// response guesses fn's success status and body from its c.JSON / c.Status(...).JSON calls;
+// handlers that set a text/csv content type are reported as CSV downloads.
+func (g *generator) response(p *pkg, fn *ast.FuncDecl) (status string, schema map[string]any, contentType string) {
+	status = "200"
+	csv := false
+	noContent := false
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		switch _, name := selector(call.Fun); {
+		case name == "Set" && len(call.Args) == 2 && strings.Contains(stringLit(call.Args[1]), "text/csv"):
+			csv = true
+		case name == "SendStatus" && len(call.Args) == 1 && statusCode(call.Args[0]) == "204":
+			noContent = true
+		}
+		if schema != nil {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != "JSON" || len(call.Args) != 1 {
+			return true
+		}
+		code := "200"
+		if inner, ok := sel.X.(*ast.CallExpr); ok {
+			if _, name := selector(inner.Fun); name == "Status" && len(inner.Args) == 1 {
+				code = statusCode(inner.Args[0])
+			}
+		} else if ident(sel.X) != "c" {
+			return true
+		}
+		if !strings.HasPrefix(code, "2") || code == "204" {
+			return true
+		}
+		if s := g.valueSchema(p, fn, call.Args[0]); s != nil {
+			status, schema, contentType = code, s, "application/json"
+		}
+		return true
+	})
+	if csv {
+		return "200", nil, "text/csv"
+	}
+	if schema == nil && noContent {
+		return "204", nil, ""
+	}
+	if schema == nil && contentType == "" && hasJSONCall(fn) {
+		contentType = "application/json"
+		schema = map[string]any{"type": "object"}
+	}
+	return status, schema, contentType
+}
+
+func hasJSONCall(fn *ast.FuncDecl) bool {
+	found := false
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok {
+			if _, name := selector(call.Fun); name == "JSON" {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// valueSchema describes an expression passed to c.JSON: a local variable, a composite literal
+// (models.X{...}, []models.X{...}) or a fiber.Map literal.
+func (g *generator) valueSchema(p *pkg, fn *ast.FuncDecl, e ast.Expr) map[string]any {
+	switch v := e.(type) {
+	case *ast.Ident:
+		if t := declaredType(p, fn, v.Name); t != nil {
+			return g.schemaFor(p, t)
+		}
+	case *ast.CompositeLit:
+		if recv, name := selector(v.Type); recv == "fiber" && name == "Map" {
+			props := map[string]any{}
+			for _, elt := range v.Elts {
+				if kv, ok := elt.(*ast.KeyValueExpr); ok {
+					if k := stringLit(kv.Key); k != "" {
+						props[k] = map[string]any{}
+					}
+				}
+			}
+			return map[string]any{"type": "object", "properties": props}
+		}
+		if v.Type != nil {
+			return g.schemaFor(p, v.Type)
+		}
+	case *ast.UnaryExpr:
+		return g.valueSchema(p, fn, v.X)
+	}
+	return nil
+}
+
+// declaredType finds the type of local name in fn: var name T, name := T{...}, name := []T{},
+// name := make([]T, ...), name := new(T), or name, err := helper(...) for a helper in package p.
+func declaredType(p *pkg, fn *ast.FuncDecl, name string) ast.Expr {
+	if name == "" {
+		return nil
+	}
+	var found ast.Expr
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		if found != nil {
+			return false
+		}
+		switch s := n.(type) {
+		case *ast.ValueSpec:
+			for i, id := range s.Names {
+				if id.Name != name {
+					continue
+				}
+				if s.Type != nil {
+					found = s.Type
+				} else if i < len(s.Values) {
+					found = exprType(s.Values[i])
+				}
+			}
+		case *ast.AssignStmt:
+			if s.Tok != token.DEFINE {
+				return true
+			}
+			for i, lhs := range s.Lhs {
+				if ident(lhs) != name {
+					continue
+				}
+				if len(s.Lhs) == len(s.Rhs) {
+					found = exprType(s.Rhs[i])
+				} else if call, ok := s.Rhs[0].(*ast.CallExpr); ok && len(s.Rhs) == 1 {
+					found = resultType(p, call, i)
+				}
+			}
+		}
+		return true
+	})
+	return found
+}
+
+// resultType is the type of the i-th result of a call to a function declared in p.
+func resultType(p *pkg, call *ast.CallExpr, i int) ast.Expr {
+	callee := p.funcs[ident(call.Fun)]
+	if callee == nil || callee.Type.Results == nil {
+		return nil
+	}
+	n := 0
+	for _, field := range callee.Type.Results.List {
+		count := max(len(field.Names), 1)
+		if i < n+count {
+			return field.Type
+		}
+		n += count
+	}
+	return nil
+}
+
+func exprType(e ast.Expr) ast.Expr {
+	switch v := e.(type) {
+	case *ast.CompositeLit:
+		return v.Type
+	case *ast.UnaryExpr:
+		return exprType(v.X)
+	case *ast.CallExpr:
+		if fun := ident(v.Fun); (fun == "make" || fun == "new") && len(v.Args) > 0 {
+			return v.Args[0]
+		}
+	}
+	return nil
+}
+
+// schemaFor converts a type expression seen in package p into a schema, registering named
+// struct and enum types under components/schemas.
+func (g *generator) schemaFor(p *pkg, t ast.Expr) map[string]any {
+	switch v := t.(type) {
+	case *ast.StarExpr:
+		s := g.schemaFor(p, v.X)
+		if ref, ok := s["$ref"]; ok {
+			return map[string]any{"allOf": []any{map[string]any{"$ref": ref}}, "nullable": true}
+		}
+		s["nullable"] = true
+		return s
+	case *ast.ArrayType:
+		if ident(v.Elt) == "byte" {
+			return map[string]any{"type": "string", "format": "byte"}
+		}
+		return map[string]any{"type": "array", "items": g.schemaFor(p, v.Elt)}
+	case *ast.MapType:
+		return map[string]any{"type": "object", "additionalProperties": g.schemaFor(p, v.Value)}
+	case *ast.InterfaceType:
+		return map[string]any{}
+	case *ast.StructType:
+		return g.structSchema(p, v)
+	case *ast.IndexExpr:
+		// Generic instantiation such as models.Page[models.AuditLog]
+		base := g.schemaFor(p, v.X)
+		return map[string]any{"allOf": []any{base}, "x-type-argument": typeName(v.Index)}
+	case *ast.SelectorExpr:
+		switch pkgName, name := ident(v.X), v.Sel.Name; {
+		case pkgName == "time" && name == "Time":
+			return map[string]any{"type": "string", "format": "date-time"}
+		case pkgName == "time" && name == "Duration":
+			return map[string]any{"type": "integer", "format": "int64"}
+		case pkgName == "json" && name == "RawMessage":
+			return map[string]any{}
+		case pkgName == "fiber" && name == "Map":
+			return map[string]any{"type": "object"}
+		case pkgName == "sql":
+			s := basicSchema(strings.ToLower(strings.TrimPrefix(name, "Null")))
+			s["nullable"] = true
+			return s
+		default:
+			if dir, ok := p.imports[pkgName]; ok {
+				if other, err := g.load(dir); err == nil {
+					return g.named(other, name)
+				}
+			}
+			return map[string]any{}
+		}
+	case *ast.Ident:
+		if _, ok := p.types[v.Name]; ok {
+			return g.named(p, v.Name)
+		}
+		return basicSchema(v.Name)
+	}
+	return map[string]any{}
+}
+
+func basicSchema(name string) map[string]any {
+	switch name {
+	case "string":
+		return map[string]any{"type": "string"}
+	case "bool":
+		return map[string]any{"type": "boolean"}
+	case "int", "int16", "int32", "uint", "uint16", "uint32":
+		return map[string]any{"type": "integer"}
+	case "int64", "uint64":
+		return map[string]any{"type": "integer", "format": "int64"}
+	case "float32", "float64":
+		return map[string]any{"type": "number"}
+	case "time":
+		return map[string]any{"type": "string", "format": "date-time"}
+	}
+	return map[string]any{}
+}
+
+// named returns a $ref to type name of package p, generating its component schema on first use.
+func (g *generator) named(p *pkg, name string) map[string]any {
+	key := name
+	if p != g.models {
+		key = p.name + "." + name
+	}
+	ref := map[string]any{"$ref": "#/components/schemas/" + key}
+	if _, done := g.schemas[key]; done {
+		return ref
+	}
+	spec := p.types[name]
+	if spec == nil {
+		return map[string]any{}
+	}
+	g.schemas[key] = map[string]any{} // placeholder, so recursive types terminate
+	s := g.schemaFor(p, spec.Type)
+	if vals, ok := p.enums[name]; ok {
+		s["enum"] = vals
+	}
+	if spec.Doc != nil {
+		s["description"] = strings.TrimSpace(spec.Doc.Text())
+	} else if spec.Comment != nil {
+		s["description"] = strings.TrimSpace(spec.Comment.Text())
+	}
+	g.schemas[key] = s
+	return ref
+}
+
+// structSchema maps exported fields by their json tag; embedded structs are flattened.
+func (g *generator) structSchema(p *pkg, st *ast.StructType) map[string]any {
+	props := map[string]any{}
+	for _, f := range st.Fields.List {
+		tag := ""
+		if f.Tag != nil {
+			raw, _ := strconv.Unquote(f.Tag.Value)
+			tag = reflectTag(raw, "json")
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if name == "-" {
+			continue
+		}
+		if len(f.Names) == 0 {
+			// Embedded: inline its properties
+			embedded := g.schemaFor(p, f.Type)
+			if ref, ok := embedded["$ref"].(string); ok {
+				if s, ok := g.schemas[strings.TrimPrefix(ref, "#/components/schemas/")].(map[string]any); ok {
+					if inner, ok := s["properties"].(map[string]any); ok {
+						for k, v := range inner {
+							props[k] = v
+						}
+					}
+				}
+			}
+			continue
+		}
+		for _, n := range f.Names {
+			if !n.IsExported() {
+				continue
+			}
+			key := name
+			if key == "" {
+				key = n.Name
+			}
+			s := g.schemaFor(p, f.Type)
+			if f.Comment != nil {
+				if _, isRef := s["$ref"]; !isRef {
+					s["description"] = strings.TrimSpace(f.Comment.Text())
+				}
+			}
+			props[key] = s
+		}
+	}
+	return map[string]any{"type": "object", "properties": props}
+}
+
+// reflectTag is reflect.StructTag.Get without needing a reflect.StructTag value.
+func reflectTag(tag, key string) string {
+	for tag != "" {
+		tag = strings.TrimLeft(tag, " ")
+		name, rest, ok := strings.Cut(tag, ":\"")
+		if !ok {
+			return ""
+		}
+		end := strings.Index(rest, "\"")
+		if end < 0 {
+			return ""
+		}
+		if name == key {
+			return rest[:end]
+		}
+		tag = rest[end+1:]
+	}
+	return ""
+}
+
+func paramSchema(name, getter string) map[string]any {
+	switch {
+	case getter == "QueryInt" || name == "id" || strings.HasSuffix(name, "_id") || name == "limit" || name == "offset":
+		return map[string]any{"type": "integer"}
+	case getter == "QueryBool":
+		return map[string]any{"type": "boolean"}
+	case getter == "QueryFloat":
+		return map[string]any{"type": "number"}
+	case strings.HasSuffix(name, "date") || name == "from" || name == "to":
+		return map[string]any{"type": "string", "format": "date"}
+	}
+	return map[string]any{"type": "string"}
+}
+
+var statusCodes = map[string]string{
+	"StatusOK": "200", "StatusCreated": "201", "StatusAccepted": "202", "StatusNoContent": "204",
+	"StatusMultiStatus": "207",
+}
+
+// statusCode reads fiber.StatusX or a literal code; anything else is treated as an error status.
+func statusCode(e ast.Expr) string {
+	if lit, ok := e.(*ast.BasicLit); ok {
+		return lit.Value
+	}
+	if _, name := selector(e); name != "" {
+		if code, ok := statusCodes[name]; ok {
+			return code
+		}
+	}
+	return "default"
+}
+
+func tag(path string) string {
+	seg, _, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	return seg
+}
+
+// humanize turns "registerVolunteer" into "Register volunteer" and "GetVolunteerByID" into
+// "Get volunteer by ID".
+func humanize(name string) string {
+	var words []string
+	runes := []rune(name)
+	start := 0
+	for i := 1; i <= len(runes); i++ {
+		// A word ends before an upper-case letter that follows a lower-case one, or that starts
+		// a new word after an acronym ("IDFoo" -> "ID", "Foo").
+		if i == len(runes) || unicode.IsUpper(runes[i]) &&
+			(unicode.IsLower(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+	for i, w := range words {
+		acronym := len(w) > 1 && strings.ToUpper(w) == w
+		switch {
+		case i == 0:
+			words[i] = strings.ToUpper(w[:1]) + w[1:]
+		case !acronym:
+			words[i] = strings.ToLower(w)
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+func typeName(e ast.Expr) string {
+	if recv, name := selector(e); name != "" {
+		return recv + "." + name
+	}
+	return ident(e)
+}
+
+func ident(e ast.Expr) string {
+	if id, ok := e.(*ast.Ident); ok {
+		return id.Name
+	}
+	return ""
+}
+
+func selector(e ast.Expr) (recv, name string) {
+	if sel, ok := e.(*ast.SelectorExpr); ok {
+		return ident(sel.X), sel.Sel.Name
+	}
+	return "", ""
+}
+
+func stringLit(e ast.Expr) string {
+	if lit, ok := e.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+		s, _ := strconv.Unquote(lit.Value)
+		return s
+	}
+	return ""
+}
@@ -0,0 +1,218 @@
+// Command seed fills a development database with a realistic demo event: committees, volunteers,
+// assignments across shifts, attendance for the shifts that have already happened, announcements and
+// questions. Each run creates a new event, so it can be repeated without clashing with earlier data.
+//
+//	DATABASE_URL=postgres://... go run ./cmd/seed -volunteers 200 -days 3
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/joho/godotenv"
+
+	"Seva-app-backend/db"
+	hAuth "Seva-app-backend/handlers/auth"
+)
+
+var (
+	committeeNames = []string{"Registration", "Food Service", "Crowd Control", "Medical", "Parking", "Stage", "Cleaning", "Water", "Helpdesk", "Transport"}
+	firstNames     = []string{"Arjun", "Meera", "Rahul", "Anjali", "Vishnu", "Lakshmi", "Karthik", "Divya", "Nikhil", "Sneha", "Hari", "Gayathri", "Rohan", "Priya", "Aditya", "Kavya"}
+	lastNames      = []string{"Nair", "Menon", "Pillai", "Kumar", "Iyer", "Sharma", "Krishnan", "Das", "Reddy", "Varma"}
+	depts          = []string{"CSE", "ECE", "EEE", "Mechanical", "Civil", "Chemical", "Biotech"}
+	shifts         = []struct {
+		name       string
+		start, end int // local hours
+	}{{"Morning Shift", 7, 12}, {"Afternoon Shift", 12, 17}, {"Evening Shift", 17, 22}}
+	questionTexts = []string{
+		"Where do we collect our volunteer badges?",
+		"Is lunch provided for the afternoon shift?",
+		"Who do I report to if my committee lead is not around?",
+		"Can I swap my shift with another volunteer?",
+		"Where is the nearest first-aid point to the main stage?",
+		"Is there parking for volunteers' two-wheelers?",
+	}
+)
+
+func main() {
+	_ = godotenv.Load()
+
+	eventName := flag.String("event", "", "event name (default \"Demo Event <timestamp>\")")
+	tz := flag.String("tz", "Asia/Kolkata", "event time zone")
+	days := flag.Int("days", 2, "event length in days; the event ends today so earlier days get attendance")
+	nCommittees := flag.Int("committees", 6, "number of committees (max "+strconv.Itoa(len(committeeNames))+")")
+	nVolunteers := flag.Int("volunteers", 60, "number of volunteers")
+	attendanceRate := flag.Float64("attendance", 0.8, "fraction of past shifts that have a check-in")
+	nAnnouncements := flag.Int("announcements", 8, "number of announcements")
+	nQuestions := flag.Int("questions", 12, "number of questions")
+	password := flag.String("password", "password123", "password for every seeded volunteer")
+	seed := flag.Int64("seed", time.Now().UnixNano(), "random seed")
+	flag.Parse()
+
+	if *days < 1 || *nCommittees < 1 || *nCommittees > len(committeeNames) || *nVolunteers < 1 {
+		log.Fatal("days, committees and volunteers must be positive (committees at most " + strconv.Itoa(len(committeeNames)) + ")")
+	}
+	loc, err := time.LoadLocation(*tz)
+	if err != nil {
+		log.Fatalf("invalid -tz: %v", err)
+	}
+	runID := strconv.FormatInt(time.Now().Unix(), 36)
+	if *eventName == "" {
+		*eventName = "Demo Event " + runID
+	}
+	rng := rand.New(rand.NewSource(*seed))
+
+	hash, err := hAuth.BcryptHash(*password)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx := context.Background()
+	pool := db.MustPool()
+	defer pool.Close()
+	if db.AutoMigrateEnabled() {
+		if err := db.Migrate(ctx, pool); err != nil {
+			log.Fatalf("Database migration failed: %v", err)
+		}
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer tx.Rollback(ctx)
+
+	now := time.Now().In(loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	firstDay := today.AddDate(0, 0, -(*days - 1))
+
+	var eventID int64
+	err = tx.QueryRow(ctx, `
+		INSERT INTO events (name, venue, tz, starts_at, ends_at)
+		VALUES ($1, 'Amritapuri', $2, $3, $4)
+		RETURNING id
+	`, *eventName, *tz, firstDay.Add(7*time.Hour), today.Add(22*time.Hour)).Scan(&eventID)
+	must(err)
+
+	var adminID *int64
+	if err := tx.QueryRow(ctx, `SELECT id FROM faculty WHERE role = 'admin' ORDER BY id LIMIT 1`).Scan(&adminID); err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		log.Fatal(err)
+	}
+
+	committeeIDs := make([]int64, 0, *nCommittees)
+	for _, name := range committeeNames[:*nCommittees] {
+		var id int64
+		must(tx.QueryRow(ctx, `INSERT INTO committees (event_id, name, description) VALUES ($1, $2, $3) RETURNING id`,
+			eventID, name, name+" committee for "+*eventName).Scan(&id))
+		committeeIDs = append(committeeIDs, id)
+	}
+
+	type seeded struct{ volunteerID, assignmentID int64 }
+	var volunteers []seeded
+	var attendanceRows int
+	for n := 1; n <= *nVolunteers; n++ {
+		first, last := firstNames[rng.Intn(len(firstNames))], lastNames[rng.Intn(len(lastNames))]
+		var volunteerID int64
+		must(tx.QueryRow(ctx, `
+			INSERT INTO volunteers (name, email, phone, dept, college_id, password_hash)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING id
+		`, first+" "+last,
+			fmt.Sprintf("demo.%s.%d@example.com", runID, n),
+			fmt.Sprintf("+9190000%05d", rng.Intn(100000)),
+			depts[rng.Intn(len(depts))],
+			fmt.Sprintf("DEMO-%s-%04d", runID, n),
+			hash,
+		).Scan(&volunteerID))
+
+		// One assignment per volunteer: a committee, a day and a shift
+		day := firstDay.AddDate(0, 0, rng.Intn(*days))
+		sh := shifts[rng.Intn(len(shifts))]
+		start, end := day.Add(time.Duration(sh.start)*time.Hour), day.Add(time.Duration(sh.end)*time.Hour)
+		role, status := "volunteer", "assigned"
+		switch r := rng.Float64(); {
+		case r < 0.1:
+			role = "lead"
+		case r < 0.2:
+			role = "support"
+		}
+		if rng.Float64() < 0.1 {
+			status = "standby"
+		}
+		var assignmentID int64
+		must(tx.QueryRow(ctx, `
+			INSERT INTO volunteer_assignments (event_id, committee_id, volunteer_id, role, status, reporting_time, shift, start_time, end_time)
+			VALUES ($1, $2, $3, $4::assignment_role, $5::assignment_status, $6, $7, $8, $9)
+			RETURNING id
+		`, eventID, committeeIDs[rng.Intn(len(committeeIDs))], volunteerID, role, status,
+			start.Add(-15*time.Minute), sh.name, start, end).Scan(&assignmentID))
+		volunteers = append(volunteers, seeded{volunteerID, assignmentID})
+
+		// Attendance only for shifts that have started
+		if start.Before(now) && rng.Float64() < *attendanceRate {
+			checkIn := start.Add(time.Duration(rng.Intn(30)-10) * time.Minute)
+			var checkOut *time.Time
+			if end.Before(now) {
+				t := end.Add(time.Duration(rng.Intn(30)-5) * time.Minute)
+				checkOut = &t
+			}
+			_, err := tx.Exec(ctx, `INSERT INTO attendance (assignment_id, check_in_time, check_out_time) VALUES ($1, $2, $3)`,
+				assignmentID, checkIn, checkOut)
+			must(err)
+			attendanceRows++
+		}
+	}
+
+	priorities := []string{"low", "normal", "normal", "high", "urgent"}
+	for n := 1; n <= *nAnnouncements; n++ {
+		var committeeID *int64
+		if rng.Float64() < 0.5 {
+			committeeID = &committeeIDs[rng.Intn(len(committeeIDs))]
+		}
+		_, err := tx.Exec(ctx, `
+			INSERT INTO announcements (event_id, committee_id, title, body, priority, created_by, created_at, notified_at)
+			VALUES ($1, $2, $3, $4, $5::announcement_priority, $6, $7, $7)
+		`, eventID, committeeID,
+			fmt.Sprintf("Update #%d", n),
+			"Please gather at your committee point ten minutes before your shift. Carry your badge and a water bottle.",
+			priorities[rng.Intn(len(priorities))], adminID,
+			now.Add(-time.Duration(rng.Intn(*days*24*60))*time.Minute))
+		must(err)
+	}
+
+	for n := 0; n < *nQuestions; n++ {
+		v := volunteers[rng.Intn(len(volunteers))]
+		var answer *string
+		var answeredBy *int64
+		var answeredAt *time.Time
+		if adminID != nil && rng.Float64() < 0.5 {
+			a, t := "Please check with the helpdesk near the main gate.", now
+			answer, answeredBy, answeredAt = &a, adminID, &t
+		}
+		_, err := tx.Exec(ctx, `
+			INSERT INTO questions (volunteer_id, question_text, event_id, answered_by, answer_text, answered_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+		`, v.volunteerID, questionTexts[rng.Intn(len(questionTexts))], eventID, answeredBy, answer, answeredAt)
+		must(err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Seeded event %q (id %d): %d committees, %d volunteers/assignments, %d attendance rows, %d announcements, %d questions",
+		*eventName, eventID, len(committeeIDs), len(volunteers), attendanceRows, *nAnnouncements, *nQuestions)
+	log.Printf("Volunteer logins: demo.%s.<n>@example.com / %s", runID, *password)
+}
+
+func must(err error) {
+	if err != nil {
+		log.Fatal(err)
+	}
+}
@@ -2,44 +2,138 @@ package db
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-// MustPool creates and returns a new pgxpool.Pool, or panics if an error occurs.
+// MustPool connects with NewPool, retrying a database that is not reachable yet (see ConnectWithRetry),
+// and exits the process if it still fails.
 func MustPool() *pgxpool.Pool {
+	pool, err := ConnectWithRetry(context.Background(), envInt("DB_CONNECT_ATTEMPTS", 5, 1))
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Println("Successfully connected to PostgreSQL database!")
+	return pool
+}
+
+// ConfigError is a problem with the pool configuration itself; retrying cannot fix it.
+type ConfigError struct{ Err error }
+
+func (e *ConfigError) Error() string { return e.Err.Error() }
+func (e *ConfigError) Unwrap() error { return e.Err }
+
+// NewPool builds a pool from DATABASE_URL and the DB_* sizing variables and pings it.
+// Configuration problems are returned as *ConfigError; anything else is a connection failure.
+func NewPool(ctx context.Context) (*pgxpool.Pool, error) {
 	connStr := os.Getenv("DATABASE_URL")
 	if connStr == "" {
-		log.Fatal("DATABASE_URL environment variable is not set")
+		return nil, &ConfigError{errors.New("DATABASE_URL environment variable is not set")}
 	}
 
 	config, err := pgxpool.ParseConfig(connStr)
 	if err != nil {
-		log.Fatalf("Unable to parse DATABASE_URL: %v", err)
+		return nil, &ConfigError{fmt.Errorf("unable to parse DATABASE_URL: %w", err)}
 	}
 
-	// Optional: Configure connection pool settings
-	config.MaxConns = 10
-	config.MinConns = 2
-	config.MaxConnLifetime = time.Hour
-	config.MaxConnIdleTime = 30 * time.Minute
+	// Pool sizing: DB_MAX_CONNS, DB_MIN_CONNS (integers) and DB_MAX_CONN_LIFETIME,
+	// DB_MAX_CONN_IDLE_TIME (Go durations such as "1h" or "90s"); invalid values keep the default.
+	config.MaxConns = int32(envInt("DB_MAX_CONNS", 10, 1))
+	config.MinConns = int32(envInt("DB_MIN_CONNS", 2, 0))
+	if config.MinConns > config.MaxConns {
+		log.Printf("DB_MIN_CONNS (%d) exceeds DB_MAX_CONNS (%d); using %d", config.MinConns, config.MaxConns, config.MaxConns)
+		config.MinConns = config.MaxConns
+	}
+	config.MaxConnLifetime = envDuration("DB_MAX_CONN_LIFETIME", time.Hour)
+	config.MaxConnIdleTime = envDuration("DB_MAX_CONN_IDLE_TIME", 30*time.Minute)
+	log.Printf("DB pool: max_conns=%d min_conns=%d max_conn_lifetime=%s max_conn_idle_time=%s",
+		config.MaxConns, config.MinConns, config.MaxConnLifetime, config.MaxConnIdleTime)
 
-	pool, err := pgxpool.NewWithConfig(context.Background(), config)
+	pool, err := pgxpool.NewWithConfig(ctx, config)
 	if err != nil {
-		log.Fatalf("Unable to create connection pool: %v", err)
+		return nil, &ConfigError{fmt.Errorf("unable to create connection pool: %w", err)}
 	}
 
 	// Ping the database to verify the connection
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	pingCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
-	if err = pool.Ping(ctx); err != nil {
+	if err := pool.Ping(pingCtx); err != nil {
 		pool.Close()
-		log.Fatalf("Could not ping database: %v", err)
+		return nil, fmt.Errorf("could not ping database: %w", err)
 	}
+	return pool, nil
+}
 
-	log.Println("Successfully connected to PostgreSQL database!")
-	return pool
+// ConnectWithRetry calls NewPool up to attempts times, backing off 1s, 2s, 4s, ... (capped at 30s)
+// between connection failures so a database that is still starting doesn't crash-loop the container.
+// Configuration errors are returned immediately.
+func ConnectWithRetry(ctx context.Context, attempts int) (*pgxpool.Pool, error) {
+	backoff := time.Second
+	for attempt := 1; ; attempt++ {
+		pool, err := NewPool(ctx)
+		if err == nil {
+			return pool, nil
+		}
+		var cfgErr *ConfigError
+		if errors.As(err, &cfgErr) || attempt >= attempts {
+			return nil, err
+		}
+		log.Printf("Database not reachable (attempt %d/%d): %v; retrying in %s", attempt, attempts, err, backoff)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, 30*time.Second)
+	}
+}
+
+// envInt reads a whole number >= min from the environment variable name, or returns def.
+func envInt(name string, def, min int) int {
+	v := os.Getenv(name)
+	if v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < min {
+		log.Printf("Ignoring invalid %s=%q (want an integer >= %d); using %d", name, v, min, def)
+		return def
+	}
+	return n
+}
+
+// envDuration reads a positive Go duration from the environment variable name, or returns def.
+func envDuration(name string, def time.Duration) time.Duration {
+	v := os.Getenv(name)
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		log.Printf("Ignoring invalid %s=%q (want a positive duration such as 30m); using %s", name, v, def)
+		return def
+	}
+	return d
+}
+
+// queryTimeout bounds the database work of a single request; see WithTimeout. It is read on first use
+// rather than at package init, which runs before main loads .env.
+var (
+	queryTimeout     time.Duration
+	queryTimeoutOnce sync.Once
+)
+
+// WithTimeout derives a context from parent (normally c.Context()) that expires after DB_QUERY_TIMEOUT
+// (default 5s), so a slow query fails the request instead of holding a pool connection indefinitely.
+// Long-running exports and bulk uploads keep the request context instead.
+func WithTimeout(parent context.Context) (context.Context, context.CancelFunc) {
+	queryTimeoutOnce.Do(func() { queryTimeout = envDuration("DB_QUERY_TIMEOUT", 5*time.Second) })
+	return context.WithTimeout(parent, queryTimeout)
 }
@@ -0,0 +1,25 @@
+package db
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// DB_QUERY_TIMEOUT set after the package is initialized (as godotenv.Load in main does) still applies.
+func TestWithTimeoutReadsEnvLazily(t *testing.T) {
+	t.Setenv("DB_QUERY_TIMEOUT", "250ms")
+	queryTimeoutOnce = sync.Once{}
+	t.Cleanup(func() { queryTimeoutOnce = sync.Once{} })
+
+	ctx, cancel := WithTimeout(context.Background())
+	defer cancel()
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		t.Fatal("WithTimeout context has no deadline")
+	}
+	if left := time.Until(deadline); left > 250*time.Millisecond {
+		t.Fatalf("deadline in %s, want at most 250ms", left)
+	}
+}
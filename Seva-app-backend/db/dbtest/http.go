@@ -0,0 +1,48 @@
+package dbtest
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+
+	mw "Seva-app-backend/middleware"
+	"Seva-app-backend/models"
+)
+
+// App returns a Fiber app with the production error handler, for mounting a package's routes under test.
+func App() *fiber.App {
+	return fiber.New(fiber.Config{ErrorHandler: mw.ErrorHandler})
+}
+
+// As stands in for the JWT guard: every request runs with claims for user sub in role.
+func As(sub int64, role models.UserRole) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Locals("claims", &mw.Claims{Sub: sub, Role: role})
+		return c.Next()
+	}
+}
+
+// Pass stands in for role guards the test is not exercising.
+func Pass(c *fiber.Ctx) error { return c.Next() }
+
+// Do sends method path to app, with body as JSON when it is not empty, and returns the status and body.
+func Do(t testing.TB, app *fiber.App, method, path, body string) (int, []byte) {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	}
+	res, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res.StatusCode, b
+}
@@ -0,0 +1,88 @@
+package db
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Postgres SQLSTATE codes the API maps to client-facing responses.
+const (
+	CodeUniqueViolation     = "23505"
+	CodeForeignKeyViolation = "23503"
+	CodeCheckViolation      = "23514"
+)
+
+// Constraint names referenced by handlers. Keep in sync with db/migrations.
+const (
+	ConstraintVolunteersEmail       = "volunteers_email_key"
+	ConstraintVolunteersCollegeID   = "volunteers_college_id_key"
+	ConstraintFacultyEmail          = "faculty_email_key"
+	ConstraintCommitteesEventName   = "committees_event_id_name_key"
+	ConstraintLocationsEventName    = "locations_event_id_name_key"
+	ConstraintAssignmentsUnique     = "volunteer_assignments_event_id_committee_id_volunteer_id_key"
+	ConstraintAttendanceActiveDay   = "ux_attendance_active_assignment_day"
+	ConstraintDepartmentsNameLower  = "ux_departments_name_lower"
+	ConstraintShiftsEventNameLower  = "ux_shifts_event_name_lower"
+	ConstraintCarbonFootprintUnique = "carbon_footprint_event_id_committee_id_metric_date_key"
+	ConstraintAnnouncementTargetVol = "announcement_targets_volunteer_id_fkey"
+)
+
+// constraintMessages maps known constraint names to friendly client-facing messages.
+var constraintMessages = map[string]string{
+	ConstraintVolunteersEmail:       "Email already registered",
+	ConstraintVolunteersCollegeID:   "College ID already registered",
+	ConstraintFacultyEmail:          "Email already registered for a faculty account",
+	ConstraintCommitteesEventName:   "Committee name already exists for this event",
+	ConstraintLocationsEventName:    "Location name already exists for this event",
+	ConstraintAssignmentsUnique:     "Volunteer is already assigned to this committee for this event",
+	ConstraintAttendanceActiveDay:   "Volunteer already has an active check-in for this assignment today",
+	ConstraintDepartmentsNameLower:  "Department already exists",
+	ConstraintShiftsEventNameLower:  "Shift name already exists for this event",
+	ConstraintCarbonFootprintUnique: "Metrics already recorded for this event, committee and date",
+	ConstraintAnnouncementTargetVol: "One or more target volunteers do not exist",
+}
+
+// AsPgError unwraps err into a *pgconn.PgError if it carries one.
+func AsPgError(err error) (*pgconn.PgError, bool) {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr, true
+	}
+	return nil, false
+}
+
+// IsViolation reports whether err is a Postgres error with the given SQLSTATE code.
+// If constraint is non-empty the violated constraint must also match it.
+func IsViolation(err error, code, constraint string) bool {
+	pgErr, ok := AsPgError(err)
+	if !ok || pgErr.Code != code {
+		return false
+	}
+	return constraint == "" || pgErr.ConstraintName == constraint
+}
+
+// IsUniqueViolation reports whether err is a unique violation on constraint ("" matches any).
+func IsUniqueViolation(err error, constraint string) bool {
+	return IsViolation(err, CodeUniqueViolation, constraint)
+}
+
+// IsForeignKeyViolation reports whether err is a foreign key violation on constraint ("" matches any).
+func IsForeignKeyViolation(err error, constraint string) bool {
+	return IsViolation(err, CodeForeignKeyViolation, constraint)
+}
+
+// IsCheckViolation reports whether err is a check constraint violation on constraint ("" matches any).
+func IsCheckViolation(err error, constraint string) bool {
+	return IsViolation(err, CodeCheckViolation, constraint)
+}
+
+// ConstraintMessage returns the friendly message registered for the constraint err violated, if any.
+func ConstraintMessage(err error) (string, bool) {
+	pgErr, ok := AsPgError(err)
+	if !ok || pgErr.ConstraintName == "" {
+		return "", false
+	}
+	msg, ok := constraintMessages[pgErr.ConstraintName]
+	return msg, ok
+}
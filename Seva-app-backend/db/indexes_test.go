@@ -0,0 +1,142 @@
+package db_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"Seva-app-backend/db/dbtest"
+)
+
+// dashboardQuery is the shape of the shifts-without-check-in lookup (see 0003_attendance_dashboard_indexes.sql).
+const dashboardQuery = `
+	SELECT va.id FROM volunteer_assignments va JOIN events e ON e.id = va.event_id
+	WHERE va.event_id = $1 AND va.committee_id = $2 AND (va.start_time AT TIME ZONE e.tz)::date = '2025-09-26'
+	  AND NOT EXISTS (SELECT 1 FROM attendance att WHERE att.assignment_id = va.id
+	                  AND (att.check_in_time AT TIME ZONE e.tz)::date = '2025-09-26')`
+
+// seedDashboard fills 20 events x 10 committees with 40k assignments over three days, half of them checked
+// in, and returns one committee to query.
+func seedDashboard(tb testing.TB, pool *pgxpool.Pool) (eventID, committeeID int64) {
+	tb.Helper()
+	dbtest.Exec(tb, pool, `INSERT INTO events (name) SELECT 'Event ' || g FROM generate_series(1, 20) g`)
+	dbtest.Exec(tb, pool, `INSERT INTO committees (event_id, name) SELECT e.id, 'Committee ' || g FROM events e, generate_series(1, 10) g`)
+	dbtest.Exec(tb, pool, `INSERT INTO volunteers (name) SELECT 'Volunteer ' || g FROM generate_series(1, 20000) g`)
+	dbtest.Exec(tb, pool, `
+		INSERT INTO volunteer_assignments (event_id, committee_id, volunteer_id, start_time)
+		SELECT c.event_id, c.id, v.id, TIMESTAMPTZ '2025-09-25 08:00+00' + (v.id % 3) * INTERVAL '1 day'
+		FROM committees c JOIN volunteers v ON v.id % 100 = c.id % 100`)
+	dbtest.Exec(tb, pool, `
+		INSERT INTO attendance (assignment_id, check_in_time)
+		SELECT id, start_time FROM volunteer_assignments WHERE id % 2 = 0`)
+	dbtest.Exec(tb, pool, `ANALYZE`)
+	if err := pool.QueryRow(context.Background(), `SELECT event_id, id FROM committees ORDER BY id LIMIT 1`).
+		Scan(&eventID, &committeeID); err != nil {
+		tb.Fatal(err)
+	}
+	return eventID, committeeID
+}
+
+// planNode is the part of EXPLAIN (FORMAT JSON) output the test looks at.
+type planNode struct {
+	NodeType     string     `json:"Node Type"`
+	RelationName string     `json:"Relation Name"`
+	IndexName    string     `json:"Index Name"`
+	Plans        []planNode `json:"Plans"`
+}
+
+// explain maps each table in the dashboard query's plan to how it is read: an index name or a node type
+// such as "Seq Scan".
+func explain(t *testing.T, tx pgx.Tx, eventID, committeeID int64) map[string][]string {
+	t.Helper()
+	var raw []byte
+	if err := tx.QueryRow(context.Background(), `EXPLAIN (FORMAT JSON) `+dashboardQuery, eventID, committeeID).Scan(&raw); err != nil {
+		t.Fatal(err)
+	}
+	var plans []struct {
+		Plan planNode `json:"Plan"`
+	}
+	if err := json.Unmarshal(raw, &plans); err != nil {
+		t.Fatal(err)
+	}
+	out := map[string][]string{}
+	var walk func(n planNode)
+	walk = func(n planNode) {
+		if n.RelationName != "" {
+			how := n.NodeType
+			if n.IndexName != "" {
+				how = n.IndexName
+			}
+			out[n.RelationName] = append(out[n.RelationName], how)
+		}
+		for _, child := range n.Plans {
+			walk(child)
+		}
+	}
+	for _, p := range plans {
+		walk(p.Plan)
+	}
+	return out
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// The 0003 indexes turn the dashboard query's sequential scans into index lookups.
+func TestDashboardQueryUsesIndexes(t *testing.T) {
+	pool := dbtest.Migrated(t)
+	eventID, committeeID := seedDashboard(t, pool)
+	ctx := context.Background()
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback(ctx)
+
+	with := explain(t, tx, eventID, committeeID)
+	if !contains(with["volunteer_assignments"], "idx_assignments_event_committee_start") {
+		t.Errorf("volunteer_assignments read via %v, want idx_assignments_event_committee_start", with["volunteer_assignments"])
+	}
+	if !contains(with["attendance"], "idx_attendance_assignment_checkin") {
+		t.Errorf("attendance read via %v, want idx_attendance_assignment_checkin", with["attendance"])
+	}
+
+	// Without them (dropped inside the transaction, which is rolled back) both tables are scanned
+	for _, idx := range []string{"idx_assignments_event_committee_start", "idx_attendance_assignment_checkin",
+		"idx_attendance_open", "ux_attendance_active_assignment_day", "idx_assignments_shift_id"} {
+		if _, err := tx.Exec(ctx, `DROP INDEX IF EXISTS `+idx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	without := explain(t, tx, eventID, committeeID)
+	if !contains(without["attendance"], "Seq Scan") {
+		t.Errorf("without the indexes attendance read via %v, expected a Seq Scan", without["attendance"])
+	}
+	t.Logf("with indexes: %v; without: %v", with, without)
+}
+
+func BenchmarkDashboardQuery(b *testing.B) {
+	pool := dbtest.Migrated(b)
+	eventID, committeeID := seedDashboard(b, pool)
+	ctx := context.Background()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		rows, err := pool.Query(ctx, dashboardQuery, eventID, committeeID)
+		if err != nil {
+			b.Fatal(err)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package db
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// migrationGlob matches the numbered migration files only. The legacy init.mg.up.sql that still lives in
+// migrations/ is not a migration (0001 supersedes it) and must never be applied.
+const migrationGlob = "migrations/[0-9][0-9][0-9][0-9]_*.sql"
+
+//go:embed migrations/[0-9][0-9][0-9][0-9]_*.sql
+var migrationFiles embed.FS
+
+// migrateLockID is the pg_advisory_lock key held while migrating, so replicas starting
+// together don't apply the same migration twice.
+const migrateLockID = 727_001
+
+// AutoMigrateEnabled reports whether pending migrations should run on startup.
+// DB_AUTO_MIGRATE defaults to on; set it to "false" (or "0"/"off") when migrations are applied out of band.
+func AutoMigrateEnabled() bool {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv("DB_AUTO_MIGRATE"))) {
+	case "false", "0", "off", "no":
+		return false
+	}
+	return true
+}
+
+// Migrate applies the embedded migrations/NNNN_*.sql files that are not yet recorded in schema_migrations,
+// in file-name order, each in its own transaction. Files are named NNNN_description.sql; the name
+// without ".sql" is the recorded version, so never rename or edit a file once it has shipped.
+func Migrate(ctx context.Context, pool *pgxpool.Pool) error {
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, `SELECT pg_advisory_lock($1)`, migrateLockID); err != nil {
+		return err
+	}
+	defer conn.Exec(context.Background(), `SELECT pg_advisory_unlock($1)`, migrateLockID)
+
+	if _, err := conn.Exec(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version TEXT PRIMARY KEY,
+			applied_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		)`); err != nil {
+		return err
+	}
+
+	applied := map[string]bool{}
+	rows, err := conn.Query(ctx, `SELECT version FROM schema_migrations`)
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var v string
+		if err := rows.Scan(&v); err != nil {
+			rows.Close()
+			return err
+		}
+		applied[v] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	names, err := fs.Glob(migrationFiles, migrationGlob)
+	if err != nil {
+		return err
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		version := strings.TrimSuffix(strings.TrimPrefix(name, "migrations/"), ".sql")
+		if applied[version] {
+			continue
+		}
+		body, err := migrationFiles.ReadFile(name)
+		if err != nil {
+			return err
+		}
+
+		tx, err := conn.Begin(ctx)
+		if err != nil {
+			return err
+		}
+		// No arguments, so pgx sends the file over the simple protocol and multi-statement scripts work
+		if _, err := tx.Exec(ctx, string(body)); err != nil {
+			tx.Rollback(ctx)
+			return fmt.Errorf("migration %s: %w", version, err)
+		}
+		if _, err := tx.Exec(ctx, `INSERT INTO schema_migrations (version) VALUES ($1)`, version); err != nil {
+			tx.Rollback(ctx)
+			return fmt.Errorf("migration %s: %w", version, err)
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return fmt.Errorf("migration %s: %w", version, err)
+		}
+		log.Printf("Applied migration %s", version)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package db_test
+
+import (
+	"context"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"Seva-app-backend/db"
+	"Seva-app-backend/db/dbtest"
+)
+
+var numberedMigration = regexp.MustCompile(`^\d{4}_.+\.sql$`)
+
+func TestMigrateEmptyDatabase(t *testing.T) {
+	pool := dbtest.NewDatabase(t)
+	ctx := context.Background()
+
+	if err := db.Migrate(ctx, pool); err != nil {
+		t.Fatalf("Migrate on an empty database: %v", err)
+	}
+	// A second run finds everything applied and changes nothing
+	if err := db.Migrate(ctx, pool); err != nil {
+		t.Fatalf("second Migrate: %v", err)
+	}
+
+	entries, err := os.ReadDir("migrations")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want []string
+	for _, e := range entries {
+		if numberedMigration.MatchString(e.Name()) {
+			want = append(want, strings.TrimSuffix(e.Name(), ".sql"))
+		}
+	}
+	sort.Strings(want)
+
+	rows, err := pool.Query(ctx, `SELECT version FROM schema_migrations ORDER BY version`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	var got []string
+	for rows.Next() {
+		var v string
+		if err := rows.Scan(&v); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, v)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("applied migrations = %v, want %v", got, want)
+	}
+
+	// The schema the handlers rely on is in place
+	var n int
+	if err := pool.QueryRow(ctx, `SELECT COUNT(*) FROM volunteer_assignments`).Scan(&n); err != nil {
+		t.Fatalf("volunteer_assignments not created: %v", err)
+	}
+
+	// Migrations must not leave a usable account; the first admin comes from BootstrapAdmin
+	if err := pool.QueryRow(ctx, `SELECT COUNT(*) FROM faculty WHERE role = 'admin' OR password_hash IS NOT NULL`).
+		Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	if n != 0 {
+		t.Fatalf("fresh database has %d faculty accounts that can log in or are admins, want 0", n)
+	}
+}
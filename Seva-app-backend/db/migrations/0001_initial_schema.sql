@@ -0,0 +1,354 @@
+-- Initial schema. Every statement is idempotent so this also applies cleanly to databases that were
+-- set up by hand from the old DATA.SQL before the migrations runner existed.
+-- Schema changes from here on go in new, higher-numbered files in this directory.
+
+-- Create ENUM types first
+DO $$ BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'user_role') THEN
+        CREATE TYPE user_role AS ENUM ('admin', 'faculty', 'volunteer');
+    END IF;
+    IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'announcement_priority') THEN
+        CREATE TYPE announcement_priority AS ENUM ('low', 'normal', 'high', 'urgent');
+    END IF;
+    IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'location_type') THEN
+        CREATE TYPE location_type AS ENUM ('stage', 'dining', 'helpdesk', 'parking', 'water', 'toilet', 'poi');
+    END IF;
+    IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'assignment_role') THEN
+        CREATE TYPE assignment_role AS ENUM ('volunteer', 'lead', 'support');
+    END IF;
+    IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'assignment_status') THEN
+        CREATE TYPE assignment_status AS ENUM ('assigned', 'standby', 'cancelled');
+    END IF;
+    IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'question_category') THEN
+        CREATE TYPE question_category AS ENUM ('general', 'logistics', 'food', 'medical', 'accommodation', 'transport', 'other');
+    END IF;
+END $$;
+
+-- Table: events
+CREATE TABLE IF NOT EXISTS events (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE,
+    venue TEXT,
+    tz TEXT NOT NULL DEFAULT 'UTC', -- Default to UTC if not specified
+    starts_at TIMESTAMP WITH TIME ZONE,
+    ends_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    require_checkin_photo BOOLEAN NOT NULL DEFAULT FALSE -- Volunteers must attach a selfie to check in
+);
+-- Upgrade path for databases created before check-in photos existed
+ALTER TABLE events ADD COLUMN IF NOT EXISTS require_checkin_photo BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Table: faculty
+CREATE TABLE IF NOT EXISTS faculty (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    email TEXT UNIQUE, -- Email can be null but if present, must be unique
+    phone TEXT,
+    department TEXT,
+    role user_role NOT NULL DEFAULT 'faculty',
+    password_hash TEXT, -- Nullable if account is pre-created without password
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW() -- Added for consistency
+);
+
+-- Table: volunteers
+CREATE TABLE IF NOT EXISTS volunteers (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    email TEXT UNIQUE, -- Email can be null but if present, must be unique
+    phone TEXT,
+    dept TEXT,
+    college_id TEXT UNIQUE, -- College ID can be null but if present, must be unique
+    password_hash TEXT, -- Nullable if account is pre-created without password
+    role user_role NOT NULL DEFAULT 'volunteer',
+    photo_url TEXT, -- Optional avatar URL (http/https)
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    sms_opt_out BOOLEAN NOT NULL DEFAULT FALSE -- Volunteer asked not to receive SMS/WhatsApp messages
+);
+-- Upgrade path for databases created before photo_url existed
+ALTER TABLE volunteers ADD COLUMN IF NOT EXISTS photo_url TEXT;
+-- Upgrade path for databases created before SMS opt-out existed
+ALTER TABLE volunteers ADD COLUMN IF NOT EXISTS sms_opt_out BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Table: committees
+CREATE TABLE IF NOT EXISTS committees (
+    id BIGSERIAL PRIMARY KEY,
+    event_id BIGINT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    description TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE(event_id, name) -- Committee names must be unique within an event
+);
+
+-- Table: announcements
+CREATE TABLE IF NOT EXISTS announcements (
+    id BIGSERIAL PRIMARY KEY,
+    event_id BIGINT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    committee_id BIGINT REFERENCES committees(id) ON DELETE SET NULL, -- Committee is optional
+    title TEXT NOT NULL,
+    body TEXT NOT NULL,
+    priority announcement_priority NOT NULL DEFAULT 'normal',
+    created_by BIGINT REFERENCES faculty(id) ON DELETE SET NULL, -- Creator faculty member
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP WITH TIME ZONE,
+    publish_at TIMESTAMP WITH TIME ZONE -- Hidden from volunteers until this time; NULL = publish immediately
+);
+-- Upgrade path for databases created before publish_at existed
+ALTER TABLE announcements ADD COLUMN IF NOT EXISTS publish_at TIMESTAMP WITH TIME ZONE;
+-- Optional narrowing: only volunteers holding this assignment role in the event/committee
+ALTER TABLE announcements ADD COLUMN IF NOT EXISTS target_role assignment_role;
+-- Full-text search over title (weight A) and body (weight B), used by GET /announcements?q=
+ALTER TABLE announcements ADD COLUMN IF NOT EXISTS search_tsv tsvector
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+        setweight(to_tsvector('english', coalesce(body, '')), 'B')
+    ) STORED;
+CREATE INDEX IF NOT EXISTS idx_announcements_search_tsv ON announcements USING GIN (search_tsv);
+
+-- Table: locations
+CREATE TABLE IF NOT EXISTS locations (
+    id BIGSERIAL PRIMARY KEY,
+    event_id BIGINT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    type location_type NOT NULL,
+    description TEXT,
+    lat DOUBLE PRECISION NOT NULL,
+    lng DOUBLE PRECISION NOT NULL,
+    UNIQUE(event_id, name) -- Location names must be unique within an event
+);
+
+-- Table: volunteer_assignments
+CREATE TABLE IF NOT EXISTS volunteer_assignments (
+    id BIGSERIAL PRIMARY KEY,
+    event_id BIGINT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    committee_id BIGINT NOT NULL REFERENCES committees(id) ON DELETE CASCADE,
+    volunteer_id BIGINT NOT NULL REFERENCES volunteers(id) ON DELETE CASCADE,
+    role assignment_role NOT NULL DEFAULT 'volunteer',
+    status assignment_status NOT NULL DEFAULT 'assigned',
+    reporting_time TIMESTAMP WITH TIME ZONE,
+    shift TEXT, -- e.g., "Morning Shift", "Setup Crew"
+    start_time TIMESTAMP WITH TIME ZONE,
+    end_time TIMESTAMP WITH TIME ZONE,
+    notes TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    reminder_sent_at TIMESTAMP WITH TIME ZONE, -- Set once the pre-shift reminder went out; cleared when the times change
+    UNIQUE(event_id, committee_id, volunteer_id) -- A volunteer can only have one assignment per committee per event
+);
+-- Upgrade path for databases created before shift reminders existed
+ALTER TABLE volunteer_assignments ADD COLUMN IF NOT EXISTS reminder_sent_at TIMESTAMP WITH TIME ZONE;
+CREATE INDEX IF NOT EXISTS idx_assignments_reminder_due ON volunteer_assignments (COALESCE(reporting_time, start_time)) WHERE reminder_sent_at IS NULL;
+
+-- Table: attendance
+CREATE TABLE IF NOT EXISTS attendance (
+    id BIGSERIAL PRIMARY KEY,
+    assignment_id BIGINT NOT NULL REFERENCES volunteer_assignments(id) ON DELETE CASCADE,
+    check_in_time TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    check_out_time TIMESTAMP WITH TIME ZONE, -- Null if still checked in
+    lat DOUBLE PRECISION,
+    lng DOUBLE PRECISION,
+    checked_in_by BIGINT REFERENCES faculty(id) ON DELETE SET NULL,  -- Set when faculty records the check-in on the volunteer's behalf
+    checked_out_by BIGINT REFERENCES faculty(id) ON DELETE SET NULL  -- Set when faculty records the check-out
+);
+-- Upgrade path for databases created before manual attendance existed
+ALTER TABLE attendance ADD COLUMN IF NOT EXISTS checked_in_by BIGINT REFERENCES faculty(id) ON DELETE SET NULL;
+ALTER TABLE attendance ADD COLUMN IF NOT EXISTS checked_out_by BIGINT REFERENCES faculty(id) ON DELETE SET NULL;
+
+-- Table: attendance_photos (optional check-in selfie, one per attendance row)
+CREATE TABLE IF NOT EXISTS attendance_photos (
+    attendance_id BIGINT PRIMARY KEY REFERENCES attendance(id) ON DELETE CASCADE,
+    content_type TEXT NOT NULL,
+    data BYTEA NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Add a partial unique index to prevent multiple active check-ins for the same assignment on the same day.
+-- We cast to 'timestamp without time zone' in 'UTC' to make the expression IMMUTABLE before taking the date part.
+CREATE UNIQUE INDEX IF NOT EXISTS ux_attendance_active_assignment_day
+ON attendance (assignment_id, ((check_in_time AT TIME ZONE 'UTC')::date))
+WHERE check_out_time IS NULL;
+
+-- Supports the live on-site count (open check-ins per assignment)
+CREATE INDEX IF NOT EXISTS idx_attendance_open ON attendance (assignment_id) WHERE check_out_time IS NULL;
+
+
+-- Table: carbon_footprint
+CREATE TABLE IF NOT EXISTS carbon_footprint (
+    id BIGSERIAL PRIMARY KEY,
+    event_id BIGINT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    committee_id BIGINT REFERENCES committees(id) ON DELETE SET NULL, -- Committee is optional
+    metric_date DATE NOT NULL,
+    waste_bags INT NOT NULL DEFAULT 0 CHECK (waste_bags >= 0),
+    plastic_kg DOUBLE PRECISION NOT NULL DEFAULT 0.0 CHECK (plastic_kg >= 0),
+    volunteers_count INT NOT NULL DEFAULT 0 CHECK (volunteers_count >= 0),
+    notes TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE(event_id, committee_id, metric_date)
+);
+
+-- Table: auth_sessions (for Faculty/Admin refresh tokens)
+CREATE TABLE IF NOT EXISTS auth_sessions (
+    id BIGSERIAL PRIMARY KEY,
+    faculty_id BIGINT NOT NULL REFERENCES faculty(id) ON DELETE CASCADE,
+    refresh_token_hash TEXT NOT NULL UNIQUE, -- Hashed refresh token
+    user_agent TEXT,
+    ip TEXT,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE, -- Set when token is explicitly revoked or rotated
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Table: password_resets (single-use tokens for the email password-reset flow; only hashes are stored)
+CREATE TABLE IF NOT EXISTS password_resets (
+    id BIGSERIAL PRIMARY KEY,
+    user_type TEXT NOT NULL CHECK (user_type IN ('faculty', 'volunteer')),
+    user_id BIGINT NOT NULL,
+    token_hash TEXT NOT NULL UNIQUE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Table: api_keys
+CREATE TABLE IF NOT EXISTS api_keys (
+    id BIGSERIAL PRIMARY KEY,
+    label TEXT NOT NULL,
+    role user_role NOT NULL,
+    key_hash BYTEA NOT NULL UNIQUE, -- Hashed API key
+    owner_faculty_id BIGINT REFERENCES faculty(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    revoked_at TIMESTAMP WITH TIME ZONE -- Set if key is revoked
+);
+
+-- Table: audit_logs
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id BIGSERIAL PRIMARY KEY,
+    actor_type TEXT NOT NULL, -- e.g., 'admin', 'faculty', 'volunteer', 'api_key'
+    actor_id TEXT, -- ID of the actor (e.g., faculty_id, volunteer_id, api_key_id). Stored as TEXT due to potential varied source tables.
+    event_id BIGINT REFERENCES events(id) ON DELETE SET NULL, -- Optional event context
+    entity_table TEXT NOT NULL, -- Table where the change occurred (e.g., 'volunteers', 'announcements')
+    entity_id TEXT NOT NULL, -- ID of the entity that was changed (e.g., '1', 'UUID-XYZ'). Stored as TEXT.
+    action TEXT NOT NULL, -- e.g., 'create', 'update', 'delete', 'login', 'checkin'
+    diff JSONB, -- JSONB to store a diff of the changes (e.g., old vs new values)
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Table: questions (May I Help You)
+CREATE TABLE IF NOT EXISTS questions (
+    id BIGSERIAL PRIMARY KEY,
+    volunteer_id BIGINT REFERENCES volunteers(id) ON DELETE SET NULL, -- Nullable if anonymous questions are allowed
+    question_text TEXT NOT NULL,
+    asked_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    event_id BIGINT REFERENCES events(id) ON DELETE SET NULL, -- Optional event context for the question
+    committee_id BIGINT REFERENCES committees(id) ON DELETE SET NULL, -- Optional committee context for the question
+    answered_by BIGINT REFERENCES faculty(id) ON DELETE SET NULL, -- The faculty member who answered
+    answer_text TEXT, -- Null if not yet answered
+    answered_at TIMESTAMP WITH TIME ZONE, -- Null if not yet answered
+    category question_category NOT NULL DEFAULT 'general', -- Topic used by admins to triage
+    assigned_to BIGINT REFERENCES faculty(id) ON DELETE SET NULL, -- Faculty member the question is routed to
+    assigned_at TIMESTAMP WITH TIME ZONE
+);
+-- Upgrade path for databases created before category existed
+ALTER TABLE questions ADD COLUMN IF NOT EXISTS category question_category NOT NULL DEFAULT 'general';
+-- Upgrade path for databases created before question assignment existed
+ALTER TABLE questions ADD COLUMN IF NOT EXISTS assigned_to BIGINT REFERENCES faculty(id) ON DELETE SET NULL;
+ALTER TABLE questions ADD COLUMN IF NOT EXISTS assigned_at TIMESTAMP WITH TIME ZONE;
+CREATE INDEX IF NOT EXISTS idx_questions_assigned_to ON questions (assigned_to) WHERE answer_text IS NULL;
+-- Table: question_votes (volunteers upvoting a question they also have)
+CREATE TABLE IF NOT EXISTS question_votes (
+    question_id BIGINT NOT NULL REFERENCES questions(id) ON DELETE CASCADE,
+    volunteer_id BIGINT NOT NULL REFERENCES volunteers(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (question_id, volunteer_id)
+);
+
+-- Table: departments (canonical values for volunteers.dept / faculty.department)
+CREATE TABLE IF NOT EXISTS departments (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+CREATE UNIQUE INDEX IF NOT EXISTS ux_departments_name_lower ON departments (lower(name));
+
+-- Seed departments from the free-text values already in use (idempotent).
+-- Review and merge near-duplicates (e.g. 'CSE' vs 'cse') before enabling DEPARTMENT_STRICT.
+INSERT INTO departments (name)
+SELECT DISTINCT ON (lower(d)) d
+FROM (
+    SELECT trim(dept) AS d FROM volunteers WHERE dept IS NOT NULL AND trim(dept) <> ''
+    UNION ALL
+    SELECT trim(department) FROM faculty WHERE department IS NOT NULL AND trim(department) <> ''
+) src
+ORDER BY lower(d), d
+ON CONFLICT DO NOTHING;
+
+-- Table: announcement_targets (explicit volunteer recipients of an announcement)
+CREATE TABLE IF NOT EXISTS announcement_targets (
+    announcement_id BIGINT NOT NULL REFERENCES announcements(id) ON DELETE CASCADE,
+    volunteer_id BIGINT NOT NULL REFERENCES volunteers(id) ON DELETE CASCADE,
+    PRIMARY KEY (announcement_id, volunteer_id)
+);
+CREATE INDEX IF NOT EXISTS idx_announcement_targets_volunteer ON announcement_targets (volunteer_id);
+
+-- Table: announcement_acks (volunteers who have seen an announcement)
+CREATE TABLE IF NOT EXISTS announcement_acks (
+    announcement_id BIGINT NOT NULL REFERENCES announcements(id) ON DELETE CASCADE,
+    volunteer_id BIGINT NOT NULL REFERENCES volunteers(id) ON DELETE CASCADE,
+    acked_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (announcement_id, volunteer_id)
+);
+
+-- Table: faculty_event_roles (events a faculty member is scoped to; none = all events)
+CREATE TABLE IF NOT EXISTS faculty_event_roles (
+    faculty_id BIGINT NOT NULL REFERENCES faculty(id) ON DELETE CASCADE,
+    event_id BIGINT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    granted_by BIGINT REFERENCES faculty(id) ON DELETE SET NULL,
+    granted_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (faculty_id, event_id)
+);
+
+-- Table: idempotency_keys (stored responses for retried POSTs carrying an Idempotency-Key header)
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    user_scope TEXT NOT NULL, -- '<role>:<user id>' so keys never collide across users
+    idem_key TEXT NOT NULL,
+    request_hash BYTEA NOT NULL, -- sha256 of method, path and body; detects key reuse with a different payload
+    status_code INT, -- NULL while the original request is still in flight
+    content_type TEXT,
+    response_body BYTEA,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (user_scope, idem_key)
+);
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys (expires_at);
+
+-- updated_at on mutable tables, maintained by a trigger so every UPDATE path (handlers, bulk upserts,
+-- merges) stamps it without having to remember to.
+CREATE OR REPLACE FUNCTION set_updated_at() RETURNS trigger AS $$
+BEGIN
+    NEW.updated_at := NOW();
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DO $$
+DECLARE
+    t TEXT;
+BEGIN
+    FOREACH t IN ARRAY ARRAY['events', 'faculty', 'volunteers', 'committees', 'announcements',
+                             'locations', 'volunteer_assignments', 'questions', 'departments']
+    LOOP
+        EXECUTE format('ALTER TABLE %I ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()', t);
+        EXECUTE format('DROP TRIGGER IF EXISTS trg_%s_updated_at ON %I', t, t);
+        EXECUTE format('CREATE TRIGGER trg_%s_updated_at BEFORE UPDATE ON %I FOR EACH ROW EXECUTE FUNCTION set_updated_at()', t, t);
+    END LOOP;
+END$$;
+
+INSERT INTO events (name, venue, tz, starts_at, ends_at)
+SELECT 'Amma Birthday 2025', 'Amritapuri', 'Asia/Kolkata',
+       TIMESTAMPTZ '2025-09-26 07:00:00+05:30', TIMESTAMPTZ '2025-09-27 23:59:00+05:30'
+WHERE NOT EXISTS (SELECT 1 FROM events WHERE name = 'Amma Birthday 2025');
+
+INSERT INTO faculty (name, email, password_hash, role) VALUES (
+    'Admin User',
+    'admin@example.com', -- E.g., 'admin@example.com'
+    '$2b$12$DgWqOsehDXrQx9wxMGP70u6P/TtXrO.YL1qCfU.dHSMuQqJcOyg86', 
+    'admin'
+) ON CONFLICT (email) DO NOTHING;
@@ -0,0 +1,4 @@
+-- GET /announcements/me resolves visibility in one query with correlated EXISTS lookups on the
+-- volunteer's assignments (va.volunteer_id = $1 plus event/committee/role). The unique key on
+-- (event_id, committee_id, volunteer_id) can't serve a lookup by volunteer, so index that first.
+CREATE INDEX IF NOT EXISTS idx_assignments_volunteer ON volunteer_assignments (volunteer_id, event_id, committee_id);
@@ -0,0 +1,29 @@
+-- Indexes for the attendance dashboard queries (ListShiftsWithoutCheckIn, ListAssignmentsWithCheckinStatus,
+-- ListActiveCheckinsInShift and the by-hour/by-committee reports).
+--
+-- Those queries filter assignments on event_id [, committee_id] and the shift's start date, and then look up
+-- each assignment's attendance for that day. The date is compared in the event's timezone
+-- ((start_time AT TIME ZONE e.tz)::date), which can't be a functional index because e.tz comes from a join,
+-- so the indexes cover the columns and Postgres filters the date on the (few) matching rows.
+--
+-- To check a plan, e.g. for a dashboard refresh:
+--   EXPLAIN (ANALYZE, BUFFERS)
+--   SELECT va.id FROM volunteer_assignments va JOIN events e ON e.id = va.event_id
+--   WHERE va.event_id = 1 AND va.committee_id = 3 AND (va.start_time AT TIME ZONE e.tz)::date = '2025-09-26'
+--     AND NOT EXISTS (SELECT 1 FROM attendance att WHERE att.assignment_id = va.id
+--                     AND (att.check_in_time AT TIME ZONE e.tz)::date = '2025-09-26');
+-- Without these indexes both tables are read with a Seq Scan; with them expect Index Scans using
+-- idx_assignments_event_committee_start and idx_attendance_assignment_checkin. TestDashboardQueryUsesIndexes
+-- (db/indexes_test.go) asserts this plan, and BenchmarkDashboardQuery times it.
+
+-- Assignments of an event (optionally one committee), in shift order
+CREATE INDEX IF NOT EXISTS idx_assignments_event_committee_start
+    ON volunteer_assignments (event_id, committee_id, start_time);
+
+-- Per-assignment attendance lookups (EXISTS / correlated subqueries), newest check-in last
+CREATE INDEX IF NOT EXISTS idx_attendance_assignment_checkin
+    ON attendance (assignment_id, check_in_time);
+
+-- Time-window scans over check-ins (by-hour histogram, live arrivals)
+CREATE INDEX IF NOT EXISTS idx_attendance_check_in_time
+    ON attendance (check_in_time);
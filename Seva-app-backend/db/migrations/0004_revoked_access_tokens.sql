@@ -0,0 +1,8 @@
+-- Blocklist of logged-out access tokens, keyed by the token's jti. Only used when ACCESS_TOKEN_BLOCKLIST
+-- is on, and only admin tokens carry a jti. Rows expire with the token they revoke (expires_at = the
+-- token's exp) and are swept by the API, so the table stays bounded by one access-token lifetime.
+CREATE TABLE IF NOT EXISTS revoked_access_tokens (
+    jti TEXT PRIMARY KEY,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_revoked_access_tokens_expires_at ON revoked_access_tokens (expires_at);
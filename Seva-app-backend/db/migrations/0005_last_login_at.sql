@@ -0,0 +1,20 @@
+-- When each account last logged in (or refreshed its tokens), so admins can spot dormant accounts.
+-- NULL means the account has not logged in since this column was added.
+ALTER TABLE faculty ADD COLUMN IF NOT EXISTS last_login_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE volunteers ADD COLUMN IF NOT EXISTS last_login_at TIMESTAMP WITH TIME ZONE;
+
+-- A login is not an edit: re-create the updated_at triggers on these two tables so an UPDATE that only
+-- touches last_login_at leaves updated_at alone.
+DO $$
+DECLARE
+    t TEXT;
+BEGIN
+    FOREACH t IN ARRAY ARRAY['faculty', 'volunteers']
+    LOOP
+        EXECUTE format('DROP TRIGGER IF EXISTS trg_%s_updated_at ON %I', t, t);
+        EXECUTE format('CREATE TRIGGER trg_%s_updated_at BEFORE UPDATE ON %I FOR EACH ROW '
+                       'WHEN ((to_jsonb(OLD) - ''last_login_at'' - ''updated_at'') IS DISTINCT FROM '
+                       '(to_jsonb(NEW) - ''last_login_at'' - ''updated_at'')) '
+                       'EXECUTE FUNCTION set_updated_at()', t, t);
+    END LOOP;
+END$$;
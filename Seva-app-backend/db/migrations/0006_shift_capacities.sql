@@ -0,0 +1,10 @@
+-- Optional cap on how many volunteers a committee's shift takes. Only non-cancelled assignments count
+-- toward it; CreateAssignment and BulkUpload refuse to go over it unless called with ?override=true.
+-- Shift names match case-insensitively, like the canonical shift names on volunteer_assignments.
+CREATE TABLE IF NOT EXISTS shift_capacities (
+    committee_id BIGINT NOT NULL REFERENCES committees(id) ON DELETE CASCADE,
+    shift TEXT NOT NULL,
+    capacity INT NOT NULL CHECK (capacity >= 0),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+CREATE UNIQUE INDEX IF NOT EXISTS uq_shift_capacities_committee_shift ON shift_capacities (committee_id, lower(shift));
@@ -0,0 +1,33 @@
+-- Shifts as first-class, per-event entities. volunteer_assignments.shift (free text) stays for now and is
+-- kept in sync with the linked shift's name by the API; shift_id is what filters and reports should use.
+CREATE TABLE IF NOT EXISTS shifts (
+    id BIGSERIAL PRIMARY KEY,
+    event_id BIGINT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    start_time TIMESTAMP WITH TIME ZONE,
+    end_time TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CHECK (end_time IS NULL OR start_time IS NULL OR end_time > start_time)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS ux_shifts_event_name_lower ON shifts (event_id, lower(name));
+
+DROP TRIGGER IF EXISTS trg_shifts_updated_at ON shifts;
+CREATE TRIGGER trg_shifts_updated_at BEFORE UPDATE ON shifts FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+ALTER TABLE volunteer_assignments ADD COLUMN IF NOT EXISTS shift_id BIGINT REFERENCES shifts(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_assignments_shift_id ON volunteer_assignments (shift_id);
+
+-- Backfill: one shift per distinct (case-insensitive) shift name already used in each event, spanning the
+-- earliest start and latest end of its assignments, then link the assignments to it.
+INSERT INTO shifts (event_id, name, start_time, end_time)
+SELECT event_id, min(shift), min(start_time),
+       CASE WHEN min(start_time) IS NULL OR max(end_time) > min(start_time) THEN max(end_time) END
+FROM volunteer_assignments
+WHERE shift IS NOT NULL AND btrim(shift) <> ''
+GROUP BY event_id, lower(shift)
+ON CONFLICT (event_id, lower(name)) DO NOTHING;
+
+UPDATE volunteer_assignments va SET shift_id = s.id
+FROM shifts s
+WHERE va.shift_id IS NULL AND s.event_id = va.event_id AND lower(s.name) = lower(va.shift);
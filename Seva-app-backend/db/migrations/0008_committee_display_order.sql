@@ -0,0 +1,4 @@
+-- Custom display order for committees in the app (e.g. by importance). Lower values come first;
+-- committees with the same display_order (all of them, until organizers set one) fall back to name order.
+ALTER TABLE committees ADD COLUMN IF NOT EXISTS display_order INT NOT NULL DEFAULT 0;
+CREATE INDEX IF NOT EXISTS idx_committees_event_display_order ON committees (event_id, display_order, name);
@@ -0,0 +1,14 @@
+-- 0001 seeds an admin@example.com admin whose bcrypt hash is in the repository, so anyone could log in as it.
+-- Shipped migrations are never edited, so disable it here instead: while its password is still the seeded
+-- one, take away the password and the admin role and end its sessions. BootstrapAdmin then seeds a real
+-- admin on the next start if no other admin exists (or use BOOTSTRAP_ADMIN_EMAIL/PASSWORD or cmd/seed).
+UPDATE auth_sessions SET revoked_at = NOW()
+WHERE revoked_at IS NULL AND faculty_id IN (
+    SELECT id FROM faculty
+    WHERE lower(email) = 'admin@example.com'
+      AND password_hash = '$2b$12$DgWqOsehDXrQx9wxMGP70u6P/TtXrO.YL1qCfU.dHSMuQqJcOyg86'
+);
+
+UPDATE faculty SET password_hash = NULL, role = 'faculty'
+WHERE lower(email) = 'admin@example.com'
+  AND password_hash = '$2b$12$DgWqOsehDXrQx9wxMGP70u6P/TtXrO.YL1qCfU.dHSMuQqJcOyg86';
@@ -0,0 +1,8 @@
+-- A reminder is not an edit: re-create the volunteer_assignments updated_at trigger so the reminder job's
+-- UPDATE that only stamps reminder_sent_at leaves updated_at alone. Otherwise an admin holding the row
+-- would get a spurious 409 on their next expected_updated_at update.
+DROP TRIGGER IF EXISTS trg_volunteer_assignments_updated_at ON volunteer_assignments;
+CREATE TRIGGER trg_volunteer_assignments_updated_at BEFORE UPDATE ON volunteer_assignments FOR EACH ROW
+    WHEN ((to_jsonb(OLD) - 'reminder_sent_at' - 'updated_at') IS DISTINCT FROM
+          (to_jsonb(NEW) - 'reminder_sent_at' - 'updated_at'))
+    EXECUTE FUNCTION set_updated_at();
@@ -0,0 +1,41 @@
+-- ux_attendance_active_assignment_day keyed on the UTC date while the API's duplicate check uses the event's
+-- local date, so near midnight the two disagreed: a legitimate check-in on a new local day could hit the
+-- index, and two check-ins on one local day but different UTC days passed it. The index can't look up
+-- events.tz itself, so keep the local check-in day on the row and key the index on that.
+ALTER TABLE attendance ADD COLUMN IF NOT EXISTS check_in_day DATE;
+
+CREATE OR REPLACE FUNCTION set_attendance_check_in_day() RETURNS trigger AS $$
+BEGIN
+    NEW.check_in_day := (NEW.check_in_time AT TIME ZONE COALESCE(
+        (SELECT e.tz FROM volunteer_assignments va JOIN events e ON e.id = va.event_id WHERE va.id = NEW.assignment_id),
+        'UTC'))::date;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_attendance_check_in_day ON attendance;
+CREATE TRIGGER trg_attendance_check_in_day BEFORE INSERT OR UPDATE OF check_in_time, assignment_id ON attendance
+    FOR EACH ROW EXECUTE FUNCTION set_attendance_check_in_day();
+
+UPDATE attendance a
+SET check_in_day = (a.check_in_time AT TIME ZONE e.tz)::date
+FROM volunteer_assignments va
+JOIN events e ON e.id = va.event_id
+WHERE va.id = a.assignment_id AND a.check_in_day IS NULL;
+ALTER TABLE attendance ALTER COLUMN check_in_day SET NOT NULL;
+
+-- The old index let an assignment have two open check-ins on one local day when they fell on different UTC
+-- days, and those rows would stop the new index from building. Keep the earliest of each such group open
+-- and close the others at their own check-in time, so no record is dropped.
+UPDATE attendance a
+SET check_out_time = a.check_in_time
+WHERE a.check_out_time IS NULL
+  AND EXISTS (
+    SELECT 1 FROM attendance o
+    WHERE o.assignment_id = a.assignment_id AND o.check_in_day = a.check_in_day AND o.check_out_time IS NULL
+      AND (o.check_in_time, o.id) < (a.check_in_time, a.id));
+
+DROP INDEX IF EXISTS ux_attendance_active_assignment_day;
+CREATE UNIQUE INDEX ux_attendance_active_assignment_day
+ON attendance (assignment_id, check_in_day)
+WHERE check_out_time IS NULL;
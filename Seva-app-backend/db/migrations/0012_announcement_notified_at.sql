@@ -0,0 +1,16 @@
+-- When an announcement was pushed to its volunteers. Announcements published on creation are stamped right
+-- away; scheduled ones stay NULL until the dispatcher claims them once publish_at has passed.
+ALTER TABLE announcements ADD COLUMN IF NOT EXISTS notified_at TIMESTAMP WITH TIME ZONE;
+
+-- Everything already visible was handled (or missed) before this column existed; don't push it again.
+UPDATE announcements SET notified_at = COALESCE(publish_at, created_at)
+WHERE notified_at IS NULL AND (publish_at IS NULL OR publish_at <= NOW());
+
+CREATE INDEX IF NOT EXISTS idx_announcements_pending_publish ON announcements (publish_at) WHERE notified_at IS NULL;
+
+-- Sending is not an edit: claiming an announcement leaves updated_at alone.
+DROP TRIGGER IF EXISTS trg_announcements_updated_at ON announcements;
+CREATE TRIGGER trg_announcements_updated_at BEFORE UPDATE ON announcements FOR EACH ROW
+    WHEN ((to_jsonb(OLD) - 'notified_at' - 'updated_at') IS DISTINCT FROM
+          (to_jsonb(NEW) - 'notified_at' - 'updated_at'))
+    EXECUTE FUNCTION set_updated_at();
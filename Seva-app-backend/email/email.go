@@ -0,0 +1,128 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net"
+	"net/smtp"
+	"os"
+	"strings"
+	"time"
+)
+
+// sendTimeout bounds a background send so a hung SMTP server can't pile up goroutines.
+const sendTimeout = 20 * time.Second
+
+// Message is a plain-text email.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Sender delivers email (SMTP, provider API, ...).
+type Sender interface {
+	Send(ctx context.Context, m Message) error
+}
+
+// Noop discards every email. It is the default when EMAIL_PROVIDER is unset.
+type Noop struct{}
+
+func (Noop) Send(context.Context, Message) error { return nil }
+
+// Log writes emails to the server log instead of sending them; handy in development.
+type Log struct{}
+
+func (Log) Send(_ context.Context, m Message) error {
+	log.Printf("email (not sent): to=%s subject=%q\n%s", m.To, m.Subject, m.Body)
+	return nil
+}
+
+// SMTP sends through an SMTP server with PLAIN auth (when Username is set).
+type SMTP struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+func (s *SMTP) Send(ctx context.Context, m Message) error {
+	if strings.ContainsAny(m.To+m.Subject, "\r\n") {
+		return errors.New("email: newline in To or Subject")
+	}
+	var auth smtp.Auth
+	if s.Username != "" {
+		auth = smtp.PlainAuth("", s.Username, s.Password, s.Host)
+	}
+	msg := "From: " + s.From + "\r\n" +
+		"To: " + m.To + "\r\n" +
+		"Subject: " + m.Subject + "\r\n" +
+		"MIME-Version: 1.0\r\n" +
+		"Content-Type: text/plain; charset=UTF-8\r\n" +
+		"\r\n" + m.Body
+
+	// smtp.SendMail has no context support; run it aside and give up waiting when ctx ends.
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(net.JoinHostPort(s.Host, s.Port), auth, s.From, []string{m.To}, []byte(msg))
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// FromEnv builds the Sender selected by EMAIL_PROVIDER.
+//   - "" / "none": Noop (email disabled)
+//   - "log":       Log
+//   - "smtp":      SMTP using SMTP_HOST, SMTP_PORT (default 587), SMTP_USERNAME, SMTP_PASSWORD, EMAIL_FROM
+func FromEnv() Sender {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv("EMAIL_PROVIDER"))) {
+	case "", "none", "noop":
+		return Noop{}
+	case "log":
+		return Log{}
+	case "smtp":
+		host, from := os.Getenv("SMTP_HOST"), os.Getenv("EMAIL_FROM")
+		if host == "" || from == "" {
+			log.Println("EMAIL_PROVIDER=smtp but SMTP_HOST or EMAIL_FROM is not set; email disabled")
+			return Noop{}
+		}
+		port := os.Getenv("SMTP_PORT")
+		if port == "" {
+			port = "587"
+		}
+		return &SMTP{
+			Host:     host,
+			Port:     port,
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     from,
+		}
+	default:
+		log.Printf("Unknown EMAIL_PROVIDER %q; email disabled", os.Getenv("EMAIL_PROVIDER"))
+		return Noop{}
+	}
+}
+
+// SendAsync sends m in the background and only logs failures, so callers never block on
+// (or fail because of) email delivery. A nil Sender or empty recipient is a no-op.
+func SendAsync(s Sender, m Message) {
+	if s == nil || strings.TrimSpace(m.To) == "" {
+		return
+	}
+	if _, ok := s.(Noop); ok {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+		defer cancel()
+		if err := s.Send(ctx, m); err != nil {
+			log.Printf("email: failed to send %q to %s: %v", m.Subject, m.To, err)
+		}
+	}()
+}
@@ -1,37 +1,57 @@
 package announcements
 
 import (
+	"bufio"
+	"bytes"
+	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"log"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
+	"Seva-app-backend/db"
 	mw "Seva-app-backend/middleware"
 	"Seva-app-backend/models" // Using models.ErrorResponse and other models
+	"Seva-app-backend/notify"
+	"Seva-app-backend/validation"
 )
 
 // Register mounts announcement routes under /announcements
-func Register(g fiber.Router, pool *pgxpool.Pool, jwtGuard fiber.Handler, requireAdmin fiber.Handler, requireVolunteer fiber.Handler) {
+func Register(g fiber.Router, pool *pgxpool.Pool, jwtGuard fiber.Handler, requireAdmin fiber.Handler, requireVolunteer fiber.Handler, notifier *notify.Dispatcher) {
 	// Admin/Faculty Reads (list all, get by ID)
 	// g.Get("/", jwtGuard, mw.RequireRole(string(mw.RoleFaculty), string(mw.RoleAdmin)), ListAll(pool)) // Faculty/Admin can list all announcements
 	// g.Get("/:id", jwtGuard, mw.RequireRole(string(mw.RoleFaculty), string(mw.RoleAdmin)), Get(pool))
-	g.Get("/", jwtGuard, mw.RequireRole(string(models.UserRoleFaculty), string(models.UserRoleAdmin)), ListAll(pool))
-	g.Get("/:id", jwtGuard, mw.RequireRole(string(models.UserRoleFaculty), string(models.UserRoleAdmin)), Get(pool))
+	g.Get("/", jwtGuard, mw.RequireRole(string(models.UserRoleFaculty), string(models.UserRoleAdmin)), mw.RequireEventScope(pool, mw.EventIDFromQuery), ListAll(pool))
+	g.Get("/:id", jwtGuard, mw.RequireRole(string(models.UserRoleFaculty), string(models.UserRoleAdmin)), mw.RequireEventScope(pool, EventOfAnnouncement(pool)), Get(pool))
 	// Volunteer Read (list only relevant announcements)
 	g.Get("/me", jwtGuard, requireVolunteer, ListForVolunteer(pool))
+	g.Get("/me/stream", jwtGuard, requireVolunteer, StreamForVolunteer(notifier))
+	g.Post("/:id/ack", jwtGuard, requireVolunteer, Ack(pool))
+	g.Get("/:id/acks", jwtGuard, requireAdmin, ListAcks(pool))
 
 	// Admin Writes (protected by JWT and Admin role)
-	g.Post("/", jwtGuard, requireAdmin, Create(pool))
+	g.Post("/", jwtGuard, requireAdmin, Create(pool, notifier))
 	g.Put("/:id", jwtGuard, requireAdmin, Update(pool))
 	g.Delete("/:id", jwtGuard, requireAdmin, Del(pool))
 }
 
-// listAll (Admin/Faculty) - GET /announcements?event_id=&committee_id=&active_only=true&limit=&offset=
+// listAll (Admin/Faculty) - GET /announcements?event_id=&committee_id=&active_only=true&q=&limit=&offset=
+// q is a full-text search (English stemming, web-search syntax: "quoted phrases", OR, -exclude) over
+// title and body, not a substring match. With q, results are ordered by relevance (title matches
+// weigh more) then recency; without it, by priority then recency.
 func ListAll(pool *pgxpool.Pool) fiber.Handler {
 	return func(c *fiber.Ctx) error {
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
 		eventID, err := strconv.ParseInt(c.Query("event_id", ""), 10, 64)
 		if err != nil && c.Query("event_id", "") != "" { // Allow empty event_id to list all
 			return fiber.NewError(fiber.StatusBadRequest, "invalid event_id")
@@ -63,11 +83,6 @@ func ListAll(pool *pgxpool.Pool) fiber.Handler {
 			where = append(where, "(a.expires_at IS NULL OR a.expires_at > NOW())")
 		}
 
-		whereClause := ""
-		if len(where) > 0 {
-			whereClause = "WHERE " + strings.Join(where, " AND ")
-		}
-
 		order := `
 		  ORDER BY CASE a.priority
 		             WHEN 'urgent' THEN 1
@@ -76,11 +91,28 @@ func ListAll(pool *pgxpool.Pool) fiber.Handler {
 		             ELSE 4
 		           END, a.created_at DESC
 		`
+		if q := strings.TrimSpace(c.Query("q", "")); q != "" {
+			tsq := "websearch_to_tsquery('english', $" + strconv.Itoa(paramCounter) + ")"
+			where = append(where, "a.search_tsv @@ "+tsq)
+			order = `
+		  ORDER BY ts_rank(a.search_tsv, ` + tsq + `) DESC, a.created_at DESC
+		`
+			args = append(args, q)
+			paramCounter++
+		}
+
+		whereClause := ""
+		if len(where) > 0 {
+			whereClause = "WHERE " + strings.Join(where, " AND ")
+		}
 
 		args = append(args, limit, offset)
 		query := `
 		  SELECT a.id, a.event_id, a.committee_id, a.title, a.body,
-		         a.priority::text, a.created_by, a.created_at, a.expires_at,
+		         a.priority::text, a.created_by, a.created_at, a.updated_at, a.expires_at,
+		         a.publish_at, (a.publish_at IS NOT NULL AND a.publish_at > NOW()) AS scheduled,
+		         a.target_role::text,
+		         ARRAY(SELECT t.volunteer_id FROM announcement_targets t WHERE t.announcement_id = a.id ORDER BY t.volunteer_id) AS target_volunteer_ids,
 		         f.name AS created_by_name, c.name AS committee_name
 		  FROM announcements a
 		  LEFT JOIN faculty f ON f.id = a.created_by
@@ -88,7 +120,7 @@ func ListAll(pool *pgxpool.Pool) fiber.Handler {
 		  ` + whereClause + order + `
 		  LIMIT $` + strconv.Itoa(paramCounter) + ` OFFSET $` + strconv.Itoa(paramCounter+1)
 
-		rows, err := pool.Query(c.Context(), query, args...)
+		rows, err := pool.Query(ctx, query, args...)
 		if err != nil {
 			return err
 		}
@@ -98,12 +130,15 @@ func ListAll(pool *pgxpool.Pool) fiber.Handler {
 		for rows.Next() {
 			var a models.Announcement
 			var priorityStr string // To scan the ENUM as text
+			var targetRole *string
 			if err := rows.Scan(&a.ID, &a.EventID, &a.CommitteeID, &a.Title, &a.Body,
-				&priorityStr, &a.CreatedBy, &a.CreatedAt, &a.ExpiresAt,
+				&priorityStr, &a.CreatedBy, &a.CreatedAt, &a.UpdatedAt, &a.ExpiresAt,
+				&a.PublishAt, &a.Scheduled, &targetRole, &a.TargetVolunteerIDs,
 				&a.CreatedByName, &a.CommitteeName); err != nil {
 				return err
 			}
 			a.Priority = models.AnnouncementPriority(priorityStr)
+			a.TargetRole = assignmentRolePtr(targetRole)
 			out = append(out, a)
 		}
 		return c.JSON(out)
@@ -111,9 +146,14 @@ func ListAll(pool *pgxpool.Pool) fiber.Handler {
 }
 
 // listForVolunteer (Volunteer) - GET /announcements/me
-// Lists announcements relevant to the logged-in volunteer (event-wide AND committee-specific to their assignments).
+// Lists announcements relevant to the logged-in volunteer: event-wide and committee-specific ones for their
+// assignments, ones targeting their assignment role, and ones that list them explicitly.
+// Visibility is derived inline (see visibleToVolunteer), so this is a single round-trip.
 func ListForVolunteer(pool *pgxpool.Pool) fiber.Handler {
 	return func(c *fiber.Ctx) error {
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
 		volunteerID, err := mw.GetUserIDFromClaims(c)
 		if err != nil {
 			return fiber.NewError(fiber.StatusUnauthorized, "volunteer ID not found in token")
@@ -123,76 +163,22 @@ func ListForVolunteer(pool *pgxpool.Pool) fiber.Handler {
 		limit := clampInt(c.QueryInt("limit", 100), 1, 500)
 		offset := maxInt(c.QueryInt("offset", 0), 0)
 
-		// 1. Get all unique event_ids and committee_ids associated with the volunteer
-		var assignedEventIDs []int64
-		var assignedCommitteeIDs []int64
-
-		rows, err := pool.Query(c.Context(), `
-			SELECT DISTINCT event_id, committee_id
-			FROM volunteer_assignments
-			WHERE volunteer_id = $1
-		`, volunteerID)
-		if err != nil {
-			return err
-		}
-		defer rows.Close()
-
-		for rows.Next() {
-			var eventID, committeeID int64
-			if err := rows.Scan(&eventID, &committeeID); err != nil {
-				return err
-			}
-			assignedEventIDs = append(assignedEventIDs, eventID)
-			assignedCommitteeIDs = append(assignedCommitteeIDs, committeeID)
-		}
-
-		// If the volunteer has no assignments, return empty list
-		if len(assignedEventIDs) == 0 {
-			return c.JSON([]models.Announcement{})
-		}
-
-		// Remove duplicate event IDs
-		uniqueEventIDs := make(map[int64]struct{})
-		for _, id := range assignedEventIDs {
-			uniqueEventIDs[id] = struct{}{}
-		}
-		finalEventIDs := make([]int64, 0, len(uniqueEventIDs))
-		for id := range uniqueEventIDs {
-			finalEventIDs = append(finalEventIDs, id)
-		}
-
-		// Remove duplicate committee IDs (optional, but good for cleaner query if array processing is slow)
-		uniqueCommitteeIDs := make(map[int64]struct{})
-		for _, id := range assignedCommitteeIDs {
-			uniqueCommitteeIDs[id] = struct{}{}
-		}
-		finalCommitteeIDs := make([]int64, 0, len(uniqueCommitteeIDs))
-		for id := range uniqueCommitteeIDs {
-			finalCommitteeIDs = append(finalCommitteeIDs, id)
-		}
-
-		// 2. Build the WHERE clause for announcements
-		args := []any{}
+		// Build the WHERE clause for announcements ($1 is the volunteer)
+		args := []any{volunteerID}
 		whereConditions := []string{}
-		paramCounter := 1
+		paramCounter := 2
 
-		// Condition 1: Event-wide announcements for any of the volunteer's assigned events
-		whereConditions = append(whereConditions, "(a.event_id = ANY($"+strconv.Itoa(paramCounter)+") AND a.committee_id IS NULL)")
-		args = append(args, finalEventIDs)
-		paramCounter++
+		// Event/committee-wide, role-targeted or explicitly addressed to this volunteer
+		whereConditions = append(whereConditions, visibleToVolunteer("$1"))
 
-		// Condition 2: Committee-specific announcements for any of the volunteer's assigned committees
-		if len(finalCommitteeIDs) > 0 {
-			whereConditions = append(whereConditions, "(a.committee_id = ANY($"+strconv.Itoa(paramCounter)+"))")
-			args = append(args, finalCommitteeIDs)
-			paramCounter++
-		}
+		// Scheduled announcements stay hidden until publish_at
+		whereConditions = append(whereConditions, "(a.publish_at IS NULL OR a.publish_at <= NOW())")
 
 		if activeOnly {
 			whereConditions = append(whereConditions, "(a.expires_at IS NULL OR a.expires_at > NOW())")
 		}
 
-		whereClause := "WHERE " + strings.Join(whereConditions, " OR ") // Use OR to combine event-wide and committee-specific
+		whereClause := "WHERE " + strings.Join(whereConditions, " AND ")
 
 		order := `
 		  ORDER BY CASE a.priority
@@ -206,15 +192,20 @@ func ListForVolunteer(pool *pgxpool.Pool) fiber.Handler {
 		args = append(args, limit, offset)
 		query := `
 		  SELECT a.id, a.event_id, a.committee_id, a.title, a.body,
-		         a.priority::text, a.created_by, a.created_at, a.expires_at,
-		         f.name AS created_by_name, c.name AS committee_name
+		         a.priority::text, a.created_by, a.created_at, a.updated_at, a.expires_at,
+		         a.publish_at, (a.publish_at IS NOT NULL AND a.publish_at > NOW()) AS scheduled,
+		         a.target_role::text,
+		         ARRAY(SELECT t.volunteer_id FROM announcement_targets t WHERE t.announcement_id = a.id ORDER BY t.volunteer_id) AS target_volunteer_ids,
+		         f.name AS created_by_name, c.name AS committee_name,
+		         (ak.volunteer_id IS NOT NULL) AS acked
 		  FROM announcements a
 		  LEFT JOIN faculty f ON f.id = a.created_by
 		  LEFT JOIN committees c ON c.id = a.committee_id
+		  LEFT JOIN announcement_acks ak ON ak.announcement_id = a.id AND ak.volunteer_id = $1
 		  ` + whereClause + order + `
 		  LIMIT $` + strconv.Itoa(paramCounter) + ` OFFSET $` + strconv.Itoa(paramCounter+1)
 
-		rows, err = pool.Query(c.Context(), query, args...)
+		rows, err := pool.Query(ctx, query, args...)
 		if err != nil {
 			return err
 		}
@@ -224,36 +215,81 @@ func ListForVolunteer(pool *pgxpool.Pool) fiber.Handler {
 		for rows.Next() {
 			var a models.Announcement
 			var priorityStr string
+			var targetRole *string
+			var acked bool
 			if err := rows.Scan(&a.ID, &a.EventID, &a.CommitteeID, &a.Title, &a.Body,
-				&priorityStr, &a.CreatedBy, &a.CreatedAt, &a.ExpiresAt,
-				&a.CreatedByName, &a.CommitteeName); err != nil {
+				&priorityStr, &a.CreatedBy, &a.CreatedAt, &a.UpdatedAt, &a.ExpiresAt,
+				&a.PublishAt, &a.Scheduled, &targetRole, &a.TargetVolunteerIDs,
+				&a.CreatedByName, &a.CommitteeName, &acked); err != nil {
 				return err
 			}
 			a.Priority = models.AnnouncementPriority(priorityStr)
+			a.TargetRole = assignmentRolePtr(targetRole)
+			a.Acked = &acked
 			out = append(out, a)
 		}
 		return c.JSON(out)
 	}
 }
 
+// CountActiveForVolunteer returns how many announcements GET /announcements/me would list for the
+// volunteer by default (published and not expired), and how many of those they have not acked yet.
+func CountActiveForVolunteer(ctx context.Context, pool *pgxpool.Pool, volunteerID int64) (active, unacked int, err error) {
+	err = pool.QueryRow(ctx, `
+		SELECT COUNT(*),
+		       COUNT(*) FILTER (WHERE NOT EXISTS (
+		           SELECT 1 FROM announcement_acks ak WHERE ak.announcement_id = a.id AND ak.volunteer_id = $1))
+		FROM announcements a
+		WHERE `+visibleToVolunteer("$1")+`
+		  AND (a.publish_at IS NULL OR a.publish_at <= NOW())
+		  AND (a.expires_at IS NULL OR a.expires_at > NOW())
+	`, volunteerID).Scan(&active, &unacked)
+	return active, unacked, err
+}
+
+// EventOfAnnouncement resolves the event of the :id announcement for mw.RequireEventScope.
+func EventOfAnnouncement(pool *pgxpool.Pool) mw.EventIDResolver {
+	return func(c *fiber.Ctx) (int64, bool, error) {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil || id <= 0 {
+			return 0, false, fiber.NewError(fiber.StatusBadRequest, "invalid id")
+		}
+		var eventID int64
+		if err := pool.QueryRow(c.Context(), `SELECT event_id FROM announcements WHERE id = $1`, id).Scan(&eventID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return 0, false, fiber.NewError(fiber.StatusNotFound, "announcement not found")
+			}
+			return 0, false, err
+		}
+		return eventID, true, nil
+	}
+}
+
 // GET /announcements/:id
 func Get(pool *pgxpool.Pool) fiber.Handler {
 	return func(c *fiber.Ctx) error {
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
 		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
 		if err != nil || id <= 0 {
 			return fiber.NewError(fiber.StatusBadRequest, "invalid id")
 		}
 		var a models.Announcement
 		var priorityStr string
-		err = pool.QueryRow(c.Context(), `
+		var targetRole *string
+		err = pool.QueryRow(ctx, `
 		  SELECT a.id, a.event_id, a.committee_id, a.title, a.body,
-		         a.priority::text, a.created_by, a.created_at, a.expires_at,
+		         a.priority::text, a.created_by, a.created_at, a.updated_at, a.expires_at,
+		         a.publish_at, (a.publish_at IS NOT NULL AND a.publish_at > NOW()) AS scheduled,
+		         a.target_role::text,
+		         ARRAY(SELECT t.volunteer_id FROM announcement_targets t WHERE t.announcement_id = a.id ORDER BY t.volunteer_id) AS target_volunteer_ids,
 		         f.name AS created_by_name, c.name AS committee_name
 		  FROM announcements a
 		  LEFT JOIN faculty f ON f.id = a.created_by
 		  LEFT JOIN committees c ON c.id = a.committee_id
 		  WHERE a.id=$1
-		`, id).Scan(&a.ID, &a.EventID, &a.CommitteeID, &a.Title, &a.Body, &priorityStr, &a.CreatedBy, &a.CreatedAt, &a.ExpiresAt, &a.CreatedByName, &a.CommitteeName)
+		`, id).Scan(&a.ID, &a.EventID, &a.CommitteeID, &a.Title, &a.Body, &priorityStr, &a.CreatedBy, &a.CreatedAt, &a.UpdatedAt, &a.ExpiresAt, &a.PublishAt, &a.Scheduled, &targetRole, &a.TargetVolunteerIDs, &a.CreatedByName, &a.CommitteeName)
 		if err != nil {
 			if errors.Is(err, sql.ErrNoRows) {
 				return fiber.NewError(fiber.StatusNotFound, "not found")
@@ -261,45 +297,95 @@ func Get(pool *pgxpool.Pool) fiber.Handler {
 			return err
 		}
 		a.Priority = models.AnnouncementPriority(priorityStr)
+		a.TargetRole = assignmentRolePtr(targetRole)
 		return c.JSON(a)
 	}
 }
 
 // POST /announcements  (guarded by admin)
-func Create(pool *pgxpool.Pool) fiber.Handler {
+// Invalid fields are a 422 validation_failed naming them.
+// Urgent announcements are pushed to the targeted volunteers in the background via notifier; scheduled
+// ones are pushed by PublishDue once publish_at passes.
+func Create(pool *pgxpool.Pool, notifier *notify.Dispatcher) fiber.Handler {
 	return func(c *fiber.Ctx) error {
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
 		var b models.CreateAnnouncementRequest
 		if err := c.BodyParser(&b); err != nil {
 			return fiber.NewError(fiber.StatusBadRequest, "bad json")
 		}
-		if b.EventID <= 0 || strings.TrimSpace(b.Title) == "" || strings.TrimSpace(b.Body) == "" {
-			return fiber.NewError(fiber.StatusBadRequest, "event_id, title and body are required")
+		var v validation.Validator
+		v.Check(b.EventID > 0, "event_id", validation.FieldRequired)
+		if v.Required("title", b.Title) {
+			v.MaxLen("title", b.Title, models.MaxTitleLen)
+		}
+		if v.Required("body", b.Body) {
+			v.MaxLen("body", b.Body, models.MaxBodyLen)
+		}
+		pr, err := normPriority(string(b.Priority))
+		v.Check(err == nil, "priority", validation.FieldInvalidValue)
+		role, err := normTargetRole(b.TargetRole)
+		v.Check(err == nil, "target_role", validation.FieldInvalidValue)
+		if err := v.Err(); err != nil {
+			return err
+		}
+		if b.CommitteeID != nil {
+			if err := checkCommitteeInEvent(ctx, pool, *b.CommitteeID, b.EventID); err != nil {
+				return err
+			}
 		}
-		pr := normPriority(string(b.Priority))
 
 		claims := c.Locals("claims").(*mw.Claims)
 		createdBy := &claims.Sub // Set created_by to the ID of the logged-in admin/faculty
 
+		tx, err := pool.Begin(ctx)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback(ctx)
+
 		var a models.Announcement
 		var priorityStr string
-		err := pool.QueryRow(c.Context(), `
-		  INSERT INTO announcements(event_id, committee_id, title, body, priority, created_by, expires_at)
-		  VALUES ($1,$2,$3,$4,$5::announcement_priority,$6,$7)
+		var targetRole *string
+		err = tx.QueryRow(ctx, `
+		  INSERT INTO announcements(event_id, committee_id, title, body, priority, created_by, expires_at, publish_at, target_role, notified_at)
+		  VALUES ($1,$2,$3,$4,$5::announcement_priority,$6,$7,$8,$9::assignment_role,
+		          CASE WHEN $8::timestamptz IS NULL OR $8::timestamptz <= NOW() THEN NOW() END)
 		  RETURNING id, event_id, committee_id, title, body,
-		            priority::text, created_by, created_at, expires_at
-		`, b.EventID, b.CommitteeID, b.Title, b.Body, pr, createdBy, b.ExpiresAt).
-			Scan(&a.ID, &a.EventID, &a.CommitteeID, &a.Title, &a.Body, &priorityStr, &a.CreatedBy, &a.CreatedAt, &a.ExpiresAt)
+		            priority::text, created_by, created_at, updated_at, expires_at,
+		            publish_at, (publish_at IS NOT NULL AND publish_at > NOW()), target_role::text
+		`, b.EventID, b.CommitteeID, b.Title, b.Body, pr, createdBy, b.ExpiresAt, b.PublishAt, role).
+			Scan(&a.ID, &a.EventID, &a.CommitteeID, &a.Title, &a.Body, &priorityStr, &a.CreatedBy, &a.CreatedAt, &a.UpdatedAt, &a.ExpiresAt, &a.PublishAt, &a.Scheduled, &targetRole)
 		if err != nil {
 			return err
 		}
 		a.Priority = models.AnnouncementPriority(priorityStr)
+		a.TargetRole = assignmentRolePtr(targetRole)
+
+		if a.TargetVolunteerIDs, err = replaceTargets(ctx, tx, a.ID, b.TargetVolunteerIDs); err != nil {
+			return err
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return err
+		}
+
+		// Scheduled announcements aren't visible yet, so don't push them now.
+		if !a.Scheduled && notifier != nil {
+			go publishAnnouncement(pool, notifier, a)
+		}
 		return c.Status(fiber.StatusCreated).JSON(a)
 	}
 }
 
 // PUT /announcements/:id  (guarded by admin)
+// "publish_at": null publishes a scheduled announcement now; moving publish_at into the future schedules
+// it again. Either way PublishDue pushes it when it becomes visible.
 func Update(pool *pgxpool.Pool) fiber.Handler {
 	return func(c *fiber.Ctx) error {
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
 		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
 		if err != nil || id <= 0 {
 			return fiber.NewError(fiber.StatusBadRequest, "invalid id")
@@ -308,55 +394,124 @@ func Update(pool *pgxpool.Pool) fiber.Handler {
 		if err := c.BodyParser(&b); err != nil {
 			return fiber.NewError(fiber.StatusBadRequest, "bad json")
 		}
+		// "committee_id": null clears the committee scope (event-wide); an absent key leaves it alone.
+		clearCommittee := b.CommitteeID == nil && jsonFieldIsNull(c.Body(), "committee_id")
+		// Likewise "publish_at": null publishes now.
+		clearPublishAt := b.PublishAt == nil && jsonFieldIsNull(c.Body(), "publish_at")
+
+		var v validation.Validator
+		if b.Title != nil && v.Required("title", *b.Title) {
+			v.MaxLen("title", strings.TrimSpace(*b.Title), models.MaxTitleLen)
+		}
+		if b.Body != nil && v.Required("body", *b.Body) {
+			v.MaxLen("body", strings.TrimSpace(*b.Body), models.MaxBodyLen)
+		}
+		var pr string
+		if b.Priority != nil {
+			pr, err = normPriority(string(*b.Priority))
+			v.Check(err == nil, "priority", validation.FieldInvalidValue)
+		}
+		var role *string
+		if b.TargetRole != nil {
+			role, err = normTargetRole(b.TargetRole)
+			v.Check(err == nil, "target_role", validation.FieldInvalidValue)
+		}
+		if err := v.Err(); err != nil {
+			return err
+		}
+
 		sets := []string{}
 		args := []any{}
 		i := 1
 
 		if b.Title != nil {
-			t := strings.TrimSpace(*b.Title)
-			if t == "" {
-				return fiber.NewError(fiber.StatusBadRequest, "title cannot be empty")
-			}
 			sets = append(sets, "title=$"+itoa(i))
-			args = append(args, t)
+			args = append(args, strings.TrimSpace(*b.Title))
 			i++
 		}
 		if b.Body != nil {
-			body := strings.TrimSpace(*b.Body)
-			if body == "" {
-				return fiber.NewError(fiber.StatusBadRequest, "body cannot be empty")
-			}
 			sets = append(sets, "body=$"+itoa(i))
-			args = append(args, body)
+			args = append(args, strings.TrimSpace(*b.Body))
 			i++
 		}
 		if b.Priority != nil {
 			sets = append(sets, "priority=$"+itoa(i)+`::announcement_priority`)
-			args = append(args, normPriority(string(*b.Priority)))
+			args = append(args, pr)
 			i++
 		}
 		if b.CommitteeID != nil {
+			var eventID int64
+			err := pool.QueryRow(ctx, `SELECT event_id FROM announcements WHERE id=$1`, id).Scan(&eventID)
+			if err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					return fiber.NewError(fiber.StatusNotFound, "not found")
+				}
+				return err
+			}
+			if err := checkCommitteeInEvent(ctx, pool, *b.CommitteeID, eventID); err != nil {
+				return err
+			}
 			sets = append(sets, "committee_id=$"+itoa(i))
 			args = append(args, *b.CommitteeID)
 			i++
+		} else if clearCommittee {
+			sets = append(sets, "committee_id=NULL")
 		}
 		if b.ExpiresAt != nil {
 			sets = append(sets, "expires_at=$"+itoa(i))
 			args = append(args, *b.ExpiresAt)
 			i++
 		}
-		if len(sets) == 0 {
+		if b.PublishAt != nil {
+			// Rescheduled into the future: it will need pushing again when it reappears
+			sets = append(sets, "publish_at=$"+itoa(i),
+				"notified_at=CASE WHEN $"+itoa(i)+"::timestamptz > NOW() THEN NULL ELSE notified_at END")
+			args = append(args, *b.PublishAt)
+			i++
+		} else if clearPublishAt {
+			sets = append(sets, "publish_at=NULL")
+		}
+		if b.TargetRole != nil {
+			sets = append(sets, "target_role=$"+itoa(i)+`::assignment_role`)
+			args = append(args, role)
+			i++
+		}
+		if len(sets) == 0 && b.TargetVolunteerIDs == nil {
 			return fiber.NewError(fiber.StatusBadRequest, "no fields to update")
 		}
-		args = append(args, id)
 
-		sqlQuery := `UPDATE announcements SET ` + strings.Join(sets, ", ") + ` WHERE id=$` + itoa(i)
-		cmd, err := pool.Exec(c.Context(), sqlQuery, args...)
+		tx, err := pool.Begin(ctx)
 		if err != nil {
 			return err
 		}
-		if cmd.RowsAffected() == 0 {
-			return fiber.NewError(fiber.StatusNotFound, "not found")
+		defer tx.Rollback(ctx)
+
+		if len(sets) > 0 {
+			args = append(args, id)
+			sqlQuery := `UPDATE announcements SET ` + strings.Join(sets, ", ") + ` WHERE id=$` + itoa(i)
+			cmd, err := tx.Exec(ctx, sqlQuery, args...)
+			if err != nil {
+				return err
+			}
+			if cmd.RowsAffected() == 0 {
+				return fiber.NewError(fiber.StatusNotFound, "not found")
+			}
+		} else {
+			var exists bool
+			if err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM announcements WHERE id=$1)`, id).Scan(&exists); err != nil {
+				return err
+			}
+			if !exists {
+				return fiber.NewError(fiber.StatusNotFound, "not found")
+			}
+		}
+		if b.TargetVolunteerIDs != nil {
+			if _, err := replaceTargets(ctx, tx, id, *b.TargetVolunteerIDs); err != nil {
+				return err
+			}
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return err
 		}
 		return c.SendStatus(fiber.StatusNoContent)
 	}
@@ -365,11 +520,14 @@ func Update(pool *pgxpool.Pool) fiber.Handler {
 // DELETE /announcements/:id  (guarded by admin)
 func Del(pool *pgxpool.Pool) fiber.Handler {
 	return func(c *fiber.Ctx) error {
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
 		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
 		if err != nil || id <= 0 {
 			return fiber.NewError(fiber.StatusBadRequest, "invalid id")
 		}
-		cmd, err := pool.Exec(c.Context(), `DELETE FROM announcements WHERE id=$1`, id)
+		cmd, err := pool.Exec(ctx, `DELETE FROM announcements WHERE id=$1`, id)
 		if err != nil {
 			return err
 		}
@@ -380,6 +538,347 @@ func Del(pool *pgxpool.Pool) fiber.Handler {
 	}
 }
 
+// Ack - POST /announcements/:id/ack (Volunteer)
+// Records that the logged-in volunteer has seen the announcement. Repeat acks are no-ops.
+// A scheduled announcement that isn't published yet is a 404, as in GET /announcements/me.
+func Ack(pool *pgxpool.Pool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil || id <= 0 {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid id")
+		}
+		volunteerID, err := mw.GetUserIDFromClaims(c)
+		if err != nil {
+			return fiber.NewError(fiber.StatusUnauthorized, "volunteer ID not found in token")
+		}
+
+		var relevant bool
+		err = pool.QueryRow(ctx, `
+			SELECT `+visibleToVolunteer("$2")+`
+			FROM announcements a WHERE a.id = $1 AND (a.publish_at IS NULL OR a.publish_at <= NOW())
+		`, id, volunteerID).Scan(&relevant)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fiber.NewError(fiber.StatusNotFound, "not found")
+			}
+			return err
+		}
+		if !relevant {
+			return fiber.NewError(fiber.StatusForbidden, "announcement is not addressed to your assignments")
+		}
+
+		var ackedAt time.Time
+		err = pool.QueryRow(ctx, `
+			INSERT INTO announcement_acks(announcement_id, volunteer_id) VALUES ($1,$2)
+			ON CONFLICT (announcement_id, volunteer_id) DO UPDATE SET acked_at = announcement_acks.acked_at
+			RETURNING acked_at
+		`, id, volunteerID).Scan(&ackedAt)
+		if err != nil {
+			return err
+		}
+		return c.JSON(fiber.Map{"announcement_id": id, "acked_at": ackedAt})
+	}
+}
+
+// ListAcks - GET /announcements/:id/acks (Admin)
+// Lists every volunteer the announcement targets with their ack status, plus counts.
+func ListAcks(pool *pgxpool.Pool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil || id <= 0 {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid id")
+		}
+
+		var exists bool
+		if err := pool.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM announcements WHERE id=$1)`, id).Scan(&exists); err != nil {
+			return err
+		}
+		if !exists {
+			return fiber.NewError(fiber.StatusNotFound, "not found")
+		}
+
+		rows, err := pool.Query(ctx, `
+			SELECT v.id, v.name, v.college_id, ak.acked_at
+			FROM announcements a
+			JOIN volunteers v ON `+visibleToVolunteer("v.id")+`
+			LEFT JOIN announcement_acks ak ON ak.announcement_id = a.id AND ak.volunteer_id = v.id
+			WHERE a.id = $1
+			ORDER BY (ak.acked_at IS NOT NULL), v.name
+		`, id)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		resp := models.AnnouncementAcksResponse{AnnouncementID: id, Volunteers: []models.AnnouncementAckStatus{}}
+		for rows.Next() {
+			var s models.AnnouncementAckStatus
+			if err := rows.Scan(&s.VolunteerID, &s.Name, &s.CollegeID, &s.AckedAt); err != nil {
+				return err
+			}
+			s.Acked = s.AckedAt != nil
+			if s.Acked {
+				resp.Acked++
+			}
+			resp.Volunteers = append(resp.Volunteers, s)
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		resp.Total = len(resp.Volunteers)
+		resp.Pending = resp.Total - resp.Acked
+		return c.JSON(resp)
+	}
+}
+
+// StreamForVolunteer (Volunteer) - GET /announcements/me/stream
+// Server-sent events: one "announcement" event (JSON) per newly published announcement addressed
+// to the logged-in volunteer (other notify kinds, e.g. "question_answered", use their kind as the event name), with a comment heartbeat every streamHeartbeat to keep proxies from
+// closing the connection. The stream ends when the client disconnects or the server shuts down.
+func StreamForVolunteer(notifier *notify.Dispatcher) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		volunteerID, err := mw.GetUserIDFromClaims(c)
+		if err != nil {
+			return fiber.NewError(fiber.StatusUnauthorized, "volunteer ID not found in token")
+		}
+
+		c.Set(fiber.HeaderContentType, "text/event-stream")
+		c.Set(fiber.HeaderCacheControl, "no-cache")
+		c.Set(fiber.HeaderConnection, "keep-alive")
+		c.Set("X-Accel-Buffering", "no") // Disable nginx response buffering
+
+		events, unsubscribe := notifier.Hub.Subscribe(volunteerID)
+		done := c.Context().Done()
+
+		c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+			defer unsubscribe()
+			heartbeat := time.NewTicker(streamHeartbeat)
+			defer heartbeat.Stop()
+
+			fmt.Fprint(w, ": connected\n\n")
+			if err := w.Flush(); err != nil {
+				return
+			}
+			for {
+				select {
+				case <-done:
+					return
+				case m, ok := <-events:
+					if !ok {
+						return
+					}
+					data, err := json.Marshal(m)
+					if err != nil {
+						log.Printf("sse: failed to encode %s: %v", m.ID(), err)
+						continue
+					}
+					fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", m.ID(), m.Kind, data)
+				case <-heartbeat.C:
+					fmt.Fprint(w, ": ping\n\n")
+				}
+				// A failed flush means the client went away.
+				if err := w.Flush(); err != nil {
+					return
+				}
+			}
+		})
+		return nil
+	}
+}
+
+// streamHeartbeat is how often StreamForVolunteer writes a keep-alive comment.
+const streamHeartbeat = 20 * time.Second
+
+// publishAnnouncement resolves the volunteers an announcement targets, pushes it to their live
+// streams and, for urgent announcements, hands it to the external notifier.
+// Targeting mirrors ListForVolunteer (see visibleToVolunteer).
+func publishAnnouncement(pool *pgxpool.Pool, notifier *notify.Dispatcher, a models.Announcement) {
+	urgent := a.Priority == models.PriorityUrgent
+	if !urgent && notifier.Hub.Len() == 0 {
+		return // Nobody to tell
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	recipients, err := announcementRecipients(ctx, pool, a.ID)
+	if err != nil {
+		log.Printf("notify: failed to resolve recipients for announcement %d: %v", a.ID, err)
+		return
+	}
+	m := notify.Message{
+		Kind:           notify.KindAnnouncement,
+		AnnouncementID: a.ID,
+		EventID:        a.EventID,
+		CommitteeID:    a.CommitteeID,
+		Title:          a.Title,
+		Body:           a.Body,
+		Priority:       string(a.Priority),
+	}
+	notifier.Publish(recipients, m)
+	if urgent {
+		notifier.Dispatch(recipients, m)
+	}
+}
+
+// PublishDue pushes scheduled announcements whose publish_at has passed, the way Create pushes
+// immediate ones. Each is claimed by setting notified_at in the statement that selects it, so it goes
+// out once even with several instances polling. Announcements that expired before they were due are
+// claimed but not sent.
+func PublishDue(ctx context.Context, pool *pgxpool.Pool, notifier *notify.Dispatcher) error {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	rows, err := pool.Query(ctx, `
+		UPDATE announcements a
+		SET notified_at = NOW()
+		WHERE a.notified_at IS NULL AND (a.publish_at IS NULL OR a.publish_at <= NOW())
+		RETURNING a.id, a.event_id, a.committee_id, a.title, a.body, a.priority::text,
+		          (a.expires_at IS NOT NULL AND a.expires_at <= NOW())
+	`)
+	if err != nil {
+		return err
+	}
+	due := []models.Announcement{}
+	for rows.Next() {
+		var a models.Announcement
+		var priorityStr string
+		var expired bool
+		if err := rows.Scan(&a.ID, &a.EventID, &a.CommitteeID, &a.Title, &a.Body, &priorityStr, &expired); err != nil {
+			rows.Close()
+			return err
+		}
+		if !expired {
+			a.Priority = models.AnnouncementPriority(priorityStr)
+			due = append(due, a)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, a := range due {
+		publishAnnouncement(pool, notifier, a)
+	}
+	if len(due) > 0 {
+		log.Printf("announcements: published %d scheduled announcement(s)", len(due))
+	}
+	return nil
+}
+
+// RunScheduled calls PublishDue every interval until ctx is cancelled.
+func RunScheduled(ctx context.Context, pool *pgxpool.Pool, notifier *notify.Dispatcher, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := PublishDue(ctx, pool, notifier); err != nil {
+			log.Printf("announcements: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// announcementRecipients returns the distinct volunteers an announcement is visible to.
+func announcementRecipients(ctx context.Context, pool *pgxpool.Pool, announcementID int64) ([]notify.Recipient, error) {
+	rows, err := pool.Query(ctx, `
+		SELECT v.id, v.name, v.phone, v.email, v.sms_opt_out
+		FROM announcements a
+		JOIN volunteers v ON `+visibleToVolunteer("v.id")+`
+		WHERE a.id = $1
+	`, announcementID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []notify.Recipient{}
+	for rows.Next() {
+		var r notify.Recipient
+		if err := rows.Scan(&r.VolunteerID, &r.Name, &r.Phone, &r.Email, &r.SMSOptOut); err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+// visibleToVolunteer returns a SQL predicate over announcement alias "a" that holds when the
+// announcement is addressed to the volunteer identified by volExpr (a placeholder or column):
+//   - untargeted: the volunteer is assigned to the event (or to the committee, if set)
+//   - target_role: as above, but only through assignments with that role
+//   - announcement_targets: the volunteer is listed explicitly
+func visibleToVolunteer(volExpr string) string {
+	scope := `((a.committee_id IS NULL AND va.event_id = a.event_id) OR va.committee_id = a.committee_id)`
+	return `(
+		(a.target_role IS NULL
+		 AND NOT EXISTS (SELECT 1 FROM announcement_targets t WHERE t.announcement_id = a.id)
+		 AND EXISTS (SELECT 1 FROM volunteer_assignments va WHERE va.volunteer_id = ` + volExpr + ` AND ` + scope + `))
+		OR (a.target_role IS NOT NULL
+		 AND EXISTS (SELECT 1 FROM volunteer_assignments va WHERE va.volunteer_id = ` + volExpr + ` AND va.role = a.target_role AND ` + scope + `))
+		OR EXISTS (SELECT 1 FROM announcement_targets t WHERE t.announcement_id = a.id AND t.volunteer_id = ` + volExpr + `)
+	)`
+}
+
+// checkCommitteeInEvent returns 422 unless committeeID exists and belongs to eventID.
+func checkCommitteeInEvent(ctx context.Context, pool *pgxpool.Pool, committeeID, eventID int64) error {
+	var ok bool
+	err := pool.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM committees WHERE id=$1 AND event_id=$2)`, committeeID, eventID).Scan(&ok)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fiber.NewError(fiber.StatusUnprocessableEntity, "committee_id does not belong to event_id")
+	}
+	return nil
+}
+
+// replaceTargets sets the explicit recipients of an announcement to ids (deduplicated) and returns them.
+func replaceTargets(ctx context.Context, tx pgx.Tx, announcementID int64, ids []int64) ([]int64, error) {
+	if _, err := tx.Exec(ctx, `DELETE FROM announcement_targets WHERE announcement_id=$1`, announcementID); err != nil {
+		return nil, err
+	}
+	out := []int64{}
+	seen := map[int64]struct{}{}
+	for _, id := range ids {
+		if id <= 0 {
+			return nil, fiber.NewError(fiber.StatusBadRequest, "target_volunteer_ids must be positive ids")
+		}
+		if _, ok := seen[id]; !ok {
+			seen[id] = struct{}{}
+			out = append(out, id)
+		}
+	}
+	if len(out) == 0 {
+		return out, nil
+	}
+	_, err := tx.Exec(ctx, `
+		INSERT INTO announcement_targets(announcement_id, volunteer_id)
+		SELECT $1, unnest($2::bigint[])
+	`, announcementID, out)
+	return out, err
+}
+
+// jsonFieldIsNull reports whether body is a JSON object in which key is present with an explicit null.
+func jsonFieldIsNull(body []byte, key string) bool {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return false
+	}
+	v, ok := raw[key]
+	return ok && string(bytes.TrimSpace(v)) == "null"
+}
+
 // ---- helpers ----
 func clampInt(v, lo, hi int) int {
 	if v < lo {
@@ -397,11 +896,39 @@ func maxInt(a, b int) int {
 	return b
 }
 func itoa(i int) string { return strconv.FormatInt(int64(i), 10) }
-func normPriority(p string) string {
-	switch strings.ToLower(strings.TrimSpace(p)) {
+
+// normTargetRole validates an optional target role; nil or "" means no role filter.
+func normTargetRole(r *models.AssignmentRole) (*string, error) {
+	if r == nil {
+		return nil, nil
+	}
+	s := strings.ToLower(strings.TrimSpace(string(*r)))
+	switch models.AssignmentRole(s) {
+	case "":
+		return nil, nil
+	case models.RoleVolunteer, models.RoleLead, models.RoleSupport:
+		return &s, nil
+	default:
+		return nil, fiber.NewError(fiber.StatusBadRequest, "target_role must be one of volunteer, lead, support")
+	}
+}
+func assignmentRolePtr(s *string) *models.AssignmentRole {
+	if s == nil {
+		return nil
+	}
+	r := models.AssignmentRole(*s)
+	return &r
+}
+
+// normPriority parses an announcement priority; empty means "normal".
+// Unknown values are a 422 listing the accepted ones instead of quietly becoming "normal".
+func normPriority(p string) (string, error) {
+	switch v := strings.ToLower(strings.TrimSpace(p)); v {
+	case "":
+		return "normal", nil
 	case "urgent", "high", "normal", "low":
-		return strings.ToLower(strings.TrimSpace(p))
+		return v, nil
 	default:
-		return "normal"
+		return "", fiber.NewError(fiber.StatusUnprocessableEntity, fmt.Sprintf("invalid priority %q (accepted values: urgent, high, normal, low)", p))
 	}
 }
@@ -0,0 +1,355 @@
+package announcements
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"Seva-app-backend/db/dbtest"
+	mw "Seva-app-backend/middleware"
+	"Seva-app-backend/models"
+	"Seva-app-backend/notify"
+)
+
+// recorder is a notify.Notifier that hands every delivery to the test.
+type recorder chan notify.Message
+
+func (r recorder) Notify(_ context.Context, _ notify.Recipient, m notify.Message) error {
+	r <- m
+	return nil
+}
+
+// expect waits for n deliveries and then checks nothing else arrives.
+func (r recorder) expect(t *testing.T, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		select {
+		case <-r:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("got %d notification(s), want %d", i, n)
+		}
+	}
+	select {
+	case m := <-r:
+		t.Fatalf("unexpected extra notification for announcement %d", m.AnnouncementID)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+// testApp mounts the announcement handlers with the auth guards replaced by fixed claims.
+func testApp(pool *pgxpool.Pool, claims *mw.Claims, notifier *notify.Dispatcher) *fiber.App {
+	app := dbtest.App()
+	Register(app.Group("/announcements"), pool, dbtest.As(claims.Sub, claims.Role), dbtest.Pass, dbtest.Pass, notifier)
+	return app
+}
+
+type fixture struct {
+	pool        *pgxpool.Pool
+	app         *fiber.App
+	sent        recorder
+	notifier    *notify.Dispatcher
+	eventID     int64
+	committeeID int64
+}
+
+// newFixture seeds an admin and an event with one committee and one assigned volunteer.
+func newFixture(t *testing.T) *fixture {
+	pool := dbtest.Migrated(t)
+	f := &fixture{pool: pool, sent: make(recorder, 16)}
+	f.notifier = notify.NewDispatcher(f.sent, 1, 16)
+	facultyID := dbtest.ID(t, pool, `INSERT INTO faculty (name, role) VALUES ('Admin', 'admin') RETURNING id`)
+	f.eventID = dbtest.ID(t, pool, `INSERT INTO events (name) VALUES ('Test event') RETURNING id`)
+	f.committeeID = dbtest.ID(t, pool, `INSERT INTO committees (event_id, name) VALUES ($1, 'Food') RETURNING id`, f.eventID)
+	volunteerID := dbtest.ID(t, pool, `INSERT INTO volunteers (name, phone) VALUES ('Asha', '+910000000000') RETURNING id`)
+	dbtest.Exec(t, pool, `INSERT INTO volunteer_assignments (event_id, committee_id, volunteer_id) VALUES ($1, $2, $3)`,
+		f.eventID, f.committeeID, volunteerID)
+	f.app = testApp(pool, &mw.Claims{Sub: facultyID, Role: models.UserRoleAdmin}, f.notifier)
+	return f
+}
+
+// create posts an announcement with the given extra JSON fields and returns its id.
+func (f *fixture) create(t *testing.T, extra string) int64 {
+	t.Helper()
+	body := `{"event_id": ` + strconv.FormatInt(f.eventID, 10) + `, "title": "Gate change", "body": "Use gate 2"` + extra + `}`
+	code, res := dbtest.Do(t, f.app, "POST", "/announcements", body)
+	if code != fiber.StatusCreated {
+		t.Fatalf("POST /announcements = %d %s", code, res)
+	}
+	var a models.Announcement
+	if err := json.Unmarshal(res, &a); err != nil {
+		t.Fatal(err)
+	}
+	return a.ID
+}
+
+func TestScheduledUrgentAnnouncementIsPushedOnceDue(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	publishAt := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	id := f.create(t, `, "priority": "urgent", "publish_at": "`+publishAt+`"`)
+
+	// Not due yet
+	if err := PublishDue(ctx, f.pool, f.notifier); err != nil {
+		t.Fatal(err)
+	}
+	f.sent.expect(t, 0)
+
+	// Time passes
+	dbtest.Exec(t, f.pool, `UPDATE announcements SET publish_at = NOW() - INTERVAL '1 minute' WHERE id = $1`, id)
+	if err := PublishDue(ctx, f.pool, f.notifier); err != nil {
+		t.Fatal(err)
+	}
+	f.sent.expect(t, 1)
+
+	// Claimed: the next poll doesn't send it again
+	if err := PublishDue(ctx, f.pool, f.notifier); err != nil {
+		t.Fatal(err)
+	}
+	f.sent.expect(t, 0)
+}
+
+func TestUpdatePublishAtNullPublishesNow(t *testing.T) {
+	f := newFixture(t)
+	publishAt := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	id := f.create(t, `, "priority": "urgent", "publish_at": "`+publishAt+`"`)
+	path := "/announcements/" + strconv.FormatInt(id, 10)
+
+	if code, res := dbtest.Do(t, f.app, "PUT", path, `{"publish_at": null}`); code != fiber.StatusNoContent {
+		t.Fatalf("PUT publish_at null = %d %s", code, res)
+	}
+	var cleared bool
+	if err := f.pool.QueryRow(context.Background(), `SELECT publish_at IS NULL FROM announcements WHERE id = $1`, id).
+		Scan(&cleared); err != nil {
+		t.Fatal(err)
+	}
+	if !cleared {
+		t.Fatal("publish_at still set after PUT {\"publish_at\": null}")
+	}
+
+	if err := PublishDue(context.Background(), f.pool, f.notifier); err != nil {
+		t.Fatal(err)
+	}
+	f.sent.expect(t, 1)
+}
+
+func TestImmediateUrgentAnnouncementIsNotPushedTwice(t *testing.T) {
+	f := newFixture(t)
+	f.create(t, `, "priority": "urgent"`)
+	f.sent.expect(t, 1) // Create pushes it
+
+	if err := PublishDue(context.Background(), f.pool, f.notifier); err != nil {
+		t.Fatal(err)
+	}
+	f.sent.expect(t, 0)
+}
+
+func TestAckScheduledAnnouncementIsNotFound(t *testing.T) {
+	f := newFixture(t)
+	publishAt := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	id := f.create(t, `, "publish_at": "`+publishAt+`"`)
+	volunteerID := dbtest.ID(t, f.pool, `SELECT id FROM volunteers WHERE name = 'Asha'`)
+	volunteerApp := testApp(f.pool, &mw.Claims{Sub: volunteerID, Role: models.UserRoleVolunteer}, f.notifier)
+	path := "/announcements/" + strconv.FormatInt(id, 10) + "/ack"
+
+	if code, res := dbtest.Do(t, volunteerApp, "POST", path, ""); code != fiber.StatusNotFound {
+		t.Fatalf("ack before publish_at = %d %s, want 404", code, res)
+	}
+	dbtest.Exec(t, f.pool, `UPDATE announcements SET publish_at = NOW() - INTERVAL '1 minute' WHERE id = $1`, id)
+	if code, res := dbtest.Do(t, volunteerApp, "POST", path, ""); code != fiber.StatusOK {
+		t.Fatalf("ack once published = %d %s", code, res)
+	}
+}
+
+func TestUpdateSetsAndClearsCommittee(t *testing.T) {
+	f := newFixture(t)
+	id := f.create(t, "")
+	path := "/announcements/" + strconv.FormatInt(id, 10)
+	committeeOf := func() *int64 {
+		t.Helper()
+		var committeeID *int64
+		if err := f.pool.QueryRow(context.Background(), `SELECT committee_id FROM announcements WHERE id = $1`, id).
+			Scan(&committeeID); err != nil {
+			t.Fatal(err)
+		}
+		return committeeID
+	}
+
+	body := `{"committee_id": ` + strconv.FormatInt(f.committeeID, 10) + `}`
+	if code, res := dbtest.Do(t, f.app, "PUT", path, body); code != fiber.StatusNoContent {
+		t.Fatalf("PUT committee_id = %d %s", code, res)
+	}
+	if got := committeeOf(); got == nil || *got != f.committeeID {
+		t.Fatalf("committee_id = %v, want %d", got, f.committeeID)
+	}
+
+	// An absent key leaves it alone
+	if code, res := dbtest.Do(t, f.app, "PUT", path, `{"title": "Gate change (updated)"}`); code != fiber.StatusNoContent {
+		t.Fatalf("PUT title = %d %s", code, res)
+	}
+	if got := committeeOf(); got == nil || *got != f.committeeID {
+		t.Fatalf("committee_id after a title update = %v, want %d", got, f.committeeID)
+	}
+
+	// Explicit null makes it event-wide
+	if code, res := dbtest.Do(t, f.app, "PUT", path, `{"committee_id": null}`); code != fiber.StatusNoContent {
+		t.Fatalf("PUT committee_id null = %d %s", code, res)
+	}
+	if got := committeeOf(); got != nil {
+		t.Fatalf("committee_id = %d, want NULL", *got)
+	}
+}
+
+func TestUpdateRejectsCommitteeFromAnotherEvent(t *testing.T) {
+	f := newFixture(t)
+	id := f.create(t, "")
+	otherEventID := dbtest.ID(t, f.pool, `INSERT INTO events (name) VALUES ('Other event') RETURNING id`)
+	otherCommitteeID := dbtest.ID(t, f.pool, `INSERT INTO committees (event_id, name) VALUES ($1, 'Food') RETURNING id`, otherEventID)
+
+	body := `{"committee_id": ` + strconv.FormatInt(otherCommitteeID, 10) + `}`
+	if code, _ := dbtest.Do(t, f.app, "PUT", "/announcements/"+strconv.FormatInt(id, 10), body); code != fiber.StatusUnprocessableEntity {
+		t.Fatalf("PUT committee_id from another event = %d, want 422", code)
+	}
+}
+
+// listForVolunteerTwoStep is the feed as ListForVolunteer computed it before visibility moved into one
+// query: the volunteer's events and committees first, deduplicated in Go, then the announcements for them.
+func listForVolunteerTwoStep(t *testing.T, pool *pgxpool.Pool, volunteerID int64, activeOnly bool) []int64 {
+	t.Helper()
+	ctx := context.Background()
+	rows, err := pool.Query(ctx, `SELECT event_id, committee_id FROM volunteer_assignments WHERE volunteer_id = $1`, volunteerID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	events, committees := map[int64]bool{}, map[int64]bool{}
+	for rows.Next() {
+		var e, c int64
+		if err := rows.Scan(&e, &c); err != nil {
+			t.Fatal(err)
+		}
+		events[e], committees[c] = true, true
+	}
+	rows.Close()
+	keys := func(m map[int64]bool) []int64 {
+		out := make([]int64, 0, len(m))
+		for k := range m {
+			out = append(out, k)
+		}
+		sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+		return out
+	}
+
+	active := ""
+	if activeOnly {
+		active = "AND (a.expires_at IS NULL OR a.expires_at > NOW())"
+	}
+	rows, err = pool.Query(ctx, `
+		SELECT a.id FROM announcements a
+		WHERE ((a.committee_id IS NULL AND a.event_id = ANY($1)) OR a.committee_id = ANY($2))
+		  AND (a.publish_at IS NULL OR a.publish_at <= NOW()) `+active+`
+		ORDER BY CASE a.priority WHEN 'urgent' THEN 1 WHEN 'high' THEN 2 WHEN 'normal' THEN 3 ELSE 4 END,
+		         a.created_at DESC`, keys(events), keys(committees))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	ids := []int64{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func TestListForVolunteerMatchesTwoStepQuery(t *testing.T) {
+	f := newFixture(t)
+	volunteerID := dbtest.ID(t, f.pool, `INSERT INTO volunteers (name) VALUES ('Meera') RETURNING id`)
+	otherCommitteeID := dbtest.ID(t, f.pool, `INSERT INTO committees (event_id, name) VALUES ($1, 'Stage') RETURNING id`, f.eventID)
+	notMineID := dbtest.ID(t, f.pool, `INSERT INTO committees (event_id, name) VALUES ($1, 'Parking') RETURNING id`, f.eventID)
+	event2ID := dbtest.ID(t, f.pool, `INSERT INTO events (name) VALUES ('Second event') RETURNING id`)
+	event2CommitteeID := dbtest.ID(t, f.pool, `INSERT INTO committees (event_id, name) VALUES ($1, 'Food') RETURNING id`, event2ID)
+	event3ID := dbtest.ID(t, f.pool, `INSERT INTO events (name) VALUES ('Third event') RETURNING id`)
+	// Several assignments in one event, so the two-step version has duplicates to drop
+	for _, a := range [][2]int64{{f.eventID, f.committeeID}, {f.eventID, otherCommitteeID}, {event2ID, event2CommitteeID}} {
+		dbtest.Exec(t, f.pool, `INSERT INTO volunteer_assignments (event_id, committee_id, volunteer_id) VALUES ($1, $2, $3)`,
+			a[0], a[1], volunteerID)
+	}
+
+	n := 0
+	announce := func(eventID int64, committeeID *int64, priority, extra string) {
+		n++
+		dbtest.Exec(t, f.pool, `
+			INSERT INTO announcements (event_id, committee_id, title, body, priority, created_at, expires_at, publish_at)
+			VALUES ($1, $2, 'T', 'B', $3::announcement_priority, NOW() - make_interval(mins => $4), `+extra+`)`,
+			eventID, committeeID, priority, n)
+	}
+	const visible = "NULL, NULL"
+	announce(f.eventID, nil, "normal", visible)                                         // event-wide
+	announce(f.eventID, &f.committeeID, "urgent", visible)                              // my committee
+	announce(f.eventID, &otherCommitteeID, "high", visible)                             // my other committee
+	announce(f.eventID, &notMineID, "urgent", visible)                                  // same event, not my committee
+	announce(event2ID, nil, "high", visible)                                            // second event
+	announce(event3ID, nil, "urgent", visible)                                          // event I'm not in
+	announce(f.eventID, nil, "low", "NOW() - INTERVAL '1 hour', NULL")                  // expired
+	announce(event2ID, &event2CommitteeID, "normal", "NULL, NOW() + INTERVAL '1 hour'") // scheduled
+	announce(event2ID, &event2CommitteeID, "normal", "NOW() + INTERVAL '1 day', NULL")  // expires later
+	announce(f.eventID, &f.committeeID, "low", "NULL, NOW() - INTERVAL '1 minute'")     // scheduled, now due
+
+	app := testApp(f.pool, &mw.Claims{Sub: volunteerID, Role: models.UserRoleVolunteer}, f.notifier)
+	for _, activeOnly := range []bool{true, false} {
+		code, body := dbtest.Do(t, app, "GET", "/announcements/me?active_only="+strconv.FormatBool(activeOnly), "")
+		if code != fiber.StatusOK {
+			t.Fatalf("GET /announcements/me = %d %s", code, body)
+		}
+		var list []models.Announcement
+		if err := json.Unmarshal(body, &list); err != nil {
+			t.Fatal(err)
+		}
+		got := make([]int64, len(list))
+		for i, a := range list {
+			got[i] = a.ID
+		}
+		want := listForVolunteerTwoStep(t, f.pool, volunteerID, activeOnly)
+		if len(want) == 0 {
+			t.Fatal("fixture makes no announcements visible")
+		}
+		if idList(got) != idList(want) {
+			t.Fatalf("active_only=%v: got %v, want %v", activeOnly, got, want)
+		}
+	}
+}
+
+func idList(ids []int64) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.FormatInt(id, 10)
+	}
+	return strings.Join(parts, ",")
+}
+
+func TestEmptyListsAreArrays(t *testing.T) {
+	f := newFixture(t)
+	volunteerID := dbtest.ID(t, f.pool, `INSERT INTO volunteers (name) VALUES ('Meera') RETURNING id`)
+	volunteerApp := testApp(f.pool, &mw.Claims{Sub: volunteerID, Role: models.UserRoleVolunteer}, f.notifier)
+	for _, tc := range []struct {
+		app  *fiber.App
+		path string
+	}{
+		{f.app, "/announcements?event_id=" + strconv.FormatInt(f.eventID, 10)},
+		{volunteerApp, "/announcements/me"},
+	} {
+		code, body := dbtest.Do(t, tc.app, "GET", tc.path, "")
+		if code != fiber.StatusOK || strings.TrimSpace(string(body)) != "[]" {
+			t.Errorf("GET %s = %d %s, want 200 []", tc.path, code, body)
+		}
+	}
+}
@@ -1,45 +1,73 @@
 package attendance
 
 import (
+	"context"
 	"database/sql"
+	"encoding/base64"
 	"encoding/csv"
 	"errors"
 	"fmt"
+	"io"
 	"log" // Added for logging errors in CSV export
+	"math"
+	"net/http"
+	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
+	"Seva-app-backend/db"
+	"Seva-app-backend/handlers/audit"
+	hShifts "Seva-app-backend/handlers/shifts"
 	mw "Seva-app-backend/middleware"
 	"Seva-app-backend/models"
 )
 
 // Register mounts attendance routes under /attendance
-func Register(g fiber.Router, pool *pgxpool.Pool, jwtGuard fiber.Handler, requireFaculty fiber.Handler, requireVolunteer fiber.Handler) {
+// idempotent is the Idempotency-Key middleware applied to check-in so client retries don't double-insert.
+func Register(g fiber.Router, pool *pgxpool.Pool, jwtGuard fiber.Handler, requireFaculty fiber.Handler, requireVolunteer fiber.Handler, idempotent fiber.Handler) {
 	// Volunteer actions
-	g.Post("/checkin", jwtGuard, requireVolunteer, CheckIn(pool))
+	// The photo may arrive base64-encoded in JSON, which is 4/3 its size
+	checkInLimit := mw.MaxBodySize(checkInPhotoMaxBytes()*4/3 + mw.MultipartOverhead)
+	g.Post("/checkin", jwtGuard, requireVolunteer, checkInLimit, idempotent, CheckIn(pool))
 	g.Post("/checkout", jwtGuard, requireVolunteer, CheckOut(pool))
 
-	// Faculty/Admin actions (no approval needed)
-	g.Get("/shifts-without-checkin", jwtGuard, requireFaculty, ListShiftsWithoutCheckIn(pool))
-	g.Get("/active-in-shift", jwtGuard, requireFaculty, ListActiveCheckinsInShift(pool))         // NEW
-	g.Get("/active-in-committee", jwtGuard, requireFaculty, ListActiveCheckinsInCommittee(pool)) // NEW
-	g.Post("/checkout-shift", jwtGuard, requireFaculty, CheckoutShift(pool))                     // NEW
-
-	g.Get("/assignments-status", jwtGuard, requireFaculty, ListAssignmentsWithCheckinStatus(pool)) // <--- NEW ROUTE
+	// Faculty/Admin actions (no approval needed); event-scoped faculty must pass an event_id they hold
+	eventScope := mw.RequireEventScope(pool, mw.EventIDFromQuery)
+	g.Get("/shifts-without-checkin", jwtGuard, requireFaculty, eventScope, ListShiftsWithoutCheckIn(pool))
+	g.Get("/shifts-without-checkin/export_csv", jwtGuard, requireFaculty, eventScope, ExportShiftsWithoutCheckInCSV(pool))
+	g.Get("/active-in-shift", jwtGuard, requireFaculty, eventScope, ListActiveCheckinsInShift(pool))         // NEW
+	g.Get("/active-in-committee", jwtGuard, requireFaculty, eventScope, ListActiveCheckinsInCommittee(pool)) // NEW
+	g.Post("/checkout-shift", jwtGuard, requireFaculty, eventScope, CheckoutShift(pool))                     // NEW
+	g.Get("/live-count", jwtGuard, requireFaculty, eventScope, LiveCount(pool))
+	g.Get("/by-hour", jwtGuard, requireFaculty, eventScope, ByHour(pool))
+	g.Get("/by-committee", jwtGuard, requireFaculty, eventScope, ByCommittee(pool))
+	g.Post("/manual", jwtGuard, requireFaculty, mw.RequireEventScope(pool, eventOfAssignmentInBody(pool)), CreateManualAttendance(pool))
+
+	g.Get("/assignments-status", jwtGuard, requireFaculty, eventScope, ListAssignmentsWithCheckinStatus(pool)) // <--- NEW ROUTE
 	// General attendance list and export for Faculty/Admin
-	g.Get("/", jwtGuard, requireFaculty, ListAllAttendance(pool))
-	g.Get("/export_csv", jwtGuard, requireFaculty, ExportAttendanceCSV(pool))
+	g.Get("/", jwtGuard, requireFaculty, eventScope, ListAllAttendance(pool))
+	g.Get("/export_csv", jwtGuard, requireFaculty, eventScope, ExportAttendanceCSV(pool))
+	attendanceScope := mw.RequireEventScope(pool, eventOfAttendance(pool))
+	g.Get("/:id/photo", jwtGuard, requireFaculty, attendanceScope, GetCheckInPhoto(pool))
+	g.Put("/:id", jwtGuard, requireFaculty, attendanceScope, UpdateAttendance(pool))
+	g.Delete("/:id", jwtGuard, requireFaculty, attendanceScope, DeleteAttendance(pool))
 }
 
-// POST /attendance/checkin  {assignment_id, lat?, lng?, time?}
-// A volunteer can only check-in for their own assignments.
+// POST /attendance/checkin  {assignment_id, lat?, lng?, time?, photo_base64?} (JSON or multipart with a "photo" file)
+// A volunteer can only check-in for their own assignments; anyone else's is a 404.
+// Events with require_checkin_photo reject check-ins without a photo (422).
 func CheckIn(pool *pgxpool.Pool) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		_, err := mw.GetUserIDFromClaims(c)
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
+		volunteerID, err := mw.GetUserIDFromClaims(c)
 		if err != nil {
 			return fiber.NewError(fiber.StatusUnauthorized, "Volunteer ID not found in token")
 		}
@@ -62,38 +90,544 @@ func CheckIn(pool *pgxpool.Pool) fiber.Handler {
 			ts = t
 		}
 
-		// Ensure the assignment exists AND belongs to the logged-in volunteer
-		// Ensure the assignment exists
-		var assignmentExists bool
-		if err := pool.QueryRow(c.Context(),
-			`SELECT EXISTS(SELECT 1 FROM volunteer_assignments WHERE id=$1)`, b.AssignmentID).Scan(&assignmentExists); err != nil {
+		photo, photoType, err := readCheckInPhoto(c, b.PhotoBase64)
+		if err != nil {
 			return err
 		}
-		if !assignmentExists {
-			return fiber.NewError(fiber.StatusBadRequest, "Invalid assignment_id")
+
+		tx, err := pool.Begin(ctx)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback(ctx)
+
+		// Ensure the assignment exists AND belongs to the logged-in volunteer, and whether its event
+		// requires a photo. The row lock serializes concurrent check-ins for the same assignment.
+		var photoRequired bool
+		err = tx.QueryRow(ctx, `
+			SELECT e.require_checkin_photo
+			FROM volunteer_assignments va
+			JOIN events e ON e.id = va.event_id
+			WHERE va.id = $1 AND va.volunteer_id = $2
+			FOR UPDATE OF va
+		`, b.AssignmentID, volunteerID).Scan(&photoRequired)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fiber.NewError(fiber.StatusNotFound, "Assignment not found")
+			}
+			return err
+		}
+		if photoRequired && photo == nil {
+			return fiber.NewError(fiber.StatusUnprocessableEntity, "A check-in photo is required for this event")
 		}
 
 		// Prevent duplicate check-ins for the same assignment on the same day without checking out.
-		var existingAttendanceID int64
-		err = pool.QueryRow(c.Context(),
-			`SELECT id FROM attendance WHERE assignment_id=$1 AND check_out_time IS NULL AND DATE(check_in_time) = DATE($2)`,
-			b.AssignmentID, ts).Scan(&existingAttendanceID)
-		if err == nil {
-			return fiber.NewError(fiber.StatusConflict, "Already checked in for this assignment and not checked out.")
-		}
-		if !errors.Is(err, sql.ErrNoRows) {
-			return err // Actual DB error
+		if existingID, found, err := activeCheckIn(ctx, tx, b.AssignmentID, ts); err != nil {
+			return err
+		} else if found {
+			return alreadyCheckedIn(c, existingID)
 		}
 
 		var newAttendanceID int64
-		err = pool.QueryRow(c.Context(),
+		err = tx.QueryRow(ctx,
 			`INSERT INTO attendance(assignment_id, check_in_time, lat, lng)
 			 VALUES ($1,$2,$3,$4) RETURNING id`,
 			b.AssignmentID, ts, b.Lat, b.Lng).Scan(&newAttendanceID)
 		if err != nil {
 			return err
 		}
-		return c.Status(fiber.StatusCreated).JSON(fiber.Map{"status": "checked_in", "attendance_id": newAttendanceID})
+		if photo != nil {
+			if _, err := tx.Exec(ctx,
+				`INSERT INTO attendance_photos(attendance_id, content_type, data) VALUES ($1,$2,$3)`,
+				newAttendanceID, photoType, photo); err != nil {
+				return err
+			}
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return err
+		}
+		return c.Status(fiber.StatusCreated).JSON(fiber.Map{"status": "checked_in", "attendance_id": newAttendanceID, "has_photo": photo != nil})
+	}
+}
+
+// LiveCount - GET /attendance/live-count?event_id=&since=<minutes> (Faculty/Admin)
+// How many volunteers are on site right now (open check-ins), in total and per committee.
+// With since=N, also counts check-ins made in the last N minutes. One aggregate query, safe to poll.
+func LiveCount(pool *pgxpool.Pool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
+		eventID, err := strconv.ParseInt(c.Query("event_id"), 10, 64)
+		if err != nil || eventID <= 0 {
+			return fiber.NewError(fiber.StatusBadRequest, "event_id is required")
+		}
+		sinceMinutes := c.QueryInt("since", 0)
+		if sinceMinutes < 0 || sinceMinutes > 24*60 {
+			return fiber.NewError(fiber.StatusBadRequest, "since must be between 0 and 1440 minutes")
+		}
+		since := time.Now().Add(-time.Duration(sinceMinutes) * time.Minute)
+
+		rows, err := pool.Query(ctx, `
+			SELECT c.id, c.name,
+			       COUNT(DISTINCT va.volunteer_id) AS active,
+			       COUNT(*) FILTER (WHERE a.check_in_time >= $2) AS recent
+			FROM attendance a
+			JOIN volunteer_assignments va ON va.id = a.assignment_id
+			JOIN committees c ON c.id = va.committee_id
+			WHERE va.event_id = $1 AND a.check_out_time IS NULL
+			GROUP BY c.id, c.name
+			ORDER BY c.name
+		`, eventID, since)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		type committeeCount struct {
+			CommitteeID   int64  `json:"committee_id"`
+			CommitteeName string `json:"committee_name"`
+			Active        int64  `json:"active"`
+			Arrivals      *int64 `json:"arrivals,omitempty"` // Only with ?since=
+		}
+		committees := []committeeCount{}
+		var totalActive, totalArrivals int64
+		for rows.Next() {
+			var cc committeeCount
+			var recent int64
+			if err := rows.Scan(&cc.CommitteeID, &cc.CommitteeName, &cc.Active, &recent); err != nil {
+				return err
+			}
+			if sinceMinutes > 0 {
+				cc.Arrivals = &recent
+			}
+			totalActive += cc.Active
+			totalArrivals += recent
+			committees = append(committees, cc)
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		// A volunteer checked in to two committees is counted once overall.
+		if len(committees) > 1 {
+			if err := pool.QueryRow(ctx, `
+				SELECT COUNT(DISTINCT va.volunteer_id)
+				FROM attendance a
+				JOIN volunteer_assignments va ON va.id = a.assignment_id
+				WHERE va.event_id = $1 AND a.check_out_time IS NULL
+			`, eventID).Scan(&totalActive); err != nil {
+				return err
+			}
+		}
+
+		out := fiber.Map{"event_id": eventID, "active": totalActive, "committees": committees, "as_of": time.Now().UTC()}
+		if sinceMinutes > 0 {
+			out["since_minutes"] = sinceMinutes
+			out["arrivals"] = totalArrivals
+		}
+		c.Set(fiber.HeaderCacheControl, "no-store")
+		return c.JSON(out)
+	}
+}
+
+// ByHour - GET /attendance/by-hour?event_id=&date=YYYY-MM-DD (Faculty/Admin)
+// A 24-bucket histogram for one event-local day (default today): check_ins made in each hour and
+// active, the volunteers on site at any point in that hour. Hours are in the event's timezone;
+// hours that haven't started yet report active 0.
+func ByHour(pool *pgxpool.Pool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
+		eventID, err := strconv.ParseInt(c.Query("event_id"), 10, 64)
+		if err != nil || eventID <= 0 {
+			return fiber.NewError(fiber.StatusBadRequest, "event_id is required")
+		}
+		date := c.Query("date")
+		if date == "" {
+			date = todayIn(eventLocation(ctx, pool, sql.NullInt64{Int64: eventID, Valid: true})).Format("2006-01-02")
+		} else if _, err := time.Parse("2006-01-02", date); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "date must be YYYY-MM-DD")
+		}
+
+		rows, err := pool.Query(ctx, `
+			WITH hours AS (
+				SELECT h,
+				       (($2::date + make_interval(hours => h)) AT TIME ZONE e.tz) AS hour_start,
+				       (($2::date + make_interval(hours => h + 1)) AT TIME ZONE e.tz) AS hour_end,
+				       e.tz
+				FROM events e, generate_series(0, 23) AS h
+				WHERE e.id = $1
+			)
+			SELECT hours.h,
+			       COUNT(a.id) FILTER (WHERE date_trunc('hour', a.check_in_time AT TIME ZONE hours.tz) = $2::date + make_interval(hours => hours.h)),
+			       COUNT(DISTINCT va.volunteer_id) FILTER (WHERE hours.hour_start <= NOW())
+			FROM hours
+			LEFT JOIN attendance a
+			       ON a.check_in_time < hours.hour_end
+			      AND (a.check_out_time IS NULL OR a.check_out_time > hours.hour_start)
+			      AND EXISTS (SELECT 1 FROM volunteer_assignments x WHERE x.id = a.assignment_id AND x.event_id = $1)
+			LEFT JOIN volunteer_assignments va ON va.id = a.assignment_id
+			GROUP BY hours.h
+			ORDER BY hours.h
+		`, eventID, date)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		type hourBucket struct {
+			Hour     int   `json:"hour"`
+			CheckIns int64 `json:"check_ins"`
+			Active   int64 `json:"active"`
+		}
+		out := make([]hourBucket, 0, 24)
+		for rows.Next() {
+			var b hourBucket
+			if err := rows.Scan(&b.Hour, &b.CheckIns, &b.Active); err != nil {
+				return err
+			}
+			out = append(out, b)
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		if len(out) == 0 {
+			return fiber.NewError(fiber.StatusNotFound, "Event not found")
+		}
+		return c.JSON(fiber.Map{"event_id": eventID, "date": date, "hours": out})
+	}
+}
+
+// ByCommittee - GET /attendance/by-committee?event_id=&date=YYYY-MM-DD (Faculty/Admin)
+// Compares check-in rates across the event's committees for one event-local day (default today):
+// assigned counts the assignments with status assigned whose shift starts that day, checked_in
+// those of them with a check-in that day, and rate is checked_in/assigned (null when nobody is assigned).
+// Sorted by rate, lowest first, so understaffed committees come out on top.
+func ByCommittee(pool *pgxpool.Pool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
+		eventID, err := strconv.ParseInt(c.Query("event_id"), 10, 64)
+		if err != nil || eventID <= 0 {
+			return fiber.NewError(fiber.StatusBadRequest, "event_id is required")
+		}
+		date := c.Query("date")
+		if date == "" {
+			date = todayIn(eventLocation(ctx, pool, sql.NullInt64{Int64: eventID, Valid: true})).Format("2006-01-02")
+		} else if _, err := time.Parse("2006-01-02", date); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "date must be YYYY-MM-DD")
+		}
+
+		rows, err := pool.Query(ctx, `
+			SELECT c.id, c.name,
+			       COUNT(va.id) AS assigned,
+			       COUNT(va.id) FILTER (WHERE EXISTS (
+			           SELECT 1 FROM attendance a
+			           WHERE a.assignment_id = va.id AND `+localDate("a.check_in_time")+` = $2::date
+			       )) AS checked_in
+			FROM committees c
+			JOIN events e ON e.id = c.event_id
+			LEFT JOIN volunteer_assignments va
+			       ON va.committee_id = c.id AND va.event_id = e.id AND va.status = 'assigned'
+			      AND `+localDate("COALESCE(va.start_time, va.reporting_time)")+` = $2::date
+			WHERE c.event_id = $1
+			GROUP BY c.id, c.name
+			ORDER BY c.name
+		`, eventID, date)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		type committeeRate struct {
+			CommitteeID   int64    `json:"committee_id"`
+			CommitteeName string   `json:"committee_name"`
+			Assigned      int64    `json:"assigned"`
+			CheckedIn     int64    `json:"checked_in"`
+			Rate          *float64 `json:"rate"`
+		}
+		out := []committeeRate{}
+		for rows.Next() {
+			var cr committeeRate
+			if err := rows.Scan(&cr.CommitteeID, &cr.CommitteeName, &cr.Assigned, &cr.CheckedIn); err != nil {
+				return err
+			}
+			if cr.Assigned > 0 {
+				r := math.Round(float64(cr.CheckedIn)/float64(cr.Assigned)*10000) / 10000
+				cr.Rate = &r
+			}
+			out = append(out, cr)
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		sort.SliceStable(out, func(i, j int) bool {
+			if out[i].Rate == nil || out[j].Rate == nil {
+				return out[j].Rate == nil && out[i].Rate != nil
+			}
+			return *out[i].Rate < *out[j].Rate
+		})
+		return c.JSON(fiber.Map{"event_id": eventID, "date": date, "committees": out})
+	}
+}
+
+// CreateManualAttendance - POST /attendance/manual  {assignment_id, check_in_time, check_out_time?, lat?, lng?} (Faculty/Admin)
+// Records attendance on a volunteer's behalf (e.g. their phone is dead). The record is stamped with
+// checked_in_by (and checked_out_by when a check-out time is given) set to the calling faculty.
+func CreateManualAttendance(pool *pgxpool.Pool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
+		facultyID, err := mw.GetUserIDFromClaims(c)
+		if err != nil {
+			return err
+		}
+
+		var b models.ManualAttendanceRequest
+		if err := c.BodyParser(&b); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "Bad JSON")
+		}
+		if b.AssignmentID <= 0 {
+			return fiber.NewError(fiber.StatusBadRequest, "assignment_id is required")
+		}
+		if strings.TrimSpace(b.CheckInTime) == "" {
+			return fiber.NewError(fiber.StatusBadRequest, "check_in_time is required")
+		}
+		checkIn, err := time.Parse(time.RFC3339, b.CheckInTime)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "Bad check_in_time (RFC3339)")
+		}
+		var checkOut *time.Time
+		if b.CheckOutTime != nil && *b.CheckOutTime != "" {
+			t, err := time.Parse(time.RFC3339, *b.CheckOutTime)
+			if err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "Bad check_out_time (RFC3339)")
+			}
+			if !t.After(checkIn) {
+				return fiber.NewError(fiber.StatusBadRequest, "check_out_time must be after check_in_time")
+			}
+			checkOut = &t
+		}
+
+		tx, err := pool.Begin(ctx)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback(ctx)
+
+		// Lock the assignment so this can't race a concurrent check-in
+		var lockedID int64
+		if err := tx.QueryRow(ctx,
+			`SELECT id FROM volunteer_assignments WHERE id=$1 FOR UPDATE`, b.AssignmentID).Scan(&lockedID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fiber.NewError(fiber.StatusBadRequest, "Invalid assignment_id")
+			}
+			return err
+		}
+
+		// Same rule as self check-in: no second open record for the assignment on that day.
+		if existingID, found, err := activeCheckIn(ctx, tx, b.AssignmentID, checkIn); err != nil {
+			return err
+		} else if found {
+			return alreadyCheckedIn(c, existingID)
+		}
+
+		var checkedOutBy *int64
+		if checkOut != nil {
+			checkedOutBy = &facultyID
+		}
+
+		var newAttendanceID int64
+		err = tx.QueryRow(ctx,
+			`INSERT INTO attendance(assignment_id, check_in_time, check_out_time, lat, lng, checked_in_by, checked_out_by)
+			 VALUES ($1,$2,$3,$4,$5,$6,$7) RETURNING id`,
+			b.AssignmentID, checkIn, checkOut, b.Lat, b.Lng, facultyID, checkedOutBy).Scan(&newAttendanceID)
+		if err != nil {
+			return err
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return err
+		}
+
+		status := "checked_in"
+		if checkOut != nil {
+			status = "checked_out"
+		}
+		return c.Status(fiber.StatusCreated).JSON(fiber.Map{"status": status, "attendance_id": newAttendanceID})
+	}
+}
+
+// attendanceSnapshot is the audited view of an attendance record.
+type attendanceSnapshot struct {
+	CheckInTime  time.Time  `json:"check_in_time"`
+	CheckOutTime *time.Time `json:"check_out_time"`
+	Lat          *float64   `json:"lat"`
+	Lng          *float64   `json:"lng"`
+}
+
+// UpdateAttendance - PUT /attendance/:id  {check_in_time?, check_out_time?, lat?, lng?} (Faculty/Admin)
+// Corrects a record's times or position. check_out_time must stay after check_in_time.
+// The before/after values are written to the audit log.
+func UpdateAttendance(pool *pgxpool.Pool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil || id <= 0 {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid id")
+		}
+		var b models.UpdateAttendanceRequest
+		if err := c.BodyParser(&b); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "Bad JSON")
+		}
+		if b.CheckInTime == nil && b.CheckOutTime == nil && b.Lat == nil && b.Lng == nil {
+			return fiber.NewError(fiber.StatusBadRequest, "no fields to update")
+		}
+
+		tx, err := pool.Begin(ctx)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback(ctx)
+
+		var before attendanceSnapshot
+		var eventID int64
+		err = tx.QueryRow(ctx, `
+			SELECT a.check_in_time, a.check_out_time, a.lat, a.lng, va.event_id
+			FROM attendance a
+			JOIN volunteer_assignments va ON va.id = a.assignment_id
+			WHERE a.id = $1
+			FOR UPDATE OF a
+		`, id).Scan(&before.CheckInTime, &before.CheckOutTime, &before.Lat, &before.Lng, &eventID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fiber.NewError(fiber.StatusNotFound, "attendance record not found")
+			}
+			return err
+		}
+
+		after := before
+		if b.CheckInTime != nil {
+			t, err := time.Parse(time.RFC3339, *b.CheckInTime)
+			if err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "Bad check_in_time (RFC3339)")
+			}
+			after.CheckInTime = t
+		}
+		if b.CheckOutTime != nil {
+			t, err := time.Parse(time.RFC3339, *b.CheckOutTime)
+			if err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "Bad check_out_time (RFC3339)")
+			}
+			after.CheckOutTime = &t
+		}
+		if b.Lat != nil {
+			after.Lat = b.Lat
+		}
+		if b.Lng != nil {
+			after.Lng = b.Lng
+		}
+		if after.CheckOutTime != nil && !after.CheckOutTime.After(after.CheckInTime) {
+			return fiber.NewError(fiber.StatusBadRequest, "check_out_time must be after check_in_time")
+		}
+
+		if _, err := tx.Exec(ctx,
+			`UPDATE attendance SET check_in_time=$2, check_out_time=$3, lat=$4, lng=$5 WHERE id=$1`,
+			id, after.CheckInTime, after.CheckOutTime, after.Lat, after.Lng); err != nil {
+			return err
+		}
+		if err := audit.Record(c, tx, audit.Entry{
+			EventID:     &eventID,
+			EntityTable: "attendance",
+			EntityID:    id,
+			Action:      "update",
+			Diff:        fiber.Map{"before": before, "after": after},
+		}); err != nil {
+			return err
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return err
+		}
+		return c.JSON(fiber.Map{"id": id, "check_in_time": after.CheckInTime, "check_out_time": after.CheckOutTime, "lat": after.Lat, "lng": after.Lng})
+	}
+}
+
+// DeleteAttendance - DELETE /attendance/:id (Faculty/Admin)
+// Removes a mistaken record (and its check-in photo). The deleted values are written to the audit log.
+func DeleteAttendance(pool *pgxpool.Pool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil || id <= 0 {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid id")
+		}
+
+		tx, err := pool.Begin(ctx)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback(ctx)
+
+		var before attendanceSnapshot
+		var assignmentID, eventID int64
+		err = tx.QueryRow(ctx, `
+			DELETE FROM attendance a
+			USING volunteer_assignments va
+			WHERE a.id = $1 AND va.id = a.assignment_id
+			RETURNING a.check_in_time, a.check_out_time, a.lat, a.lng, a.assignment_id, va.event_id
+		`, id).Scan(&before.CheckInTime, &before.CheckOutTime, &before.Lat, &before.Lng, &assignmentID, &eventID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fiber.NewError(fiber.StatusNotFound, "attendance record not found")
+			}
+			return err
+		}
+		if err := audit.Record(c, tx, audit.Entry{
+			EventID:     &eventID,
+			EntityTable: "attendance",
+			EntityID:    id,
+			Action:      "delete",
+			Diff:        fiber.Map{"before": before, "assignment_id": assignmentID},
+		}); err != nil {
+			return err
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return err
+		}
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+}
+
+// GetCheckInPhoto - GET /attendance/:id/photo (Faculty/Admin)
+// Returns the raw image attached at check-in.
+func GetCheckInPhoto(pool *pgxpool.Pool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil || id <= 0 {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid id")
+		}
+		var contentType string
+		var data []byte
+		err = pool.QueryRow(ctx, `SELECT content_type, data FROM attendance_photos WHERE attendance_id = $1`, id).Scan(&contentType, &data)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fiber.NewError(fiber.StatusNotFound, "No photo for this attendance record")
+			}
+			return err
+		}
+		c.Set(fiber.HeaderContentType, contentType)
+		c.Set(fiber.HeaderCacheControl, "private, max-age=3600")
+		return c.Send(data)
 	}
 }
 
@@ -101,6 +635,9 @@ func CheckIn(pool *pgxpool.Pool) fiber.Handler {
 // A volunteer can only check-out for their own attendance records.
 func CheckOut(pool *pgxpool.Pool) fiber.Handler {
 	return func(c *fiber.Ctx) error {
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
 		_, err := mw.GetUserIDFromClaims(c)
 		if err != nil {
 			return fiber.NewError(fiber.StatusUnauthorized, "Volunteer ID not found in token")
@@ -125,7 +662,7 @@ func CheckOut(pool *pgxpool.Pool) fiber.Handler {
 		// Ensure the attendance record exists AND belongs to the logged-in volunteer AND is currently active (check_out_time IS NULL)
 		// Ensure the attendance record exists and is currently active (check_out_time IS NULL)
 		var attendanceExists bool
-		err = pool.QueryRow(c.Context(),
+		err = pool.QueryRow(ctx,
 			`SELECT EXISTS(SELECT 1 FROM attendance WHERE id = $1 AND check_out_time IS NULL)`,
 			b.AttendanceID).Scan(&attendanceExists)
 		if err != nil {
@@ -134,14 +671,14 @@ func CheckOut(pool *pgxpool.Pool) fiber.Handler {
 		if !attendanceExists {
 			// Check if it exists but is already checked out
 			var checkOutTime sql.NullTime
-			_ = pool.QueryRow(c.Context(), `SELECT check_out_time FROM attendance WHERE id=$1`, b.AttendanceID).Scan(&checkOutTime)
+			_ = pool.QueryRow(ctx, `SELECT check_out_time FROM attendance WHERE id=$1`, b.AttendanceID).Scan(&checkOutTime)
 			if checkOutTime.Valid {
 				return fiber.NewError(fiber.StatusConflict, "Already checked out")
 			}
 			return fiber.NewError(fiber.StatusNotFound, "Active attendance record not found")
 		}
 
-		cmd, err := pool.Exec(c.Context(),
+		cmd, err := pool.Exec(ctx,
 			`UPDATE attendance SET check_out_time=$2 WHERE id=$1 AND check_out_time IS NULL`,
 			b.AttendanceID, ts)
 		if err != nil {
@@ -154,144 +691,227 @@ func CheckOut(pool *pgxpool.Pool) fiber.Handler {
 	}
 }
 
-// ListShiftsWithoutCheckIn - GET /attendance/shifts-without-checkin?event_id=&committee_id=&shift=&date=YYYY-MM-DD&limit=100&offset=0
+// ListShiftsWithoutCheckIn - GET /attendance/shifts-without-checkin?event_id=&committee_id=&shift_id=&shift=&exact_shift=false&date=YYYY-MM-DD&include_cancelled=false&exclude_standby=false&limit=100&offset=0
 // For Faculty/Admin to view volunteer assignments that have a start_time on a specific date but no check-in record for that day.
+// Cancelled assignments are left out unless include_cancelled=true; exclude_standby=true also drops standby volunteers.
 func ListShiftsWithoutCheckIn(pool *pgxpool.Pool) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		filters := buildShiftCheckinFilters(c) // Use common filter builder for shifts
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
 
-		args := []any{}
-		whereConditions := []string{"TRUE"} // Start with TRUE to easily append AND conditions
-		paramCounter := 1
-
-		if filters.EventID.Valid {
-			whereConditions = append(whereConditions, "va.event_id=$"+strconv.Itoa(paramCounter))
-			args = append(args, filters.EventID.Int64)
-			paramCounter++
-		}
-		if filters.CommitteeID.Valid {
-			whereConditions = append(whereConditions, "va.committee_id=$"+strconv.Itoa(paramCounter))
-			args = append(args, filters.CommitteeID.Int64)
-			paramCounter++
+		filters, err := buildShiftCheckinFilters(c, pool) // Use common filter builder for shifts
+		if err != nil {
+			return err
 		}
-		if filters.Shift.Valid {
-			whereConditions = append(whereConditions, "va.shift ILIKE $"+strconv.Itoa(paramCounter))
-			args = append(args, "%"+filters.Shift.String+"%") // Case-insensitive search
-			paramCounter++
+		out, err := queryPendingShifts(ctx, c, pool, filters, true)
+		if err != nil {
+			return err
 		}
+		return c.JSON(out)
+	}
+}
 
-		// Filter for assignments whose start_time falls on the targetDate
-		// Also, ensure there is NO attendance record for this assignment on this specific day.
-		whereConditions = append(whereConditions, "DATE(va.start_time) = $"+strconv.Itoa(paramCounter))
-		args = append(args, filters.Date.Time)
-		paramCounter++
-
-		// Subquery to find assignments that *do* have a check-in for the targetDate
-		// Then exclude them from the main query.
-		whereConditions = append(whereConditions, `
-			va.id NOT IN (
-				SELECT DISTINCT assignment_id
-				FROM attendance
-				WHERE DATE(check_in_time) = $`+strconv.Itoa(paramCounter)+`
-			)
-		`)
-		args = append(args, filters.Date.Time) // Use targetDate again for the subquery
-		paramCounter++
-
-		whereClause := "WHERE " + strings.Join(whereConditions, " AND ")
-
-		// Add limit/offset
-		args = append(args, filters.Limit, filters.Offset)
-		query := `
-		  SELECT
-		    va.id AS assignment_id,
-		    va.event_id,
-		    va.committee_id,
-		    va.volunteer_id,
-		    v.name AS volunteer_name,
-		    v.dept AS volunteer_dept,
-			v.college_id AS volunteer_college_id, -- NEW
-		    c.name AS committee_name,
-		    e.name AS event_name,
-			va.role::text AS assignment_role_text,
-			va.status::text AS assignment_status_text,
-			va.reporting_time,
-			va.start_time,
-			va.end_time,
-			va.shift,
-			va.notes
-		  FROM
-		    volunteer_assignments va
-		  JOIN
-		    volunteers v ON v.id = va.volunteer_id
-		  JOIN
-		    committees c ON c.id = va.committee_id
-		  JOIN
-		    events e ON e.id = va.event_id
-		  ` + whereClause + `
-		  ORDER BY va.event_id, va.committee_id, va.start_time, v.name ASC
-		  LIMIT $` + strconv.Itoa(paramCounter) + ` OFFSET $` + strconv.Itoa(paramCounter+1)
-
-		rows, err := pool.Query(c.Context(), query, args...)
+// ExportShiftsWithoutCheckInCSV - GET /attendance/shifts-without-checkin/export_csv (same filters, no pagination)
+// A printable call-list of the volunteers who haven't checked in for their shift.
+func ExportShiftsWithoutCheckInCSV(pool *pgxpool.Pool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		filters, err := buildShiftCheckinFilters(c, pool)
 		if err != nil {
-			log.Printf("Error querying shifts without check-in: %v", err)
 			return err
 		}
-		defer rows.Close()
+		rows, err := queryPendingShifts(c.Context(), c, pool, filters, false)
+		if err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, "Failed to retrieve pending shifts for export")
+		}
 
-		out := make([]models.PendingShiftRow, 0, filters.Limit)
-		for rows.Next() {
-			var r models.PendingShiftRow
-			var volunteerDept, volunteerCollegeID sql.NullString // NEW: collegeID
-			var reportingTime, startTime, endTime sql.NullTime
-			var shift, notes, assignmentRoleStr, assignmentStatusStr sql.NullString
+		c.Set("Content-Type", "text/csv")
+		c.Set("Content-Disposition", `attachment; filename="shifts_without_checkin.csv"`)
 
-			err := rows.Scan(
-				&r.AssignmentID, &r.EventID, &r.CommitteeID, &r.VolunteerID,
-				&r.VolunteerName, &volunteerDept, &volunteerCollegeID, &r.CommitteeName, &r.EventName, // NEW: Scan collegeID
-				&assignmentRoleStr, &assignmentStatusStr, &reportingTime, &startTime, &endTime, &shift, &notes,
-			)
-			if err != nil {
-				log.Printf("Error scanning pending shifts row: %v", err)
-				return err
-			}
+		writer := csv.NewWriter(c.Response().BodyWriter())
+		defer writer.Flush()
 
-			if volunteerDept.Valid {
-				r.VolunteerDept = &volunteerDept.String
-			}
-			if volunteerCollegeID.Valid { // NEW
-				r.VolunteerCollegeID = &volunteerCollegeID.String
-			}
-			if reportingTime.Valid {
-				r.ReportingTime = &reportingTime.Time
-			}
-			if startTime.Valid {
-				r.StartTime = &startTime.Time
-			}
-			if endTime.Valid {
-				r.EndTime = &endTime.Time
+		header := []string{"Volunteer Name", "Phone", "Dept", "Committee", "Shift", "Reporting Time (ISO)"}
+		if err := writer.Write(header); err != nil {
+			log.Printf("Error writing CSV header: %v", err)
+			return fiber.NewError(fiber.StatusInternalServerError, "Failed to write CSV header")
+		}
+		for _, r := range rows {
+			reportingTime := ""
+			if r.ReportingTime != nil {
+				reportingTime = r.ReportingTime.Format(time.RFC3339)
 			}
-			if shift.Valid {
-				r.Shift = &shift.String
+			record := []string{
+				r.VolunteerName,
+				derefString(r.VolunteerPhone),
+				derefString(r.VolunteerDept),
+				r.CommitteeName,
+				derefString(r.Shift),
+				reportingTime,
 			}
-			if notes.Valid {
-				r.Notes = &notes.String
+			if err := writer.Write(record); err != nil {
+				log.Printf("Error writing CSV record for assignment ID %d: %v", r.AssignmentID, err)
 			}
-			r.AssignmentRole = models.AssignmentRole(assignmentRoleStr.String)
-			r.AssignmentStatus = models.AssignmentStatus(assignmentStatusStr.String)
+		}
+		return nil
+	}
+}
+
+// queryPendingShifts runs the shifts-without-check-in query for filters, shared by the JSON list
+// and its CSV export. paginate applies filters.Limit/Offset.
+func queryPendingShifts(ctx context.Context, c *fiber.Ctx, pool *pgxpool.Pool, filters shiftCheckinFilters, paginate bool) ([]models.PendingShiftRow, error) {
+	args := []any{}
+	whereConditions := []string{"TRUE"} // Start with TRUE to easily append AND conditions
+	paramCounter := 1
 
-			out = append(out, r)
+	if filters.EventID.Valid {
+		whereConditions = append(whereConditions, "va.event_id=$"+strconv.Itoa(paramCounter))
+		args = append(args, filters.EventID.Int64)
+		paramCounter++
+	}
+	if filters.CommitteeID.Valid {
+		whereConditions = append(whereConditions, "va.committee_id=$"+strconv.Itoa(paramCounter))
+		args = append(args, filters.CommitteeID.Int64)
+		paramCounter++
+	}
+	if filters.ShiftID.Valid || filters.Shift.Valid {
+		cond, arg := shiftCondition(filters.ShiftID, filters.Shift, filters.ExactShift, paramCounter)
+		whereConditions = append(whereConditions, cond)
+		args = append(args, arg)
+		paramCounter++
+	}
+	if !c.QueryBool("include_cancelled") {
+		whereConditions = append(whereConditions, "va.status <> 'cancelled'")
+	}
+	if c.QueryBool("exclude_standby") {
+		whereConditions = append(whereConditions, "va.status <> 'standby'")
+	}
+
+	// Filter for assignments whose start_time falls on the targetDate
+	// Also, ensure there is NO attendance record for this assignment on this specific day.
+	whereConditions = append(whereConditions, localDate("va.start_time")+" = $"+strconv.Itoa(paramCounter))
+	args = append(args, filters.Date.Time)
+	paramCounter++
+
+	// Exclude assignments that *do* have a check-in for the targetDate. Correlated on assignment_id
+	// so it is served by idx_attendance_assignment_checkin instead of scanning all attendance.
+	whereConditions = append(whereConditions, `
+		NOT EXISTS (
+			SELECT 1
+			FROM attendance att
+			WHERE att.assignment_id = va.id
+			  AND `+localDate("att.check_in_time")+` = $`+strconv.Itoa(paramCounter)+`
+		)
+	`)
+	args = append(args, filters.Date.Time) // Use targetDate again for the subquery
+	paramCounter++
+
+	whereClause := "WHERE " + strings.Join(whereConditions, " AND ")
+
+	// Add limit/offset (exports take every row)
+	pagination := ""
+	if paginate {
+		args = append(args, filters.Limit, filters.Offset)
+		pagination = `LIMIT $` + strconv.Itoa(paramCounter) + ` OFFSET $` + strconv.Itoa(paramCounter+1)
+	}
+	query := `
+	  SELECT
+	    va.id AS assignment_id,
+	    va.event_id,
+	    va.committee_id,
+	    va.volunteer_id,
+	    v.name AS volunteer_name,
+	    v.dept AS volunteer_dept,
+	    v.phone AS volunteer_phone,
+		v.college_id AS volunteer_college_id, -- NEW
+	    c.name AS committee_name,
+	    e.name AS event_name,
+		va.role::text AS assignment_role_text,
+		va.status::text AS assignment_status_text,
+		va.reporting_time,
+		va.start_time,
+		va.end_time,
+		va.shift,
+		va.notes
+	  FROM
+	    volunteer_assignments va
+	  JOIN
+	    volunteers v ON v.id = va.volunteer_id
+	  JOIN
+	    committees c ON c.id = va.committee_id
+	  JOIN
+	    events e ON e.id = va.event_id
+	  ` + whereClause + `
+	  ORDER BY va.event_id, va.committee_id, va.start_time, v.name ASC
+	  ` + pagination
+
+	rows, err := pool.Query(ctx, query, args...)
+	if err != nil {
+		log.Printf("Error querying shifts without check-in: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]models.PendingShiftRow, 0, filters.Limit)
+	for rows.Next() {
+		var r models.PendingShiftRow
+		var volunteerDept, volunteerPhone, volunteerCollegeID sql.NullString // NEW: collegeID
+		var reportingTime, startTime, endTime sql.NullTime
+		var shift, notes, assignmentRoleStr, assignmentStatusStr sql.NullString
+
+		err := rows.Scan(
+			&r.AssignmentID, &r.EventID, &r.CommitteeID, &r.VolunteerID,
+			&r.VolunteerName, &volunteerDept, &volunteerPhone, &volunteerCollegeID, &r.CommitteeName, &r.EventName, // NEW: Scan collegeID
+			&assignmentRoleStr, &assignmentStatusStr, &reportingTime, &startTime, &endTime, &shift, &notes,
+		)
+		if err != nil {
+			log.Printf("Error scanning pending shifts row: %v", err)
+			return nil, err
+		}
 
+		if volunteerDept.Valid {
+			r.VolunteerDept = &volunteerDept.String
 		}
-		return c.JSON(out)
+		if volunteerPhone.Valid {
+			r.VolunteerPhone = &volunteerPhone.String
+		}
+		if volunteerCollegeID.Valid { // NEW
+			r.VolunteerCollegeID = &volunteerCollegeID.String
+		}
+		if reportingTime.Valid {
+			r.ReportingTime = &reportingTime.Time
+		}
+		if startTime.Valid {
+			r.StartTime = &startTime.Time
+		}
+		if endTime.Valid {
+			r.EndTime = &endTime.Time
+		}
+		if shift.Valid {
+			r.Shift = &shift.String
+		}
+		if notes.Valid {
+			r.Notes = &notes.String
+		}
+		r.AssignmentRole = models.AssignmentRole(assignmentRoleStr.String)
+		r.AssignmentStatus = models.AssignmentStatus(assignmentStatusStr.String)
+
+		out = append(out, r)
+
 	}
+	return out, rows.Err()
 }
 
-// ListActiveCheckinsInShift - GET /attendance/active-in-shift?event_id=&committee_id=&shift=&date=YYYY-MM-DD
+// ListActiveCheckinsInShift - GET /attendance/active-in-shift?event_id=&committee_id=&shift_id=&shift=&exact_shift=false&date=YYYY-MM-DD
 // Lists all volunteers currently checked in (check_out_time IS NULL) for a specific shift on a given day.
 func ListActiveCheckinsInShift(pool *pgxpool.Pool) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		filters := buildShiftCheckinFilters(c) // Re-use common filter builder
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
+		filters, err := buildShiftCheckinFilters(c, pool) // Re-use common filter builder
+		if err != nil {
+			return err
+		}
 
 		args := []any{}
 		whereConditions := []string{"a.check_out_time IS NULL"} // Only active check-ins
@@ -307,14 +927,15 @@ func ListActiveCheckinsInShift(pool *pgxpool.Pool) fiber.Handler {
 			args = append(args, filters.CommitteeID.Int64)
 			paramCounter++
 		}
-		if filters.Shift.Valid {
-			whereConditions = append(whereConditions, "va.shift ILIKE $"+strconv.Itoa(paramCounter))
-			args = append(args, "%"+filters.Shift.String+"%")
+		if filters.ShiftID.Valid || filters.Shift.Valid {
+			cond, arg := shiftCondition(filters.ShiftID, filters.Shift, filters.ExactShift, paramCounter)
+			whereConditions = append(whereConditions, cond)
+			args = append(args, arg)
 			paramCounter++
 		}
 
 		// Filter by the date of check-in_time
-		whereConditions = append(whereConditions, "DATE(a.check_in_time) = $"+strconv.Itoa(paramCounter))
+		whereConditions = append(whereConditions, localDate("a.check_in_time")+" = $"+strconv.Itoa(paramCounter))
 		args = append(args, filters.Date.Time)
 		paramCounter++
 
@@ -326,6 +947,7 @@ func ListActiveCheckinsInShift(pool *pgxpool.Pool) fiber.Handler {
 		    a.id, a.assignment_id, a.check_in_time, a.check_out_time, a.lat, a.lng,
 			va.shift, -- NEW: Include shift from assignment
 		    v.id AS volunteer_id, v.name AS volunteer_name, v.college_id AS volunteer_college_id, -- NEW
+		    v.phone AS volunteer_phone,
 		    c.id AS committee_id, c.name AS committee_name,
 		    e.id AS event_id, e.name AS event_name
 		  FROM attendance a
@@ -337,7 +959,7 @@ func ListActiveCheckinsInShift(pool *pgxpool.Pool) fiber.Handler {
 		  ORDER BY a.check_in_time DESC
 		  LIMIT $` + strconv.Itoa(paramCounter) + ` OFFSET $` + strconv.Itoa(paramCounter+1)
 
-		rows, err := pool.Query(c.Context(), query, args...)
+		rows, err := pool.Query(ctx, query, args...)
 		if err != nil {
 			log.Printf("Error querying active check-ins in shift: %v", err)
 			return err
@@ -350,11 +972,12 @@ func ListActiveCheckinsInShift(pool *pgxpool.Pool) fiber.Handler {
 			var checkOutTime sql.NullTime
 			var lat, lng sql.NullFloat64
 			var shift sql.NullString
-			var volunteerCollegeID sql.NullString // NEW
+			var volunteerCollegeID, volunteerPhone sql.NullString // NEW
 
 			err := rows.Scan(&att.ID, &att.AssignmentID, &att.CheckInTime, &checkOutTime, &lat, &lng,
 				&shift,
 				&att.VolunteerID, &att.VolunteerName, &volunteerCollegeID, // NEW
+				&volunteerPhone,
 				&att.CommitteeID, &att.CommitteeName,
 				&att.EventID, &att.EventName)
 			if err != nil {
@@ -377,6 +1000,9 @@ func ListActiveCheckinsInShift(pool *pgxpool.Pool) fiber.Handler {
 			if volunteerCollegeID.Valid { // NEW
 				att.VolunteerCollegeID = &volunteerCollegeID.String
 			}
+			if volunteerPhone.Valid {
+				att.VolunteerPhone = &volunteerPhone.String
+			}
 
 			out = append(out, att)
 
@@ -389,6 +1015,9 @@ func ListActiveCheckinsInShift(pool *pgxpool.Pool) fiber.Handler {
 // Lists all volunteers currently checked in (check_out_time IS NULL) for any shift within a specific committee.
 func ListActiveCheckinsInCommittee(pool *pgxpool.Pool) fiber.Handler {
 	return func(c *fiber.Ctx) error {
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
 		eventIDFilter := sql.NullInt64{}
 		eventIDStr := c.Query("event_id", "")
 		if eventIDStr != "" {
@@ -436,6 +1065,7 @@ func ListActiveCheckinsInCommittee(pool *pgxpool.Pool) fiber.Handler {
 		    a.id, a.assignment_id, a.check_in_time, a.check_out_time, a.lat, a.lng,
 			va.shift, -- NEW: Include shift from assignment
 		    v.id AS volunteer_id, v.name AS volunteer_name, v.college_id AS volunteer_college_id, -- NEW
+		    v.phone AS volunteer_phone,
 		    c.id AS committee_id, c.name AS committee_name,
 		    e.id AS event_id, e.name AS event_name
 		  FROM attendance a
@@ -447,7 +1077,7 @@ func ListActiveCheckinsInCommittee(pool *pgxpool.Pool) fiber.Handler {
 		  ORDER BY a.check_in_time DESC
 		  LIMIT $` + strconv.Itoa(paramCounter) + ` OFFSET $` + strconv.Itoa(paramCounter+1)
 
-		rows, err := pool.Query(c.Context(), query, args...)
+		rows, err := pool.Query(ctx, query, args...)
 		if err != nil {
 			log.Printf("Error querying active check-ins in committee: %v", err)
 			return err
@@ -460,11 +1090,12 @@ func ListActiveCheckinsInCommittee(pool *pgxpool.Pool) fiber.Handler {
 			var checkOutTime sql.NullTime
 			var lat, lng sql.NullFloat64
 			var shift sql.NullString
-			var volunteerCollegeID sql.NullString // NEW
+			var volunteerCollegeID, volunteerPhone sql.NullString // NEW
 
 			err := rows.Scan(&att.ID, &att.AssignmentID, &att.CheckInTime, &checkOutTime, &lat, &lng,
 				&shift,
 				&att.VolunteerID, &att.VolunteerName, &volunteerCollegeID, // NEW
+				&volunteerPhone,
 				&att.CommitteeID, &att.CommitteeName,
 				&att.EventID, &att.EventName)
 			if err != nil {
@@ -487,6 +1118,9 @@ func ListActiveCheckinsInCommittee(pool *pgxpool.Pool) fiber.Handler {
 			if volunteerCollegeID.Valid { // NEW
 				att.VolunteerCollegeID = &volunteerCollegeID.String
 			}
+			if volunteerPhone.Valid {
+				att.VolunteerPhone = &volunteerPhone.String
+			}
 
 			out = append(out, att)
 
@@ -495,18 +1129,35 @@ func ListActiveCheckinsInCommittee(pool *pgxpool.Pool) fiber.Handler {
 	}
 }
 
-// CheckoutShift - POST /attendance/checkout-shift?event_id=&committee_id=&shift=&date=YYYY-MM-DD
+// CheckoutShift - POST /attendance/checkout-shift?event_id=&committee_id=&shift_id=&shift=&exact_shift=true&date=YYYY-MM-DD
 // Marks all active attendance records for a specific shift on a given day as checked out.
+// Prefer shift_id, which matches the linked shift exactly. shift matches the whole name case-insensitively
+// unless exact_shift=false, which falls back to the substring match the list endpoints use by default.
 func CheckoutShift(pool *pgxpool.Pool) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		filters := buildShiftCheckinFilters(c)
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
+		filters, err := buildShiftCheckinFilters(c, pool)
+		if err != nil {
+			return err
+		}
+		filters.ExactShift = c.QueryBool("exact_shift", true) // Never check out "Afternoon" when "A" was meant
 
-		if !filters.EventID.Valid || !filters.CommitteeID.Valid || !filters.Shift.Valid {
-			return fiber.NewError(fiber.StatusBadRequest, "event_id, committee_id, and shift are required to checkout a shift")
+		if !filters.EventID.Valid || !filters.CommitteeID.Valid || !(filters.ShiftID.Valid || filters.Shift.Valid) {
+			return fiber.NewError(fiber.StatusBadRequest, "event_id, committee_id, and shift_id or shift are required to checkout a shift")
+		}
+		shiftName := filters.Shift.String
+		if filters.ShiftID.Valid {
+			name, err := hShifts.Resolve(ctx, pool, filters.EventID.Int64, filters.ShiftID.Int64)
+			if err != nil {
+				return err
+			}
+			shiftName = name
 		}
 
 		// Ensure the current user is Faculty or Admin
-		_, err := mw.GetUserIDFromClaims(c)
+		facultyID, err := mw.GetUserIDFromClaims(c)
 		if err != nil {
 			return fiber.NewError(fiber.StatusUnauthorized, "Only authorized personnel can perform a shift checkout")
 		}
@@ -514,6 +1165,7 @@ func CheckoutShift(pool *pgxpool.Pool) fiber.Handler {
 		now := time.Now()
 
 		// First, get all active attendance IDs that match the criteria
+		shiftCond, shiftArg := shiftCondition(filters.ShiftID, filters.Shift, filters.ExactShift, 3)
 		activeQuery := `
             SELECT a.id
             FROM attendance a
@@ -522,11 +1174,11 @@ func CheckoutShift(pool *pgxpool.Pool) fiber.Handler {
                 a.check_out_time IS NULL AND
                 va.event_id = $1 AND
                 va.committee_id = $2 AND
-                va.shift ILIKE $3
+                ` + shiftCond + `
         `
-		activeArgs := []any{filters.EventID.Int64, filters.CommitteeID.Int64, "%" + filters.Shift.String + "%"}
+		activeArgs := []any{filters.EventID.Int64, filters.CommitteeID.Int64, shiftArg}
 
-		rows, err := pool.Query(c.Context(), activeQuery, activeArgs...)
+		rows, err := pool.Query(ctx, activeQuery, activeArgs...)
 		if err != nil {
 			log.Printf("Error finding active attendance records: %v", err)
 			return err
@@ -552,9 +1204,9 @@ func CheckoutShift(pool *pgxpool.Pool) fiber.Handler {
 		// Update each attendance record
 		var checkedOut int64
 		for _, id := range attendanceIDs {
-			cmd, err := pool.Exec(c.Context(),
-				`UPDATE attendance SET check_out_time = $1 WHERE id = $2 AND check_out_time IS NULL`,
-				now, id)
+			cmd, err := pool.Exec(ctx,
+				`UPDATE attendance SET check_out_time = $1, checked_out_by = $3 WHERE id = $2 AND check_out_time IS NULL`,
+				now, id, facultyID)
 			if err != nil {
 				log.Printf("Error checking out attendance ID %d: %v", id, err)
 				continue
@@ -562,15 +1214,24 @@ func CheckoutShift(pool *pgxpool.Pool) fiber.Handler {
 			checkedOut += cmd.RowsAffected()
 		}
 
-		return c.JSON(fiber.Map{"message": fmt.Sprintf("%d active attendances checked out for shift '%s'.", checkedOut, filters.Shift.String)})
+		return c.JSON(fiber.Map{"message": fmt.Sprintf("%d active attendances checked out for shift '%s'.", checkedOut, shiftName)})
 	}
 }
 
-// ListAllAttendance - GET /attendance?event_id=&committee_id=&volunteer_id=&shift=&start_date=YYYY-MM-DD&end_date=YYYY-MM-DD&limit=100&offset=0
+// ListAllAttendance - GET /attendance?event_id=&committee_id=&volunteer_id=&shift_id=&shift=&exact_shift=false&status=all&suspicious_only=false&start_date=YYYY-MM-DD&end_date=YYYY-MM-DD&limit=100&offset=0
 // For Faculty/Admin to view all attendance records with optional filters.
+// status is active (not checked out yet), completed (checked out) or all (default).
+// Records checked out within MIN_ATTENDANCE_DURATION of checking in are flagged suspicious;
+// suspicious_only=true lists just those. The flag is informational and never blocks a check-out.
 func ListAllAttendance(pool *pgxpool.Pool) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		filters := buildAttendanceFilters(c)
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
+		filters, err := buildAttendanceFilters(c)
+		if err != nil {
+			return err
+		}
 		args := []any{}
 		whereConditions := []string{}
 		paramCounter := 1
@@ -590,21 +1251,39 @@ func ListAllAttendance(pool *pgxpool.Pool) fiber.Handler {
 			args = append(args, filters.VolunteerID.Int64)
 			paramCounter++
 		}
-		if filters.Shift.Valid {
-			whereConditions = append(whereConditions, "va.shift ILIKE $"+strconv.Itoa(paramCounter))
-			args = append(args, "%"+filters.Shift.String+"%")
+		if filters.ShiftID.Valid || filters.Shift.Valid {
+			cond, arg := shiftCondition(filters.ShiftID, filters.Shift, filters.ExactShift, paramCounter)
+			whereConditions = append(whereConditions, cond)
+			args = append(args, arg)
 			paramCounter++
 		}
 		if filters.StartDate.Valid {
-			whereConditions = append(whereConditions, "DATE(a.check_in_time) >= $"+strconv.Itoa(paramCounter))
+			whereConditions = append(whereConditions, localDate("a.check_in_time")+" >= $"+strconv.Itoa(paramCounter))
 			args = append(args, filters.StartDate.Time)
 			paramCounter++
 		}
 		if filters.EndDate.Valid {
-			whereConditions = append(whereConditions, "DATE(a.check_in_time) <= $"+strconv.Itoa(paramCounter))
+			whereConditions = append(whereConditions, localDate("a.check_in_time")+" <= $"+strconv.Itoa(paramCounter))
 			args = append(args, filters.EndDate.Time)
 			paramCounter++
 		}
+		switch status := strings.ToLower(strings.TrimSpace(c.Query("status", "all"))); status {
+		case "active":
+			whereConditions = append(whereConditions, "a.check_out_time IS NULL")
+		case "completed":
+			whereConditions = append(whereConditions, "a.check_out_time IS NOT NULL")
+		case "all", "":
+		default:
+			return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid status %q (accepted values: active, completed, all)", status))
+		}
+
+		// Suspicious = checked out sooner than the minimum duration after checking in
+		suspiciousSQL := "(a.check_out_time IS NOT NULL AND a.check_out_time - a.check_in_time < make_interval(secs => $" + strconv.Itoa(paramCounter) + "))"
+		args = append(args, minAttendanceDuration().Seconds())
+		paramCounter++
+		if c.QueryBool("suspicious_only") {
+			whereConditions = append(whereConditions, suspiciousSQL)
+		}
 
 		whereClause := ""
 		if len(whereConditions) > 0 {
@@ -614,10 +1293,12 @@ func ListAllAttendance(pool *pgxpool.Pool) fiber.Handler {
 		args = append(args, filters.Limit, filters.Offset)
 		query := `
 		  SELECT a.id, a.assignment_id, a.check_in_time, a.check_out_time, a.lat, a.lng,
+		         a.checked_in_by, a.checked_out_by,
 		         v.id AS volunteer_id, v.name AS volunteer_name, v.college_id AS volunteer_college_id, -- NEW
 		         c.id AS committee_id, c.name AS committee_name,
 		         e.id AS event_id, e.name AS event_name,
-				 va.shift AS assignment_shift
+				 va.shift AS assignment_shift,
+		         ` + suspiciousSQL + ` AS suspicious
 		  FROM attendance a
 		  JOIN volunteer_assignments va ON va.id = a.assignment_id
 		  JOIN volunteers v ON v.id = va.volunteer_id
@@ -627,7 +1308,7 @@ func ListAllAttendance(pool *pgxpool.Pool) fiber.Handler {
 		  ORDER BY a.check_in_time DESC
 		  LIMIT $` + strconv.Itoa(paramCounter) + ` OFFSET $` + strconv.Itoa(paramCounter+1)
 
-		rows, err := pool.Query(c.Context(), query, args...)
+		rows, err := pool.Query(ctx, query, args...)
 		if err != nil {
 			log.Printf("Error querying all attendance: %v", err)
 			return err
@@ -641,12 +1322,14 @@ func ListAllAttendance(pool *pgxpool.Pool) fiber.Handler {
 			var lat, lng sql.NullFloat64
 			var assignmentShift sql.NullString
 			var volunteerCollegeID sql.NullString // NEW
+			var suspicious bool
 
 			err := rows.Scan(&att.ID, &att.AssignmentID, &att.CheckInTime, &checkOutTime, &lat, &lng,
+				&att.CheckedInBy, &att.CheckedOutBy,
 				&att.VolunteerID, &att.VolunteerName, &volunteerCollegeID, // NEW
 				&att.CommitteeID, &att.CommitteeName,
 				&att.EventID, &att.EventName,
-				&assignmentShift)
+				&assignmentShift, &suspicious)
 			if err != nil {
 				log.Printf("Error scanning attendance row for ListAllAttendance: %v", err)
 				return err
@@ -667,6 +1350,7 @@ func ListAllAttendance(pool *pgxpool.Pool) fiber.Handler {
 			if volunteerCollegeID.Valid { // NEW
 				att.VolunteerCollegeID = &volunteerCollegeID.String
 			}
+			att.Suspicious = &suspicious
 
 			out = append(out, att)
 		}
@@ -678,11 +1362,14 @@ func ListAllAttendance(pool *pgxpool.Pool) fiber.Handler {
 	}
 }
 
-// ExportAttendanceCSV - GET /attendance/export_csv?event_id=&committee_id=&volunteer_id=&shift=&start_date=YYYY-MM-DD&end_date=YYYY-MM-DD
+// ExportAttendanceCSV - GET /attendance/export_csv?event_id=&committee_id=&volunteer_id=&shift_id=&shift=&exact_shift=false&start_date=YYYY-MM-DD&end_date=YYYY-MM-DD
 // Exports attendance records to a CSV file.
 func ExportAttendanceCSV(pool *pgxpool.Pool) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		filters := buildAttendanceFilters(c) // Re-use filter building logic
+		filters, err := buildAttendanceFilters(c) // Re-use filter building logic
+		if err != nil {
+			return err
+		}
 
 		args := []any{}
 		whereConditions := []string{}
@@ -703,18 +1390,19 @@ func ExportAttendanceCSV(pool *pgxpool.Pool) fiber.Handler {
 			args = append(args, filters.VolunteerID.Int64)
 			paramCounter++
 		}
-		if filters.Shift.Valid {
-			whereConditions = append(whereConditions, "va.shift ILIKE $"+strconv.Itoa(paramCounter))
-			args = append(args, "%"+filters.Shift.String+"%")
+		if filters.ShiftID.Valid || filters.Shift.Valid {
+			cond, arg := shiftCondition(filters.ShiftID, filters.Shift, filters.ExactShift, paramCounter)
+			whereConditions = append(whereConditions, cond)
+			args = append(args, arg)
 			paramCounter++
 		}
 		if filters.StartDate.Valid {
-			whereConditions = append(whereConditions, "DATE(a.check_in_time) >= $"+strconv.Itoa(paramCounter))
+			whereConditions = append(whereConditions, localDate("a.check_in_time")+" >= $"+strconv.Itoa(paramCounter))
 			args = append(args, filters.StartDate.Time)
 			paramCounter++
 		}
 		if filters.EndDate.Valid {
-			whereConditions = append(whereConditions, "DATE(a.check_in_time) <= $"+strconv.Itoa(paramCounter))
+			whereConditions = append(whereConditions, localDate("a.check_in_time")+" <= $"+strconv.Itoa(paramCounter))
 			args = append(args, filters.EndDate.Time)
 			paramCounter++
 		}
@@ -841,15 +1529,17 @@ type attendanceFilters struct {
 	EventID     sql.NullInt64
 	CommitteeID sql.NullInt64
 	VolunteerID sql.NullInt64
+	ShiftID     sql.NullInt64
 	Shift       sql.NullString
+	ExactShift  bool
 	StartDate   sql.NullTime
 	EndDate     sql.NullTime
 	Limit       int
 	Offset      int
 }
 
-// buildAttendanceFilters parses query parameters into an attendanceFilters struct
-func buildAttendanceFilters(c *fiber.Ctx) attendanceFilters {
+// buildAttendanceFilters parses query parameters into an attendanceFilters struct; a malformed shift_id is a 400
+func buildAttendanceFilters(c *fiber.Ctx) (attendanceFilters, error) {
 	filters := attendanceFilters{}
 
 	eventIDStr := c.Query("event_id", "")
@@ -873,10 +1563,20 @@ func buildAttendanceFilters(c *fiber.Ctx) attendanceFilters {
 		}
 	}
 
+	shiftIDStr := c.Query("shift_id", "")
+	if shiftIDStr != "" {
+		id, err := strconv.ParseInt(shiftIDStr, 10, 64)
+		if err != nil || id <= 0 {
+			return filters, fiber.NewError(fiber.StatusBadRequest, "Invalid shift_id query parameter")
+		}
+		filters.ShiftID = sql.NullInt64{Int64: id, Valid: true}
+	}
+
 	shiftStr := c.Query("shift", "")
 	if shiftStr != "" {
 		filters.Shift = sql.NullString{String: shiftStr, Valid: true}
 	}
+	filters.ExactShift = c.QueryBool("exact_shift")
 
 	startDateStr := c.Query("start_date", "")
 	if startDateStr != "" {
@@ -895,21 +1595,25 @@ func buildAttendanceFilters(c *fiber.Ctx) attendanceFilters {
 	filters.Limit = clampInt(c.QueryInt("limit", 100), 1, 500)
 	filters.Offset = maxInt(c.QueryInt("offset", 0), 0)
 
-	return filters
+	return filters, nil
 }
 
 // shiftCheckinFilters struct for building dynamic queries specific to shifts and dates
 type shiftCheckinFilters struct {
 	EventID     sql.NullInt64
 	CommitteeID sql.NullInt64
+	ShiftID     sql.NullInt64
 	Shift       sql.NullString
+	ExactShift  bool
 	Date        sql.NullTime // Specific date for filtering
 	Limit       int
 	Offset      int
 }
 
-// buildShiftCheckinFilters parses query parameters for shift-based attendance endpoints
-func buildShiftCheckinFilters(c *fiber.Ctx) shiftCheckinFilters {
+// buildShiftCheckinFilters parses query parameters for shift-based attendance endpoints.
+// Without a date, "today" is taken in the event's timezone when event_id is given (UTC otherwise).
+// A malformed shift_id is a 400.
+func buildShiftCheckinFilters(c *fiber.Ctx, pool *pgxpool.Pool) (shiftCheckinFilters, error) {
 	filters := shiftCheckinFilters{}
 
 	eventIDStr := c.Query("event_id", "")
@@ -926,10 +1630,20 @@ func buildShiftCheckinFilters(c *fiber.Ctx) shiftCheckinFilters {
 		}
 	}
 
+	shiftIDStr := c.Query("shift_id", "")
+	if shiftIDStr != "" {
+		id, err := strconv.ParseInt(shiftIDStr, 10, 64)
+		if err != nil || id <= 0 {
+			return filters, fiber.NewError(fiber.StatusBadRequest, "Invalid shift_id query parameter")
+		}
+		filters.ShiftID = sql.NullInt64{Int64: id, Valid: true}
+	}
+
 	shiftStr := c.Query("shift", "")
 	if shiftStr != "" {
 		filters.Shift = sql.NullString{String: shiftStr, Valid: true}
 	}
+	filters.ExactShift = c.QueryBool("exact_shift")
 
 	dateStr := c.Query("date", "")
 	if dateStr != "" {
@@ -939,20 +1653,211 @@ func buildShiftCheckinFilters(c *fiber.Ctx) shiftCheckinFilters {
 			// If date is invalid, set a default to prevent query errors, or return an error.
 			// For simplicity, defaulting to today if provided but invalid.
 			log.Printf("Warning: Could not parse date '%s': %v", dateStr, err) // Log the error
-			filters.Date = sql.NullTime{Time: time.Now().Truncate(24 * time.Hour), Valid: true}
+			filters.Date = sql.NullTime{Time: todayIn(eventLocation(c.Context(), pool, filters.EventID)), Valid: true}
 		}
 	} else {
-		// Default to today if no date is specified
-		filters.Date = sql.NullTime{Time: time.Now().Truncate(24 * time.Hour), Valid: true}
+		// Default to today (in the event's timezone) if no date is specified
+		filters.Date = sql.NullTime{Time: todayIn(eventLocation(c.Context(), pool, filters.EventID)), Valid: true}
 	}
 
 	filters.Limit = clampInt(c.QueryInt("limit", 100), 1, 500)
 	filters.Offset = maxInt(c.QueryInt("offset", 0), 0)
 
-	return filters
+	return filters, nil
+}
+
+// minAttendanceDuration is how long a completed check-in must last before it stops being flagged
+// suspicious in ListAllAttendance (MIN_ATTENDANCE_DURATION, Go duration, default 10m).
+func minAttendanceDuration() time.Duration {
+	if v := os.Getenv("MIN_ATTENDANCE_DURATION"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d >= 0 {
+			return d
+		}
+		log.Printf("Invalid MIN_ATTENDANCE_DURATION %q, using 10m", v)
+	}
+	return 10 * time.Minute
+}
+
+// checkInPhotoTypes are the image formats accepted for check-in photos (sniffed, not trusted from the client).
+var checkInPhotoTypes = map[string]bool{"image/jpeg": true, "image/png": true, "image/webp": true}
+
+// checkInPhotoMaxBytes caps a check-in photo (CHECKIN_PHOTO_MAX_BYTES, default 2 MiB).
+func checkInPhotoMaxBytes() int64 { return mw.EnvBytes("CHECKIN_PHOTO_MAX_BYTES", 2<<20) }
+
+// readCheckInPhoto returns the optional photo from a multipart "photo" part or from photo_base64,
+// with its sniffed content type. (nil, "", nil) means no photo was sent.
+func readCheckInPhoto(c *fiber.Ctx, b64 *string) ([]byte, string, error) {
+	max := checkInPhotoMaxBytes()
+	var data []byte
+
+	if fh, err := c.FormFile("photo"); err == nil {
+		if fh.Size > max {
+			return nil, "", fiber.NewError(fiber.StatusRequestEntityTooLarge, fmt.Sprintf("photo exceeds the %d byte limit", max))
+		}
+		f, err := fh.Open()
+		if err != nil {
+			return nil, "", err
+		}
+		defer f.Close()
+		if data, err = io.ReadAll(f); err != nil {
+			return nil, "", err
+		}
+	} else if b64 != nil && strings.TrimSpace(*b64) != "" {
+		s := strings.TrimSpace(*b64)
+		if i := strings.Index(s, ","); strings.HasPrefix(s, "data:") && i > 0 {
+			s = s[i+1:] // strip "data:image/jpeg;base64,"
+		}
+		if int64(base64.StdEncoding.DecodedLen(len(s))) > max+2 {
+			return nil, "", fiber.NewError(fiber.StatusRequestEntityTooLarge, fmt.Sprintf("photo exceeds the %d byte limit", max))
+		}
+		decoded, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return nil, "", fiber.NewError(fiber.StatusBadRequest, "photo_base64 is not valid base64")
+		}
+		data = decoded
+	} else {
+		return nil, "", nil
+	}
+
+	if int64(len(data)) > max {
+		return nil, "", fiber.NewError(fiber.StatusRequestEntityTooLarge, fmt.Sprintf("photo exceeds the %d byte limit", max))
+	}
+	contentType := http.DetectContentType(data)
+	if !checkInPhotoTypes[contentType] {
+		return nil, "", fiber.NewError(fiber.StatusUnsupportedMediaType, "photo must be a JPEG, PNG or WebP image")
+	}
+	return data, contentType, nil
+}
+
+// querier is satisfied by both *pgxpool.Pool and pgx.Tx.
+type querier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// activeCheckIn finds the assignment's open attendance record on ts's day (in the event's timezone).
+// It compares the stored check_in_day, the key of ux_attendance_active_assignment_day, so a check-in it
+// lets through is one the index accepts. Callers hold a lock on the assignment row so the check and the
+// following insert are atomic.
+func activeCheckIn(ctx context.Context, q querier, assignmentID int64, ts time.Time) (int64, bool, error) {
+	var existingAttendanceID int64
+	err := q.QueryRow(ctx, `
+		SELECT a.id
+		FROM attendance a
+		JOIN volunteer_assignments va ON va.id = a.assignment_id
+		JOIN events e ON e.id = va.event_id
+		WHERE a.assignment_id = $1 AND a.check_out_time IS NULL
+		  AND a.check_in_day = `+localDate("$2::timestamptz")+`
+		LIMIT 1
+	`, assignmentID, ts).Scan(&existingAttendanceID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err // Actual DB error
+	}
+	return existingAttendanceID, true, nil
+}
+
+// alreadyCheckedIn is the 409 for a duplicate check-in, pointing at the open record.
+func alreadyCheckedIn(c *fiber.Ctx, attendanceID int64) error {
+	return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+		"error":         "Already checked in for this assignment and not checked out.",
+		"attendance_id": attendanceID,
+	})
+}
+
+// eventOfAssignmentInBody resolves the event of the body's assignment_id for mw.RequireEventScope.
+func eventOfAssignmentInBody(pool *pgxpool.Pool) mw.EventIDResolver {
+	return func(c *fiber.Ctx) (int64, bool, error) {
+		var b struct {
+			AssignmentID int64 `json:"assignment_id"`
+		}
+		if err := c.BodyParser(&b); err != nil {
+			return 0, false, fiber.NewError(fiber.StatusBadRequest, "Bad JSON")
+		}
+		if b.AssignmentID <= 0 {
+			return 0, false, fiber.NewError(fiber.StatusBadRequest, "assignment_id is required")
+		}
+		var eventID int64
+		err := pool.QueryRow(c.Context(), `SELECT event_id FROM volunteer_assignments WHERE id = $1`, b.AssignmentID).Scan(&eventID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return 0, false, fiber.NewError(fiber.StatusBadRequest, "Invalid assignment_id")
+			}
+			return 0, false, err
+		}
+		return eventID, true, nil
+	}
+}
+
+// eventOfAttendance resolves the event of the :id attendance record for mw.RequireEventScope.
+func eventOfAttendance(pool *pgxpool.Pool) mw.EventIDResolver {
+	return func(c *fiber.Ctx) (int64, bool, error) {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil || id <= 0 {
+			return 0, false, fiber.NewError(fiber.StatusBadRequest, "invalid id")
+		}
+		var eventID int64
+		err = pool.QueryRow(c.Context(), `
+			SELECT va.event_id FROM attendance a JOIN volunteer_assignments va ON va.id = a.assignment_id WHERE a.id = $1
+		`, id).Scan(&eventID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return 0, false, fiber.NewError(fiber.StatusNotFound, "attendance record not found")
+			}
+			return 0, false, err
+		}
+		return eventID, true, nil
+	}
+}
+
+// localDate turns a timestamptz column into the calendar date in its event's timezone.
+// The query must join events as e; dates compared against it are event-local.
+func localDate(tsExpr string) string {
+	return "(" + tsExpr + " AT TIME ZONE e.tz)::date"
+}
+
+// eventLocation resolves events.tz for the given event, falling back to UTC when the event
+// is unknown, not given, or has an unrecognized zone name.
+func eventLocation(ctx context.Context, pool *pgxpool.Pool, eventID sql.NullInt64) *time.Location {
+	if !eventID.Valid {
+		return time.UTC
+	}
+	var tz string
+	if err := pool.QueryRow(ctx, `SELECT tz FROM events WHERE id = $1`, eventID.Int64).Scan(&tz); err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			log.Printf("Warning: could not load timezone for event %d: %v", eventID.Int64, err)
+		}
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		log.Printf("Warning: event %d has unknown timezone %q: %v", eventID.Int64, tz, err)
+		return time.UTC
+	}
+	return loc
+}
+
+// todayIn returns the current calendar date in loc, as midnight UTC so it binds as that DATE.
+func todayIn(loc *time.Location) time.Time {
+	y, m, d := time.Now().In(loc).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
 }
 
 // helpers (moved to common/utils or kept local)
+// shiftCondition returns the WHERE condition (as parameter $n) and its argument for a shift filter:
+// an exact match on the linked shift when shiftID is set, else a case-insensitive match on the text,
+// whole-name when exact (so "A" doesn't match "Afternoon") and substring otherwise.
+func shiftCondition(shiftID sql.NullInt64, shift sql.NullString, exact bool, n int) (string, any) {
+	if shiftID.Valid {
+		return "va.shift_id = $" + strconv.Itoa(n), shiftID.Int64
+	}
+	if exact {
+		return "lower(va.shift) = lower($" + strconv.Itoa(n) + ")", strings.Join(strings.Fields(shift.String), " ")
+	}
+	return "va.shift ILIKE $" + strconv.Itoa(n), "%" + shift.String + "%"
+}
+
 func clampInt(v, lo, hi int) int {
 	if v < lo {
 		return lo
@@ -993,6 +1898,14 @@ func formatStringPtr(ns sql.NullString) string {
 	return ""
 }
 
+// derefString returns *p, or "" for nil.
+func derefString(p *string) string {
+	if p == nil {
+		return ""
+	}
+	return *p
+}
+
 // Utility to safely format sql.NullFloat64 to string
 func formatFloat64Ptr(nf sql.NullFloat64) string {
 	if nf.Valid {
@@ -1006,7 +1919,9 @@ type assignmentStatusFilters struct {
 	EventID     sql.NullInt64
 	CommitteeID sql.NullInt64
 	VolunteerID sql.NullInt64
+	ShiftID     sql.NullInt64
 	Shift       sql.NullString
+	ExactShift  bool
 	// Filters for the assignment's start/end times
 	AssignmentStartDate sql.NullTime
 	AssignmentEndDate   sql.NullTime
@@ -1016,8 +1931,8 @@ type assignmentStatusFilters struct {
 	Offset              int
 }
 
-// NEW: Helper to parse query parameters for assignmentStatusFilters
-func buildAssignmentStatusFilters(c *fiber.Ctx) assignmentStatusFilters {
+// NEW: Helper to parse query parameters for assignmentStatusFilters; a malformed shift_id is a 400
+func buildAssignmentStatusFilters(c *fiber.Ctx, pool *pgxpool.Pool) (assignmentStatusFilters, error) {
 	filters := assignmentStatusFilters{}
 
 	eventIDStr := c.Query("event_id", "")
@@ -1041,10 +1956,20 @@ func buildAssignmentStatusFilters(c *fiber.Ctx) assignmentStatusFilters {
 		}
 	}
 
+	shiftIDStr := c.Query("shift_id", "")
+	if shiftIDStr != "" {
+		id, err := strconv.ParseInt(shiftIDStr, 10, 64)
+		if err != nil || id <= 0 {
+			return filters, fiber.NewError(fiber.StatusBadRequest, "Invalid shift_id query parameter")
+		}
+		filters.ShiftID = sql.NullInt64{Int64: id, Valid: true}
+	}
+
 	shiftStr := c.Query("shift", "")
 	if shiftStr != "" {
 		filters.Shift = sql.NullString{String: shiftStr, Valid: true}
 	}
+	filters.ExactShift = c.QueryBool("exact_shift")
 
 	assignmentStartDateStr := c.Query("assignment_start_date", "")
 	if assignmentStartDateStr != "" {
@@ -1067,24 +1992,30 @@ func buildAssignmentStatusFilters(c *fiber.Ctx) assignmentStatusFilters {
 		} else {
 			// Log error but continue with default (today) if parse fails
 			log.Printf("Warning: Could not parse attendance_check_date '%s': %v", attendanceCheckDateStr, err)
-			filters.AttendanceCheckDate = sql.NullTime{Time: time.Now().Truncate(24 * time.Hour), Valid: true}
+			filters.AttendanceCheckDate = sql.NullTime{Time: todayIn(eventLocation(c.Context(), pool, filters.EventID)), Valid: true}
 		}
 	} else {
-		// Default to today if no specific date is provided for attendance check
-		filters.AttendanceCheckDate = sql.NullTime{Time: time.Now().Truncate(24 * time.Hour), Valid: true}
+		// Default to today (in the event's timezone) if no specific date is provided for attendance check
+		filters.AttendanceCheckDate = sql.NullTime{Time: todayIn(eventLocation(c.Context(), pool, filters.EventID)), Valid: true}
 	}
 
 	filters.Limit = clampInt(c.QueryInt("limit", 100), 1, 500)
 	filters.Offset = maxInt(c.QueryInt("offset", 0), 0)
 
-	return filters
+	return filters, nil
 }
 
-// NEW: ListAssignmentsWithCheckinStatus - GET /attendance/assignments-status?event_id=&committee_id=&volunteer_id=&shift=&assignment_start_date=YYYY-MM-DD&assignment_end_date=YYYY-MM-DD&attendance_check_date=YYYY-MM-DD&limit=100&offset=0
+// NEW: ListAssignmentsWithCheckinStatus - GET /attendance/assignments-status?event_id=&committee_id=&volunteer_id=&shift_id=&shift=&exact_shift=false&assignment_start_date=YYYY-MM-DD&assignment_end_date=YYYY-MM-DD&attendance_check_date=YYYY-MM-DD&limit=100&offset=0
 // For Faculty/Admin to view all assignments with their check-in status for a specific day.
 func ListAssignmentsWithCheckinStatus(pool *pgxpool.Pool) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		filters := buildAssignmentStatusFilters(c)
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
+		filters, err := buildAssignmentStatusFilters(c, pool)
+		if err != nil {
+			return err
+		}
 
 		args := []any{}
 		whereConditions := []string{}
@@ -1105,18 +2036,19 @@ func ListAssignmentsWithCheckinStatus(pool *pgxpool.Pool) fiber.Handler {
 			args = append(args, filters.VolunteerID.Int64)
 			paramCounter++
 		}
-		if filters.Shift.Valid {
-			whereConditions = append(whereConditions, "va.shift ILIKE $"+strconv.Itoa(paramCounter))
-			args = append(args, "%"+filters.Shift.String+"%")
+		if filters.ShiftID.Valid || filters.Shift.Valid {
+			cond, arg := shiftCondition(filters.ShiftID, filters.Shift, filters.ExactShift, paramCounter)
+			whereConditions = append(whereConditions, cond)
+			args = append(args, arg)
 			paramCounter++
 		}
 		if filters.AssignmentStartDate.Valid {
-			whereConditions = append(whereConditions, "DATE(va.start_time) >= $"+strconv.Itoa(paramCounter))
+			whereConditions = append(whereConditions, localDate("va.start_time")+" >= $"+strconv.Itoa(paramCounter))
 			args = append(args, filters.AssignmentStartDate.Time)
 			paramCounter++
 		}
 		if filters.AssignmentEndDate.Valid {
-			whereConditions = append(whereConditions, "DATE(va.start_time) <= $"+strconv.Itoa(paramCounter))
+			whereConditions = append(whereConditions, localDate("va.start_time")+" <= $"+strconv.Itoa(paramCounter))
 			args = append(args, filters.AssignmentEndDate.Time)
 			paramCounter++
 		}
@@ -1151,7 +2083,7 @@ func ListAssignmentsWithCheckinStatus(pool *pgxpool.Pool) fiber.Handler {
 		        SELECT att.id
 		        FROM attendance att
 		        WHERE att.assignment_id = va.id
-		          AND DATE(att.check_in_time) = ` + attendanceCheckDatePlaceholder + `
+		          AND ` + localDate("att.check_in_time") + ` = ` + attendanceCheckDatePlaceholder + `
 		          AND att.check_out_time IS NULL
 		        LIMIT 1
 		    ) AS active_attendance_id
@@ -1163,7 +2095,7 @@ func ListAssignmentsWithCheckinStatus(pool *pgxpool.Pool) fiber.Handler {
 		  ORDER BY va.event_id, va.committee_id, va.start_time, v.name ASC
 		  LIMIT $` + strconv.Itoa(paramCounter) + ` OFFSET $` + strconv.Itoa(paramCounter+1)
 
-		rows, err := pool.Query(c.Context(), query, args...)
+		rows, err := pool.Query(ctx, query, args...)
 		if err != nil {
 			log.Printf("Error querying assignments with check-in status: %v", err)
 			return err
@@ -0,0 +1,185 @@
+package attendance
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"Seva-app-backend/db/dbtest"
+	"Seva-app-backend/models"
+)
+
+// testApp mounts the attendance handlers with the auth guards replaced by claims for user sub.
+func testApp(pool *pgxpool.Pool, sub int64, role models.UserRole) *fiber.App {
+	app := dbtest.App()
+	Register(app.Group("/attendance"), pool, dbtest.As(sub, role), dbtest.Pass, dbtest.Pass, dbtest.Pass)
+	return app
+}
+
+// seedAssignment creates an event in tz with one committee, volunteer and assignment.
+func seedAssignment(t *testing.T, pool *pgxpool.Pool, tz string) (eventID, volunteerID, assignmentID int64) {
+	t.Helper()
+	eventID = dbtest.ID(t, pool, `INSERT INTO events (name, tz) VALUES ('Test event', $1) RETURNING id`, tz)
+	committeeID := dbtest.ID(t, pool, `INSERT INTO committees (event_id, name) VALUES ($1, 'Food') RETURNING id`, eventID)
+	volunteerID = dbtest.ID(t, pool, `INSERT INTO volunteers (name) VALUES ('Asha') RETURNING id`)
+	assignmentID = dbtest.ID(t, pool, `
+		INSERT INTO volunteer_assignments (event_id, committee_id, volunteer_id)
+		VALUES ($1, $2, $3) RETURNING id`, eventID, committeeID, volunteerID)
+	return eventID, volunteerID, assignmentID
+}
+
+func TestConcurrentCheckInsOneWins(t *testing.T) {
+	pool := dbtest.Migrated(t)
+	_, volunteerID, assignmentID := seedAssignment(t, pool, "Asia/Kolkata")
+	app := testApp(pool, volunteerID, models.UserRoleVolunteer)
+	body := `{"assignment_id": ` + strconv.FormatInt(assignmentID, 10) + `, "time": "2025-01-01T20:00:00Z"}`
+
+	const n = 8
+	codes := make([]int, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			req := httptest.NewRequest("POST", "/attendance/checkin", strings.NewReader(body))
+			req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+			res, err := app.Test(req, -1)
+			if err != nil {
+				t.Error(err) // not Fatal: this runs off the test goroutine
+				return
+			}
+			res.Body.Close()
+			codes[i] = res.StatusCode
+		}(i)
+	}
+	wg.Wait()
+
+	created, conflicts := 0, 0
+	for _, code := range codes {
+		switch code {
+		case fiber.StatusCreated:
+			created++
+		case fiber.StatusConflict:
+			conflicts++
+		default:
+			t.Fatalf("check-in = %d, want 201 or 409 (all: %v)", code, codes)
+		}
+	}
+	if created != 1 || conflicts != n-1 {
+		t.Fatalf("got %d created and %d conflicts, want 1 and %d", created, conflicts, n-1)
+	}
+}
+
+func TestCheckInRequiresOwnAssignment(t *testing.T) {
+	pool := dbtest.Migrated(t)
+	_, volunteerID, assignmentID := seedAssignment(t, pool, "UTC")
+	otherID := dbtest.ID(t, pool, `INSERT INTO volunteers (name) VALUES ('Ravi') RETURNING id`)
+	body := `{"assignment_id": ` + strconv.FormatInt(assignmentID, 10) + `}`
+
+	if code, res := dbtest.Do(t, testApp(pool, otherID, models.UserRoleVolunteer), "POST", "/attendance/checkin", body); code != fiber.StatusNotFound {
+		t.Fatalf("check-in on someone else's assignment = %d %s, want 404", code, res)
+	}
+	if code, res := dbtest.Do(t, testApp(pool, volunteerID, models.UserRoleVolunteer), "POST", "/attendance/checkin", body); code != fiber.StatusCreated {
+		t.Fatalf("check-in on own assignment = %d %s, want 201", code, res)
+	}
+}
+
+// An open check-in and a new one on different local days but the same UTC day must both be accepted,
+// and two on the same local day but different UTC days must not.
+func TestCheckInDayIsEventLocal(t *testing.T) {
+	pool := dbtest.Migrated(t)
+	_, volunteerID, assignmentID := seedAssignment(t, pool, "Asia/Kolkata")
+	app := testApp(pool, volunteerID, models.UserRoleVolunteer)
+	checkIn := func(at string) int {
+		code, _ := dbtest.Do(t, app, "POST", "/attendance/checkin",
+			`{"assignment_id": `+strconv.FormatInt(assignmentID, 10)+`, "time": "`+at+`"}`)
+		return code
+	}
+
+	// 15:30 on 1 Jan and 01:30 on 2 Jan in Kolkata, both on 1 Jan in UTC
+	if code := checkIn("2025-01-01T10:00:00Z"); code != fiber.StatusCreated {
+		t.Fatalf("first check-in = %d, want 201", code)
+	}
+	if code := checkIn("2025-01-01T20:00:00Z"); code != fiber.StatusCreated {
+		t.Fatalf("check-in on the next local day = %d, want 201", code)
+	}
+	// 23:00 on 2 Jan in Kolkata, 2 Jan in UTC too; the 01:30 record is still open
+	if code := checkIn("2025-01-02T17:30:00Z"); code != fiber.StatusConflict {
+		t.Fatalf("second check-in on one local day = %d, want 409", code)
+	}
+}
+
+func TestShiftsWithoutCheckInLeavesOutCancelled(t *testing.T) {
+	pool := dbtest.Migrated(t)
+	eventID, _, assignedID := seedAssignment(t, pool, "UTC")
+	committeeID := dbtest.ID(t, pool, `SELECT committee_id FROM volunteer_assignments WHERE id = $1`, assignedID)
+	otherID := dbtest.ID(t, pool, `INSERT INTO volunteers (name) VALUES ('Ravi') RETURNING id`)
+	cancelledID := dbtest.ID(t, pool, `
+		INSERT INTO volunteer_assignments (event_id, committee_id, volunteer_id, status)
+		VALUES ($1, $2, $3, 'cancelled') RETURNING id`, eventID, committeeID, otherID)
+	dbtest.Exec(t, pool, `UPDATE volunteer_assignments SET start_time = '2025-03-01T10:00:00Z' WHERE event_id = $1`, eventID)
+	app := testApp(pool, 0, models.UserRoleAdmin)
+
+	list := func(query string) map[int64]bool {
+		t.Helper()
+		code, body := dbtest.Do(t, app, "GET", "/attendance/shifts-without-checkin?event_id="+strconv.FormatInt(eventID, 10)+"&date=2025-03-01"+query, "")
+		if code != fiber.StatusOK {
+			t.Fatalf("GET shifts-without-checkin%s = %d %s", query, code, body)
+		}
+		var rows []models.PendingShiftRow
+		if err := json.Unmarshal(body, &rows); err != nil {
+			t.Fatal(err)
+		}
+		ids := map[int64]bool{}
+		for _, r := range rows {
+			ids[r.AssignmentID] = true
+		}
+		return ids
+	}
+
+	if ids := list(""); !ids[assignedID] || ids[cancelledID] || len(ids) != 1 {
+		t.Fatalf("default list = %v, want only assignment %d (not cancelled %d)", ids, assignedID, cancelledID)
+	}
+	if ids := list("&include_cancelled=true"); !ids[assignedID] || !ids[cancelledID] {
+		t.Fatalf("include_cancelled list = %v, want %d and %d", ids, assignedID, cancelledID)
+	}
+}
+
+func TestEmptyListsAreArrays(t *testing.T) {
+	pool := dbtest.Migrated(t)
+	seedAssignment(t, pool, "UTC") // In another event
+	eventID := dbtest.ID(t, pool, `INSERT INTO events (name) VALUES ('Empty event') RETURNING id`)
+	app := testApp(pool, 0, models.UserRoleAdmin)
+	event := "event_id=" + strconv.FormatInt(eventID, 10)
+	for _, p := range []string{
+		"/attendance?" + event,
+		"/attendance/shifts-without-checkin?" + event + "&date=2025-03-01",
+		"/attendance/assignments-status?" + event,
+	} {
+		code, body := dbtest.Do(t, app, "GET", p, "")
+		if code != fiber.StatusOK || strings.TrimSpace(string(body)) != "[]" {
+			t.Errorf("GET %s = %d %s, want 200 []", p, code, body)
+		}
+	}
+}
+
+func TestBadShiftIDIsRejected(t *testing.T) {
+	app := testApp(nil, 1, models.UserRoleAdmin) // rejected before any query
+	for _, p := range []string{
+		"/attendance?shift_id=abc",
+		"/attendance/export_csv?shift_id=abc",
+		"/attendance/shifts-without-checkin?shift_id=abc",
+		"/attendance/active-in-shift?shift_id=0",
+		"/attendance/assignments-status?shift_id=1.5",
+	} {
+		if code, body := dbtest.Do(t, app, "GET", p, ""); code != fiber.StatusBadRequest {
+			t.Errorf("GET %s = %d %s, want 400", p, code, body)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"Seva-app-backend/models"
+)
+
+// Register mounts the audit log viewer under /audit (admin-only)
+func Register(g fiber.Router, pool *pgxpool.Pool, jwtGuard fiber.Handler, requireAdmin fiber.Handler) {
+	g.Get("/", jwtGuard, requireAdmin, List(pool))
+	g.Get("/export_csv", jwtGuard, requireAdmin, ExportCSV(pool))
+}
+
+// List - GET /audit?entity_table=&action=&actor_id=&event_id=&start_date=YYYY-MM-DD&end_date=YYYY-MM-DD&limit=100&offset=0 (Admin)
+// Newest first, wrapped with the total row count for the filters. end_date is inclusive.
+func List(pool *pgxpool.Pool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		limit := clampInt(c.QueryInt("limit", 100), 1, 500)
+		offset := maxInt(c.QueryInt("offset", 0), 0)
+
+		whereClause, args, err := buildFilters(c)
+		if err != nil {
+			return err
+		}
+
+		var total int64
+		if err := pool.QueryRow(c.Context(), `SELECT COUNT(*) FROM audit_logs `+whereClause, args...).Scan(&total); err != nil {
+			return err
+		}
+
+		n := len(args)
+		args = append(args, limit, offset)
+		rows, err := pool.Query(c.Context(), `
+			SELECT id, actor_type, actor_id, event_id, entity_table, entity_id, action, diff, created_at
+			FROM audit_logs
+			`+whereClause+`
+			ORDER BY created_at DESC, id DESC
+			LIMIT $`+itoa(n+1)+` OFFSET $`+itoa(n+2), args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		items := make([]models.AuditLog, 0, limit)
+		for rows.Next() {
+			var a models.AuditLog
+			if err := rows.Scan(&a.ID, &a.ActorType, &a.ActorID, &a.EventID, &a.EntityTable, &a.EntityID, &a.Action, &a.Diff, &a.CreatedAt); err != nil {
+				return err
+			}
+			items = append(items, a)
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		return c.JSON(models.Page[models.AuditLog]{Items: items, Total: total, Limit: limit, Offset: offset})
+	}
+}
+
+// ExportCSV - GET /audit/export_csv?<same filters as List> (Admin)
+// Streams every matching row (no limit) newest first, so large exports don't sit in memory.
+// The diff column holds the JSON diff as a compact string.
+func ExportCSV(pool *pgxpool.Pool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		whereClause, args, err := buildFilters(c)
+		if err != nil {
+			return err
+		}
+		query := `
+			SELECT created_at, actor_type, COALESCE(actor_id, ''), action, entity_table, entity_id,
+			       COALESCE(event_id::text, ''), COALESCE(diff::text, '')
+			FROM audit_logs
+			` + whereClause + `
+			ORDER BY created_at DESC, id DESC`
+
+		c.Set("Content-Type", "text/csv")
+		c.Set("Content-Disposition", `attachment; filename="audit_log_export.csv"`)
+
+		// The stream writer runs after the handler returns, so it must not touch c
+		c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+			defer cancel()
+
+			writer := csv.NewWriter(w)
+			defer writer.Flush()
+			if err := writer.Write([]string{"Timestamp (ISO)", "Actor Type", "Actor ID", "Action", "Entity Table", "Entity ID", "Event ID", "Diff"}); err != nil {
+				return
+			}
+
+			rows, err := pool.Query(ctx, query, args...)
+			if err != nil {
+				log.Printf("Error querying audit log for CSV export: %v", err)
+				return
+			}
+			defer rows.Close()
+
+			n := 0
+			for rows.Next() {
+				var createdAt time.Time
+				var actorType, actorID, action, entityTable, entityID, eventID, diff string
+				if err := rows.Scan(&createdAt, &actorType, &actorID, &action, &entityTable, &entityID, &eventID, &diff); err != nil {
+					log.Printf("Error scanning audit row for export: %v", err)
+					return
+				}
+				if err := writer.Write([]string{createdAt.Format(time.RFC3339), actorType, actorID, action, entityTable, entityID, eventID, diff}); err != nil {
+					return // client went away
+				}
+				// Push data out periodically instead of buffering the whole export
+				if n++; n%500 == 0 {
+					writer.Flush()
+					if err := w.Flush(); err != nil {
+						return
+					}
+				}
+			}
+			if err := rows.Err(); err != nil {
+				log.Printf("Error iterating audit rows for export: %v", err)
+			}
+		})
+		return nil
+	}
+}
+
+// buildFilters turns the audit query parameters into a WHERE clause (or "") and its args.
+func buildFilters(c *fiber.Ctx) (string, []any, error) {
+	whereConditions := []string{}
+	args := []any{}
+
+	for _, f := range []struct{ param, column string }{
+		{"entity_table", "entity_table"},
+		{"action", "action"},
+		{"actor_id", "actor_id"},
+	} {
+		if v := strings.TrimSpace(c.Query(f.param, "")); v != "" {
+			args = append(args, v)
+			whereConditions = append(whereConditions, f.column+"=$"+itoa(len(args)))
+		}
+	}
+	if v := c.Query("event_id", ""); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return "", nil, fiber.NewError(fiber.StatusBadRequest, "invalid event_id")
+		}
+		args = append(args, id)
+		whereConditions = append(whereConditions, "event_id=$"+itoa(len(args)))
+	}
+	if v := c.Query("start_date", ""); v != "" {
+		t, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			return "", nil, fiber.NewError(fiber.StatusBadRequest, "start_date must be YYYY-MM-DD")
+		}
+		args = append(args, t)
+		whereConditions = append(whereConditions, "created_at >= $"+itoa(len(args)))
+	}
+	if v := c.Query("end_date", ""); v != "" {
+		t, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			return "", nil, fiber.NewError(fiber.StatusBadRequest, "end_date must be YYYY-MM-DD")
+		}
+		args = append(args, t.AddDate(0, 0, 1))
+		whereConditions = append(whereConditions, "created_at < $"+itoa(len(args)))
+	}
+
+	if len(whereConditions) == 0 {
+		return "", args, nil
+	}
+	return "WHERE " + strings.Join(whereConditions, " AND "), args, nil
+}
+
+// Helpers
+func clampInt(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+func itoa(i int) string { return strconv.FormatInt(int64(i), 10) }
@@ -0,0 +1,50 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	mw "Seva-app-backend/middleware"
+)
+
+// Execer is satisfied by both *pgxpool.Pool and pgx.Tx, so entries can be written inside the
+// transaction that makes the change.
+type Execer interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+// Entry is one change to record in audit_logs.
+type Entry struct {
+	EventID     *int64 // Optional event context
+	EntityTable string
+	EntityID    int64
+	Action      string // e.g. "update", "delete"
+	Diff        any    // Marshalled to JSONB; nil stores NULL
+}
+
+// Record writes e to audit_logs, attributed to the caller in c's JWT claims.
+func Record(c *fiber.Ctx, exec Execer, e Entry) error {
+	actorType, actorID := "system", ""
+	if cls, ok := c.Locals("claims").(*mw.Claims); ok && cls != nil {
+		actorType, actorID = string(cls.Role), strconv.FormatInt(cls.Sub, 10)
+	}
+
+	var diff []byte
+	if e.Diff != nil {
+		b, err := json.Marshal(e.Diff)
+		if err != nil {
+			return err
+		}
+		diff = b
+	}
+
+	_, err := exec.Exec(c.Context(), `
+		INSERT INTO audit_logs (actor_type, actor_id, event_id, entity_table, entity_id, action, diff)
+		VALUES ($1, NULLIF($2, ''), $3, $4, $5, $6, $7)
+	`, actorType, actorID, e.EventID, e.EntityTable, strconv.FormatInt(e.EntityID, 10), e.Action, diff)
+	return err
+}
@@ -1,29 +1,43 @@
 package auth
 
 import (
+	"context"
+	"crypto/rand"
 	"crypto/sha256"
 	"database/sql"
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"log"
 	"os"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"golang.org/x/crypto/bcrypt"
 
+	"Seva-app-backend/db"
+	mail "Seva-app-backend/email"
+	hDepartments "Seva-app-backend/handlers/departments"
 	mw "Seva-app-backend/middleware"
 	"Seva-app-backend/models"
+	"Seva-app-backend/validation"
 )
 
-func Register(g fiber.Router, pool *pgxpool.Pool, jwtGuard fiber.Handler, requireAdmin fiber.Handler) {
+// mailer sends the welcome and password-reset emails (mail.Noop when email is disabled).
+// limiter throttles the credential-guessing endpoints (login, registration, password reset) per client IP;
+// availabilityLimiter is a separate budget for check-availability, which the registration form calls as the user types.
+func Register(g fiber.Router, pool *pgxpool.Pool, jwtGuard fiber.Handler, requireAdmin fiber.Handler, limiter fiber.Handler, availabilityLimiter fiber.Handler, mailer mail.Sender) {
 	// Public routes
-	g.Post("/login", login(pool))                          // Generic login (faculty/admin or volunteer)
-	g.Post("/register/volunteer", registerVolunteer(pool)) // Student self-registration (UPDATED)
-	g.Post("/refresh", refresh(pool))                      // For Faculty/Admin refresh tokens
+	g.Post("/login", limiter, login(pool))                                         // Generic login (faculty/admin or volunteer)
+	g.Post("/register/volunteer", limiter, registerVolunteer(pool, mailer))        // Student self-registration (UPDATED)
+	g.Post("/refresh", refresh(pool))                                              // For Faculty/Admin refresh tokens
+	g.Post("/password-reset/request", limiter, requestPasswordReset(pool, mailer)) // Emails a single-use reset token
+	g.Post("/password-reset/confirm", limiter, confirmPasswordReset(pool))         // Sets a new password with that token
+	g.Get("/check-availability", availabilityLimiter, checkAvailability(pool))     // Registration form: is this email / college ID free?
 
 	// Protected routes
 	g.Get("/me", jwtGuard, me())
@@ -54,6 +68,15 @@ func BcryptVerify(hash, plain string) bool {
 	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(plain)) == nil
 }
 
+// LockEmail takes a transaction-scoped advisory lock on the normalized email so that
+// concurrent creations across the faculty and volunteers tables are serialized.
+// There is no single constraint spanning both tables, so every writer that checks
+// for a cross-table collision must hold this lock until its transaction ends.
+func LockEmail(ctx context.Context, tx pgx.Tx, email string) error {
+	_, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext('email:' || lower($1)))`, strings.TrimSpace(email))
+	return err
+}
+
 // sha256b64 hashes a string with SHA256 and base64-encodes it.
 func sha256b64(s string) string {
 	h := sha256.Sum256([]byte(s))
@@ -127,6 +150,8 @@ func issueTokens(c *fiber.Ctx, pool *pgxpool.Pool, userID int64, role models.Use
 		return fmt.Errorf("failed to build access token: %w", err)
 	}
 
+	recordLogin(c.Context(), pool, userID, role)
+
 	response := models.LoginResponse{
 		AccessToken: accessToken,
 		ExpiresIn:   int(accessTTL.Seconds()),
@@ -154,9 +179,21 @@ func issueTokens(c *fiber.Ctx, pool *pgxpool.Pool, userID int64, role models.Use
 	return c.JSON(response)
 }
 
+// recordLogin stamps last_login_at for the user tokens are being issued to. It is best-effort:
+// a failure is logged and never fails the login.
+func recordLogin(ctx context.Context, pool *pgxpool.Pool, userID int64, role models.UserRole) {
+	table := "volunteers"
+	if role == models.UserRoleAdmin || role == models.UserRoleFaculty {
+		table = "faculty"
+	}
+	if _, err := pool.Exec(ctx, `UPDATE `+table+` SET last_login_at = NOW() WHERE id = $1`, userID); err != nil {
+		log.Printf("Failed to record login for %s %d: %v", role, userID, err)
+	}
+}
+
 // ---------- /auth/register/volunteer (Student Self-Registration) ----------
 // UPDATED: This function now handles setting a password for pre-registered volunteers.
-func registerVolunteer(pool *pgxpool.Pool) fiber.Handler {
+func registerVolunteer(pool *pgxpool.Pool, mailer mail.Sender) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		var b models.RegisterVolunteerRequest
 		if err := c.BodyParser(&b); err != nil {
@@ -170,6 +207,16 @@ func registerVolunteer(pool *pgxpool.Pool) fiber.Handler {
 		if name == "" || email == "" || password == "" || len(password) < 8 {
 			return fiber.NewError(fiber.StatusBadRequest, "Name, valid email, and password (min 8 chars) are required")
 		}
+		var v validation.Validator
+		v.MaxLen("name", name, models.MaxNameLen)
+		if err := v.Err(); err != nil {
+			return err
+		}
+
+		dept, err := hDepartments.Resolve(c.Context(), pool, b.Dept)
+		if err != nil {
+			return err
+		}
 
 		// Hash the new password once
 		hashedPassword, err := BcryptHash(password)
@@ -177,9 +224,20 @@ func registerVolunteer(pool *pgxpool.Pool) fiber.Handler {
 			return err
 		}
 
+		tx, err := pool.Begin(c.Context())
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback(c.Context())
+
+		// Serialize against faculty registration and other registrations for the same email
+		if err := LockEmail(c.Context(), tx, email); err != nil {
+			return err
+		}
+
 		// 1. Check if email exists in faculty table (always a conflict for volunteer registration)
 		var facultyExists bool
-		err = pool.QueryRow(c.Context(), `SELECT EXISTS(SELECT 1 FROM faculty WHERE lower(email) = $1)`, email).Scan(&facultyExists)
+		err = tx.QueryRow(c.Context(), `SELECT EXISTS(SELECT 1 FROM faculty WHERE lower(email) = $1)`, email).Scan(&facultyExists)
 		if err != nil {
 			return fmt.Errorf("failed to check existing faculty email: %w", err)
 		}
@@ -190,7 +248,7 @@ func registerVolunteer(pool *pgxpool.Pool) fiber.Handler {
 		// 2. Check if email exists in volunteers table
 		var volunteerID int64
 		var existingPasswordHash sql.NullString
-		err = pool.QueryRow(c.Context(), `SELECT id, password_hash FROM volunteers WHERE lower(email) = $1`, email).Scan(&volunteerID, &existingPasswordHash)
+		err = tx.QueryRow(c.Context(), `SELECT id, password_hash FROM volunteers WHERE lower(email) = $1`, email).Scan(&volunteerID, &existingPasswordHash)
 
 		if err == nil {
 			// Email exists in volunteers table
@@ -199,15 +257,15 @@ func registerVolunteer(pool *pgxpool.Pool) fiber.Handler {
 				return fiber.NewError(fiber.StatusConflict, "Email already registered as a volunteer with a password. Please login.")
 			} else {
 				// 2b. Email exists, but no password is set. Allow them to set it (claim the account).
-				cmd, updateErr := pool.Exec(c.Context(), `
+				cmd, updateErr := tx.Exec(c.Context(), `
 					UPDATE volunteers SET
 						name = $1, email = $2, phone = $3, dept = $4, college_id = $5,
 						password_hash = $6 -- Only update password_hash and potentially other profile data
 					WHERE id = $7 AND role = $8 -- Ensure we only update volunteer roles
-				`, name, email, b.Phone, b.Dept, b.CollegeID, hashedPassword, volunteerID, models.UserRoleVolunteer)
+				`, name, email, b.Phone, dept, b.CollegeID, hashedPassword, volunteerID, models.UserRoleVolunteer)
 				if updateErr != nil {
 					// Handle unique constraint violations if any field other than email is updated to a conflicting value
-					if strings.Contains(updateErr.Error(), "volunteers_college_id_key") {
+					if db.IsUniqueViolation(updateErr, db.ConstraintVolunteersCollegeID) {
 						return fiber.NewError(fiber.StatusConflict, "College ID already registered for another volunteer.")
 					}
 					return fmt.Errorf("failed to update existing volunteer with password: %w", updateErr)
@@ -215,21 +273,29 @@ func registerVolunteer(pool *pgxpool.Pool) fiber.Handler {
 				if cmd.RowsAffected() == 0 {
 					return fiber.NewError(fiber.StatusNotFound, "Volunteer not found or role mismatch (concurrent modification?)")
 				}
+				if err := tx.Commit(c.Context()); err != nil {
+					return err
+				}
+				mail.SendAsync(mailer, welcomeEmail(email, name, true))
 				return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Volunteer password set successfully for existing account", "id": volunteerID})
 			}
 		} else if errors.Is(err, sql.ErrNoRows) {
 			// 3. Email does NOT exist in either faculty or volunteers table. Proceed with new registration.
-			err = pool.QueryRow(c.Context(), `
+			err = tx.QueryRow(c.Context(), `
 				INSERT INTO volunteers(name, email, phone, dept, college_id, password_hash, role)
 				VALUES ($1, $2, $3, $4, $5, $6, $7)
 				RETURNING id
-			`, name, email, b.Phone, b.Dept, b.CollegeID, hashedPassword, models.UserRoleVolunteer).Scan(&volunteerID)
+			`, name, email, b.Phone, dept, b.CollegeID, hashedPassword, models.UserRoleVolunteer).Scan(&volunteerID)
 			if err != nil {
-				if strings.Contains(err.Error(), "volunteers_college_id_key") { // Check for unique constraint violation
+				if db.IsUniqueViolation(err, db.ConstraintVolunteersCollegeID) {
 					return fiber.NewError(fiber.StatusConflict, "College ID already registered.")
 				}
 				return fmt.Errorf("failed to insert new volunteer: %w", err)
 			}
+			if err := tx.Commit(c.Context()); err != nil {
+				return err
+			}
+			mail.SendAsync(mailer, welcomeEmail(email, name, false))
 			return c.Status(fiber.StatusCreated).JSON(fiber.Map{"message": "Volunteer registered successfully", "id": volunteerID})
 		} else {
 			// Actual DB error during the SELECT query
@@ -238,6 +304,193 @@ func registerVolunteer(pool *pgxpool.Pool) fiber.Handler {
 	}
 }
 
+// checkAvailability - GET /auth/check-availability?email=&college_id= (Public)
+// Reports whether registerVolunteer would accept the email and/or college ID, as plain booleans that
+// don't say which table holds a taken value. An email or college ID on a pre-created volunteer with no
+// password still counts as available for that email, since registering claims that account.
+func checkAvailability(pool *pgxpool.Pool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		email := strings.ToLower(strings.TrimSpace(c.Query("email")))
+		collegeID := strings.TrimSpace(c.Query("college_id"))
+		if email == "" && collegeID == "" {
+			return fiber.NewError(fiber.StatusBadRequest, "email or college_id is required")
+		}
+
+		var resp models.AvailabilityResponse
+		if email != "" {
+			var available bool
+			if err := pool.QueryRow(c.Context(), `
+				SELECT NOT EXISTS(SELECT 1 FROM faculty WHERE lower(email) = $1)
+				   AND NOT EXISTS(SELECT 1 FROM volunteers WHERE lower(email) = $1 AND password_hash IS NOT NULL)
+			`, email).Scan(&available); err != nil {
+				return err
+			}
+			resp.EmailAvailable = &available
+		}
+		if collegeID != "" {
+			var available bool
+			if err := pool.QueryRow(c.Context(), `
+				SELECT NOT EXISTS(
+					SELECT 1 FROM volunteers
+					WHERE college_id = $1
+					  AND NOT (password_hash IS NULL AND $2 <> '' AND lower(email) = $2)
+				)
+			`, collegeID, email).Scan(&available); err != nil {
+				return err
+			}
+			resp.CollegeIDAvailable = &available
+		}
+		return c.JSON(resp)
+	}
+}
+
+// welcomeEmail is sent after a volunteer registers or claims a pre-created account.
+func welcomeEmail(to, name string, claimed bool) mail.Message {
+	body := "Hi " + name + ",\n\nWelcome to Seva! Your volunteer account is ready; sign in with this email address to see your assignments.\n"
+	if claimed {
+		body = "Hi " + name + ",\n\nYour password is set and your volunteer account is now active. Sign in with this email address to see your assignments.\n"
+	}
+	return mail.Message{To: to, Subject: "Welcome to Seva", Body: body}
+}
+
+// ---------- /auth/password-reset (Faculty/Admin and Volunteers) ----------
+
+// requestPasswordReset - POST /auth/password-reset/request {email}
+// Always answers 202 so the endpoint can't be used to probe which emails have accounts.
+// When the email belongs to an account, a single-use token (PASSWORD_RESET_TTL, default 1h) is emailed;
+// PASSWORD_RESET_URL, when set, is used to build a link with the token appended as ?token=.
+func requestPasswordReset(pool *pgxpool.Pool, mailer mail.Sender) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var b models.PasswordResetRequest
+		if err := c.BodyParser(&b); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "Bad JSON")
+		}
+		addr := strings.ToLower(strings.TrimSpace(b.Email))
+		if addr == "" {
+			return fiber.NewError(fiber.StatusBadRequest, "email is required")
+		}
+		accepted := fiber.Map{"message": "If an account exists for this email, a reset link has been sent."}
+
+		userType, userID, name, err := accountByEmail(c.Context(), pool, addr)
+		if err != nil {
+			return err
+		}
+		if userType == "" {
+			return c.Status(fiber.StatusAccepted).JSON(accepted)
+		}
+
+		raw := make([]byte, 32)
+		if _, err := rand.Read(raw); err != nil {
+			return err
+		}
+		token := base64.RawURLEncoding.EncodeToString(raw)
+		ttl := ttlFromEnv("PASSWORD_RESET_TTL", time.Hour)
+		if _, err := pool.Exec(c.Context(), `
+			INSERT INTO password_resets (user_type, user_id, token_hash, expires_at)
+			VALUES ($1, $2, $3, $4)
+		`, userType, userID, sha256b64(token), time.Now().Add(ttl)); err != nil {
+			return err
+		}
+
+		body := "Hi " + name + ",\n\nUse this code to reset your Seva password (valid for " + ttl.String() + "):\n\n" + token + "\n"
+		if base := os.Getenv("PASSWORD_RESET_URL"); base != "" {
+			body = "Hi " + name + ",\n\nReset your Seva password here (valid for " + ttl.String() + "):\n\n" + base + "?token=" + token + "\n"
+		}
+		body += "\nIf you didn't ask for this, you can ignore this email.\n"
+		mail.SendAsync(mailer, mail.Message{To: addr, Subject: "Reset your Seva password", Body: body})
+
+		return c.Status(fiber.StatusAccepted).JSON(accepted)
+	}
+}
+
+// confirmPasswordReset - POST /auth/password-reset/confirm {token, new_password}
+// Consumes the token and sets the new password. Faculty refresh sessions are revoked.
+func confirmPasswordReset(pool *pgxpool.Pool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var b models.PasswordResetConfirmRequest
+		if err := c.BodyParser(&b); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "Bad JSON")
+		}
+		if strings.TrimSpace(b.Token) == "" {
+			return fiber.NewError(fiber.StatusBadRequest, "token is required")
+		}
+		if len(b.NewPassword) < 8 {
+			return fiber.NewError(fiber.StatusBadRequest, "new_password must be at least 8 characters")
+		}
+		hash, err := BcryptHash(b.NewPassword)
+		if err != nil {
+			return err
+		}
+
+		tx, err := pool.Begin(c.Context())
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback(c.Context())
+
+		var userType string
+		var userID int64
+		err = tx.QueryRow(c.Context(), `
+			UPDATE password_resets SET used_at = NOW()
+			WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW()
+			RETURNING user_type, user_id
+		`, sha256b64(strings.TrimSpace(b.Token))).Scan(&userType, &userID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fiber.NewError(fiber.StatusBadRequest, "Invalid or expired reset token")
+			}
+			return err
+		}
+
+		table := "volunteers"
+		if userType == "faculty" {
+			table = "faculty"
+		}
+		cmd, err := tx.Exec(c.Context(), `UPDATE `+table+` SET password_hash = $1 WHERE id = $2`, hash, userID)
+		if err != nil {
+			return err
+		}
+		if cmd.RowsAffected() == 0 {
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid or expired reset token")
+		}
+		if userType == "faculty" {
+			if _, err := tx.Exec(c.Context(), `DELETE FROM auth_sessions WHERE faculty_id = $1`, userID); err != nil {
+				return err
+			}
+		}
+		// Any other outstanding tokens for the account are now moot
+		if _, err := tx.Exec(c.Context(), `
+			UPDATE password_resets SET used_at = NOW() WHERE user_type = $1 AND user_id = $2 AND used_at IS NULL
+		`, userType, userID); err != nil {
+			return err
+		}
+		if err := tx.Commit(c.Context()); err != nil {
+			return err
+		}
+		return c.JSON(fiber.Map{"message": "Password updated. Please sign in again."})
+	}
+}
+
+// accountByEmail finds the faculty or volunteer account for a normalized email.
+// userType is "" when no account uses it.
+func accountByEmail(ctx context.Context, pool *pgxpool.Pool, addr string) (userType string, userID int64, name string, err error) {
+	err = pool.QueryRow(ctx, `SELECT id, name FROM faculty WHERE lower(email) = $1`, addr).Scan(&userID, &name)
+	if err == nil {
+		return "faculty", userID, name, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return "", 0, "", err
+	}
+	err = pool.QueryRow(ctx, `SELECT id, name FROM volunteers WHERE lower(email) = $1`, addr).Scan(&userID, &name)
+	if err == nil {
+		return "volunteer", userID, name, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return "", 0, "", err
+	}
+	return "", 0, "", nil
+}
+
 // ---------- /auth/refresh (Faculty/Admin only) ----------
 func refresh(pool *pgxpool.Pool) fiber.Handler {
 	return func(c *fiber.Ctx) error {
@@ -293,6 +546,8 @@ func me() fiber.Handler {
 }
 
 // ---------- /auth/logout ----------
+// Revokes the refresh token in the body, if any, and blocklists the current access token when it has a jti
+// (admin tokens with ACCESS_TOKEN_BLOCKLIST on).
 func logout(pool *pgxpool.Pool) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		var b models.RefreshRequest
@@ -300,6 +555,10 @@ func logout(pool *pgxpool.Pool) fiber.Handler {
 			_, _ = pool.Exec(c.Context(), `UPDATE auth_sessions SET revoked_at=NOW() WHERE refresh_token_hash=$1`,
 				sha256b64(b.RefreshToken))
 		}
+		cls, _ := c.Locals("claims").(*mw.Claims)
+		if err := mw.RevokeAccessToken(c.Context(), pool, cls); err != nil {
+			return fmt.Errorf("failed to revoke access token: %w", err)
+		}
 		return c.SendStatus(fiber.StatusNoContent)
 	}
 }
@@ -328,9 +587,20 @@ func registerFaculty(pool *pgxpool.Pool) fiber.Handler {
 			}
 		}
 
+		tx, err := pool.Begin(c.Context())
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback(c.Context())
+
+		// Serialize against volunteer creation for the same email
+		if err := LockEmail(c.Context(), tx, b.Email); err != nil {
+			return err
+		}
+
 		// Check for email collision with volunteers
 		var exists int
-		err = pool.QueryRow(c.Context(), `
+		err = tx.QueryRow(c.Context(), `
 			SELECT 1 FROM volunteers WHERE lower(email) = $1
 		`, strings.ToLower(b.Email)).Scan(&exists)
 		if err == nil {
@@ -339,15 +609,84 @@ func registerFaculty(pool *pgxpool.Pool) fiber.Handler {
 			return err // Actual DB error
 		}
 
-		_, err = pool.Exec(c.Context(),
+		_, err = tx.Exec(c.Context(),
 			`INSERT INTO faculty(name, email, password_hash, role) VALUES ($1,$2,$3,$4)`,
 			b.Name, strings.ToLower(b.Email), hash, role)
 		if err != nil {
-			if strings.Contains(err.Error(), "faculty_email_key") {
+			if db.IsUniqueViolation(err, db.ConstraintFacultyEmail) {
 				return fiber.NewError(fiber.StatusConflict, "Email already registered for a faculty account")
 			}
 			return err
 		}
+		if err := tx.Commit(c.Context()); err != nil {
+			return err
+		}
 		return c.Status(fiber.StatusCreated).JSON(fiber.Map{"message": "Faculty account created successfully"})
 	}
 }
+
+// ---------- First-run admin bootstrap ----------
+
+// BootstrapAdmin seeds an admin account from BOOTSTRAP_ADMIN_EMAIL / BOOTSTRAP_ADMIN_PASSWORD
+// (and optional BOOTSTRAP_ADMIN_NAME) when the faculty table has no admin yet, so a fresh
+// deployment can log in and register everyone else. It is a no-op when the env vars are unset
+// or an admin already exists, so it is safe to run on every startup.
+func BootstrapAdmin(ctx context.Context, pool *pgxpool.Pool) error {
+	email := strings.ToLower(strings.TrimSpace(os.Getenv("BOOTSTRAP_ADMIN_EMAIL")))
+	password := os.Getenv("BOOTSTRAP_ADMIN_PASSWORD")
+	if email == "" || password == "" {
+		return nil
+	}
+	if len(password) < 8 {
+		return errors.New("BOOTSTRAP_ADMIN_PASSWORD must be at least 8 characters")
+	}
+	name := strings.TrimSpace(os.Getenv("BOOTSTRAP_ADMIN_NAME"))
+	if name == "" {
+		name = "Administrator"
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// Serialize concurrent instances starting up at the same time
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext('bootstrap-admin'))`); err != nil {
+		return err
+	}
+	var admins int
+	if err := tx.QueryRow(ctx, `SELECT COUNT(*) FROM faculty WHERE role = 'admin'`).Scan(&admins); err != nil {
+		return err
+	}
+	if admins > 0 {
+		return nil
+	}
+
+	if err := LockEmail(ctx, tx, email); err != nil {
+		return err
+	}
+	var exists int
+	err = tx.QueryRow(ctx, `
+		SELECT 1 FROM faculty WHERE lower(email) = $1
+		UNION ALL
+		SELECT 1 FROM volunteers WHERE lower(email) = $1
+		LIMIT 1
+	`, email).Scan(&exists)
+	if err == nil {
+		return fmt.Errorf("bootstrap admin email %s is already registered to another account", email)
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	hash, err := BcryptHash(password)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx,
+		`INSERT INTO faculty(name, email, password_hash, role) VALUES ($1,$2,$3,$4)`,
+		name, email, hash, models.UserRoleAdmin); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,44 @@
+package auth
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	mail "Seva-app-backend/email"
+	mw "Seva-app-backend/middleware"
+)
+
+func TestCheckAvailabilityHasItsOwnBudget(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: mw.ErrorHandler})
+	pass := func(c *fiber.Ctx) error { return c.Next() }
+	one := mw.RateLimitConfig{Max: 1, Window: time.Minute}
+	Register(app.Group("/auth"), nil, pass, pass, mw.RateLimit("auth", one, nil), mw.RateLimit("availability", one, nil), mail.Noop{})
+
+	status := func(method, path, body string) int {
+		t.Helper()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		res, err := app.Test(req, -1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+		return res.StatusCode
+	}
+
+	// Both requests are rejected before any query; only the limiter's verdict matters here
+	status("POST", "/auth/login", `{}`)
+	if code := status("POST", "/auth/login", `{}`); code != fiber.StatusTooManyRequests {
+		t.Fatalf("second login = %d, want 429", code)
+	}
+	if code := status("GET", "/auth/check-availability", ""); code != fiber.StatusBadRequest {
+		t.Fatalf("check-availability after the login budget is spent = %d, want 400 (not limited)", code)
+	}
+	if code := status("GET", "/auth/check-availability", ""); code != fiber.StatusTooManyRequests {
+		t.Fatalf("second check-availability = %d, want 429", code)
+	}
+}
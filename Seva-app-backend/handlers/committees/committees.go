@@ -1,15 +1,19 @@
 package committees
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"strconv"
 	"strings"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
+	"Seva-app-backend/db"
 	"Seva-app-backend/models" // Ensure this import is present
+	"Seva-app-backend/validation"
 )
 
 // Register mounts committee routes under /committees
@@ -22,14 +26,29 @@ func Register(g fiber.Router, pool *pgxpool.Pool, jwtGuard fiber.Handler, requir
 	// Admin-only write access
 	g.Post("/", jwtGuard, requireAdmin, Create(pool))
 	g.Put("/:id", jwtGuard, requireAdmin, Update(pool))
+	g.Patch("/:id/move", jwtGuard, requireAdmin, Move(pool))
 	g.Delete("/:id", jwtGuard, requireAdmin, Del(pool))
+	g.Get("/:id/capacities", jwtGuard, requireAdmin, ListCapacities(pool))
+	g.Put("/:id/capacities", jwtGuard, requireAdmin, SetCapacity(pool))
+	g.Get("/:id/assignment-stats", jwtGuard, requireAdmin, AssignmentStats(pool))
 }
 
-// List - GET /committees?event_id=1&limit=100&offset=0
-// ... (rest of the List function remains the same as previous)
+// List - GET /committees?event_id=1&sort=display_order&limit=100&offset=0
+// Committees come in display_order (then name) by default; sort=name lists them alphabetically.
 func List(pool *pgxpool.Pool) fiber.Handler {
 	return func(c *fiber.Ctx) error {
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
 		eventIDStr := c.Query("event_id", "")
+		orderBy := "c.display_order, c.name"
+		switch c.Query("sort", "display_order") {
+		case "display_order":
+		case "name":
+			orderBy = "c.name"
+		default:
+			return fiber.NewError(fiber.StatusBadRequest, "invalid sort (accepted values: display_order, name)")
+		}
 		limit := clampInt(c.QueryInt("limit", 100), 1, 500)
 		offset := maxInt(c.QueryInt("offset", 0), 0)
 		args := []any{}
@@ -47,16 +66,16 @@ func List(pool *pgxpool.Pool) fiber.Handler {
 		}
 
 		query := `
-			SELECT c.id, c.event_id, c.name, COALESCE(c.description,''), c.created_at, e.name as event_name
+			SELECT c.id, c.event_id, c.name, COALESCE(c.description,''), c.display_order, c.created_at, c.updated_at, e.name as event_name
 			FROM committees c
 			JOIN events e ON e.id = c.event_id
 			` + where + `
-			ORDER BY c.name
+			ORDER BY ` + orderBy + `
 			LIMIT $` + strconv.Itoa(paramCounter) + ` OFFSET $` + strconv.Itoa(paramCounter+1)
 
 		args = append(args, limit, offset)
 
-		rows, err := pool.Query(c.Context(), query, args...)
+		rows, err := pool.Query(ctx, query, args...)
 		if err != nil {
 			return err
 		}
@@ -65,7 +84,7 @@ func List(pool *pgxpool.Pool) fiber.Handler {
 		out := make([]models.Committee, 0, limit)
 		for rows.Next() {
 			var cm models.Committee
-			if err := rows.Scan(&cm.ID, &cm.EventID, &cm.Name, &cm.Description, &cm.CreatedAt, &cm.EventName); err != nil {
+			if err := rows.Scan(&cm.ID, &cm.EventID, &cm.Name, &cm.Description, &cm.DisplayOrder, &cm.CreatedAt, &cm.UpdatedAt, &cm.EventName); err != nil {
 				return err
 			}
 			out = append(out, cm)
@@ -78,18 +97,21 @@ func List(pool *pgxpool.Pool) fiber.Handler {
 // ... (rest of the Get function remains the same as previous)
 func Get(pool *pgxpool.Pool) fiber.Handler {
 	return func(c *fiber.Ctx) error {
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
 		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
 		if err != nil {
 			return fiber.NewError(fiber.StatusBadRequest, "invalid id")
 		}
 		var cm models.Committee
 		err = pool.
-			QueryRow(c.Context(),
-				`SELECT c.id, c.event_id, c.name, COALESCE(c.description,''), c.created_at, e.name as event_name
+			QueryRow(ctx,
+				`SELECT c.id, c.event_id, c.name, COALESCE(c.description,''), c.display_order, c.created_at, c.updated_at, e.name as event_name
 				 FROM committees c
 				 JOIN events e ON e.id = c.event_id
 				 WHERE c.id=$1`, id).
-			Scan(&cm.ID, &cm.EventID, &cm.Name, &cm.Description, &cm.CreatedAt, &cm.EventName)
+			Scan(&cm.ID, &cm.EventID, &cm.Name, &cm.Description, &cm.DisplayOrder, &cm.CreatedAt, &cm.UpdatedAt, &cm.EventName)
 		if err != nil {
 			if errors.Is(err, sql.ErrNoRows) {
 				return fiber.NewError(fiber.StatusNotFound, "committee not found")
@@ -103,10 +125,14 @@ func Get(pool *pgxpool.Pool) fiber.Handler {
 // Create - POST /committees (Admin-only)
 func Create(pool *pgxpool.Pool) fiber.Handler {
 	return func(c *fiber.Ctx) error {
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
 		var b models.CreateCommitteeRequest // This was the undeclared name
 		if err := c.BodyParser(&b); err != nil {
 			return fiber.NewError(fiber.StatusBadRequest, "bad json")
 		}
+		b.Name = strings.TrimSpace(b.Name)
 		if b.EventID <= 0 || len(b.Name) == 0 {
 			return fiber.NewError(fiber.StatusBadRequest, "event_id and name are required")
 		}
@@ -114,18 +140,36 @@ func Create(pool *pgxpool.Pool) fiber.Handler {
 		if b.Description != nil {
 			desc = *b.Description
 		}
+		var v validation.Validator
+		v.MaxLen("name", b.Name, models.MaxNameLen)
+		v.MaxLen("description", desc, models.MaxDescriptionLen)
+		if err := v.Err(); err != nil {
+			return err
+		}
+		taken, err := nameTaken(ctx, pool, b.EventID, b.Name, 0)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return fiber.NewError(fiber.StatusConflict, "Committee name already exists for this event")
+		}
+
+		displayOrder := 0
+		if b.DisplayOrder != nil {
+			displayOrder = *b.DisplayOrder
+		}
 
 		var cm models.Committee
-		err := pool.
-			QueryRow(c.Context(),
-				`INSERT INTO committees(event_id, name, description)
-				 VALUES ($1,$2,$3)
-				 RETURNING id, event_id, name, COALESCE(description,''), created_at`,
-				b.EventID, b.Name, desc).
-			Scan(&cm.ID, &cm.EventID, &cm.Name, &cm.Description, &cm.CreatedAt)
-		if err != nil {
-			// unique(event_id, name) may trigger a constraint error
-			if strings.Contains(err.Error(), "committees_event_id_name_key") { // Assuming you have such a constraint
+		err = pool.
+			QueryRow(ctx,
+				`INSERT INTO committees(event_id, name, description, display_order)
+				 VALUES ($1,$2,$3,$4)
+				 RETURNING id, event_id, name, COALESCE(description,''), display_order, created_at, updated_at`,
+				b.EventID, b.Name, desc, displayOrder).
+			Scan(&cm.ID, &cm.EventID, &cm.Name, &cm.Description, &cm.DisplayOrder, &cm.CreatedAt, &cm.UpdatedAt)
+		if err != nil {
+			// unique(event_id, name) still catches a concurrent create that slipped past nameTaken
+			if db.IsUniqueViolation(err, db.ConstraintCommitteesEventName) {
 				return fiber.NewError(fiber.StatusConflict, "Committee name already exists for this event")
 			}
 			return err
@@ -137,6 +181,9 @@ func Create(pool *pgxpool.Pool) fiber.Handler {
 // Update - PUT /committees/:id (Admin-only)
 func Update(pool *pgxpool.Pool) fiber.Handler {
 	return func(c *fiber.Ctx) error {
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
 		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
 		if err != nil {
 			return fiber.NewError(fiber.StatusBadRequest, "invalid id")
@@ -145,7 +192,7 @@ func Update(pool *pgxpool.Pool) fiber.Handler {
 		if err := c.BodyParser(&b); err != nil {
 			return fiber.NewError(fiber.StatusBadRequest, "bad json")
 		}
-		if b.Name == nil && b.Description == nil {
+		if b.Name == nil && b.Description == nil && b.DisplayOrder == nil {
 			return fiber.NewError(fiber.StatusBadRequest, "no fields to update")
 		}
 
@@ -154,11 +201,40 @@ func Update(pool *pgxpool.Pool) fiber.Handler {
 		args := []any{}
 		i := 1
 		if b.Name != nil {
+			name := strings.TrimSpace(*b.Name)
+			if name == "" {
+				return fiber.NewError(fiber.StatusBadRequest, "name cannot be empty")
+			}
+			var v validation.Validator
+			v.MaxLen("name", name, models.MaxNameLen)
+			if err := v.Err(); err != nil {
+				return err
+			}
+			var eventID int64
+			err := pool.QueryRow(ctx, `SELECT event_id FROM committees WHERE id = $1`, id).Scan(&eventID)
+			if err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					return fiber.NewError(fiber.StatusNotFound, "committee not found")
+				}
+				return err
+			}
+			taken, err := nameTaken(ctx, pool, eventID, name, id)
+			if err != nil {
+				return err
+			}
+			if taken {
+				return fiber.NewError(fiber.StatusConflict, "Committee name already exists for this event")
+			}
 			set += "name = $" + strconv.Itoa(i)
-			args = append(args, *b.Name)
+			args = append(args, name)
 			i++
 		}
 		if b.Description != nil {
+			var v validation.Validator
+			v.MaxLen("description", *b.Description, models.MaxDescriptionLen)
+			if err := v.Err(); err != nil {
+				return err
+			}
 			if set != "" {
 				set += ", "
 			}
@@ -166,13 +242,21 @@ func Update(pool *pgxpool.Pool) fiber.Handler {
 			args = append(args, *b.Description)
 			i++
 		}
+		if b.DisplayOrder != nil {
+			if set != "" {
+				set += ", "
+			}
+			set += "display_order = $" + strconv.Itoa(i)
+			args = append(args, *b.DisplayOrder)
+			i++
+		}
 		args = append(args, id)
 
-		cmd, err := pool.Exec(c.Context(),
+		cmd, err := pool.Exec(ctx,
 			`UPDATE committees SET `+set+` WHERE id = $`+strconv.Itoa(i), args...)
 		if err != nil {
-			// Check for unique constraint violation on name if it was updated
-			if b.Name != nil && strings.Contains(err.Error(), "committees_event_id_name_key") {
+			// Fallback for a concurrent rename that slipped past nameTaken
+			if db.IsUniqueViolation(err, db.ConstraintCommitteesEventName) {
 				return fiber.NewError(fiber.StatusConflict, "Committee name already exists for this event")
 			}
 			return err
@@ -184,25 +268,344 @@ func Update(pool *pgxpool.Pool) fiber.Handler {
 	}
 }
 
-// Del - DELETE /committees/:id (Admin-only)
-// ... (rest of the Del function remains the same as previous)
+// Move - PATCH /committees/:id/move (Admin-only)
+// Moves a committee created under the wrong event, together with its volunteer assignments,
+// announcements and questions, in one transaction.
+func Move(pool *pgxpool.Pool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid id")
+		}
+		var b models.MoveCommitteeRequest
+		if err := c.BodyParser(&b); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "bad json")
+		}
+		if b.TargetEventID <= 0 {
+			return fiber.NewError(fiber.StatusBadRequest, "target_event_id is required")
+		}
+
+		tx, err := pool.Begin(ctx)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback(ctx)
+
+		var fromEventID int64
+		var name string
+		err = tx.QueryRow(ctx, `SELECT event_id, name FROM committees WHERE id = $1 FOR UPDATE`, id).Scan(&fromEventID, &name)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fiber.NewError(fiber.StatusNotFound, "committee not found")
+			}
+			return err
+		}
+		if fromEventID == b.TargetEventID {
+			return fiber.NewError(fiber.StatusBadRequest, "committee already belongs to target_event_id")
+		}
+
+		var eventExists bool
+		if err := tx.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM events WHERE id = $1)`, b.TargetEventID).Scan(&eventExists); err != nil {
+			return err
+		}
+		if !eventExists {
+			return fiber.NewError(fiber.StatusUnprocessableEntity, "target event not found")
+		}
+		taken, err := nameTaken(ctx, tx, b.TargetEventID, name, id)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return fiber.NewError(fiber.StatusConflict, "Committee name already exists in the target event")
+		}
+
+		if _, err := tx.Exec(ctx, `UPDATE committees SET event_id = $1 WHERE id = $2`, b.TargetEventID, id); err != nil {
+			if db.IsUniqueViolation(err, db.ConstraintCommitteesEventName) {
+				return fiber.NewError(fiber.StatusConflict, "Committee name already exists in the target event")
+			}
+			return err
+		}
+		assignments, err := tx.Exec(ctx, `UPDATE volunteer_assignments SET event_id = $1 WHERE committee_id = $2`, b.TargetEventID, id)
+		if err != nil {
+			return err
+		}
+		announcements, err := tx.Exec(ctx, `UPDATE announcements SET event_id = $1 WHERE committee_id = $2`, b.TargetEventID, id)
+		if err != nil {
+			return err
+		}
+		questions, err := tx.Exec(ctx, `UPDATE questions SET event_id = $1 WHERE committee_id = $2`, b.TargetEventID, id)
+		if err != nil {
+			return err
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return err
+		}
+
+		return c.JSON(fiber.Map{
+			"committee_id":        id,
+			"from_event_id":       fromEventID,
+			"to_event_id":         b.TargetEventID,
+			"moved_assignments":   assignments.RowsAffected(),
+			"moved_announcements": announcements.RowsAffected(),
+			"moved_questions":     questions.RowsAffected(),
+		})
+	}
+}
+
+// Del - DELETE /committees/:id?force=false (Admin-only)
+// Refuses with 409 and the dependent counts while the committee still has volunteer assignments
+// (and their attendance) or announcements. force=true deletes those dependents in the same transaction
+// and responds with a summary of what was removed; a committee without dependents returns 204.
 func Del(pool *pgxpool.Pool) fiber.Handler {
 	return func(c *fiber.Ctx) error {
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
 		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
 		if err != nil {
 			return fiber.NewError(fiber.StatusBadRequest, "invalid id")
 		}
-		cmd, err := pool.Exec(c.Context(), `DELETE FROM committees WHERE id=$1`, id)
+		force := c.QueryBool("force", false)
+
+		tx, err := pool.Begin(ctx)
 		if err != nil {
 			return err
 		}
-		if cmd.RowsAffected() == 0 {
+		defer tx.Rollback(ctx)
+
+		// Locking the committee holds off new assignments and announcements (their foreign key needs
+		// a share lock on it), so the counts below are exactly what gets deleted.
+		if err := tx.QueryRow(ctx, `SELECT id FROM committees WHERE id = $1 FOR UPDATE`, id).Scan(&id); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fiber.NewError(fiber.StatusNotFound, "committee not found")
+			}
+			return err
+		}
+
+		var assignments, attendance, announcements int64
+		err = tx.QueryRow(ctx, `
+			SELECT
+				(SELECT COUNT(*) FROM volunteer_assignments WHERE committee_id = $1),
+				(SELECT COUNT(*) FROM attendance a JOIN volunteer_assignments va ON va.id = a.assignment_id WHERE va.committee_id = $1),
+				(SELECT COUNT(*) FROM announcements WHERE committee_id = $1)
+		`, id).Scan(&assignments, &attendance, &announcements)
+		if err != nil {
+			return err
+		}
+		if !force && assignments+announcements > 0 {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error":         "committee has dependent records; retry with ?force=true to delete them",
+				"assignments":   assignments,
+				"attendance":    attendance,
+				"announcements": announcements,
+			})
+		}
+
+		// Attendance goes with its assignments via ON DELETE CASCADE
+		if _, err := tx.Exec(ctx, `DELETE FROM volunteer_assignments WHERE committee_id = $1`, id); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, `DELETE FROM announcements WHERE committee_id = $1`, id); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, `DELETE FROM committees WHERE id = $1`, id); err != nil {
+			return err
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return err
+		}
+		if assignments+announcements == 0 {
+			return c.SendStatus(fiber.StatusNoContent)
+		}
+		return c.JSON(fiber.Map{
+			"deleted":               id,
+			"removed_assignments":   assignments,
+			"removed_attendance":    attendance,
+			"removed_announcements": announcements,
+		})
+	}
+}
+
+// ListCapacities - GET /committees/:id/capacities (Admin-only)
+// Lists the committee's shifts with their capacity (null = unlimited) and non-cancelled assignment count:
+// every shift with a capacity set, plus every shift that has assignments.
+func ListCapacities(pool *pgxpool.Pool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid id")
+		}
+		var exists bool
+		if err := pool.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM committees WHERE id = $1)`, id).Scan(&exists); err != nil {
+			return err
+		}
+		if !exists {
 			return fiber.NewError(fiber.StatusNotFound, "committee not found")
 		}
-		return c.SendStatus(fiber.StatusNoContent)
+
+		rows, err := pool.Query(ctx, `
+			SELECT COALESCE(sc.shift, a.shift), sc.capacity, COALESCE(a.assigned, 0)
+			FROM (SELECT shift, capacity FROM shift_capacities WHERE committee_id = $1) sc
+			FULL JOIN (
+				SELECT min(shift) AS shift, lower(shift) AS shift_key, COUNT(*) AS assigned
+				FROM volunteer_assignments
+				WHERE committee_id = $1 AND shift IS NOT NULL AND status <> 'cancelled'
+				GROUP BY lower(shift)
+			) a ON a.shift_key = lower(sc.shift)
+			ORDER BY 1
+		`, id)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		out := []models.ShiftCapacity{}
+		for rows.Next() {
+			sc := models.ShiftCapacity{CommitteeID: id}
+			if err := rows.Scan(&sc.Shift, &sc.Capacity, &sc.Assigned); err != nil {
+				return err
+			}
+			out = append(out, sc)
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		return c.JSON(out)
+	}
+}
+
+// SetCapacity - PUT /committees/:id/capacities (Admin-only)
+// Sets the capacity of one shift, or removes it when capacity is null. Lowering a capacity below the
+// current count keeps the existing assignments; it only blocks new ones.
+func SetCapacity(pool *pgxpool.Pool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid id")
+		}
+		var b models.SetShiftCapacityRequest
+		if err := c.BodyParser(&b); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "bad json")
+		}
+		shift := strings.Join(strings.Fields(b.Shift), " ")
+		if shift == "" {
+			return fiber.NewError(fiber.StatusBadRequest, "shift is required")
+		}
+		var v validation.Validator
+		v.MaxLen("shift", shift, models.MaxNameLen)
+		if err := v.Err(); err != nil {
+			return err
+		}
+		if b.Capacity != nil && *b.Capacity < 0 {
+			return fiber.NewError(fiber.StatusBadRequest, "capacity cannot be negative")
+		}
+
+		var exists bool
+		if err := pool.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM committees WHERE id = $1)`, id).Scan(&exists); err != nil {
+			return err
+		}
+		if !exists {
+			return fiber.NewError(fiber.StatusNotFound, "committee not found")
+		}
+
+		if b.Capacity == nil {
+			if _, err := pool.Exec(ctx, `DELETE FROM shift_capacities WHERE committee_id = $1 AND lower(shift) = lower($2)`, id, shift); err != nil {
+				return err
+			}
+			return c.SendStatus(fiber.StatusNoContent)
+		}
+
+		sc := models.ShiftCapacity{CommitteeID: id}
+		err = pool.QueryRow(ctx, `
+			WITH upserted AS (
+				INSERT INTO shift_capacities (committee_id, shift, capacity) VALUES ($1, $2, $3)
+				ON CONFLICT (committee_id, lower(shift)) DO UPDATE SET capacity = EXCLUDED.capacity, updated_at = NOW()
+				RETURNING shift, capacity
+			)
+			SELECT u.shift, u.capacity, (
+				SELECT COUNT(*) FROM volunteer_assignments va
+				WHERE va.committee_id = $1 AND lower(va.shift) = lower(u.shift) AND va.status <> 'cancelled'
+			)
+			FROM upserted u
+		`, id, shift, *b.Capacity).Scan(&sc.Shift, &sc.Capacity, &sc.Assigned)
+		if err != nil {
+			return err
+		}
+		return c.JSON(sc)
+	}
+}
+
+// AssignmentStats - GET /committees/:id/assignment-stats?event_id= (Admin-only)
+// Counts the committee's assignments by role and by status in one pass, so organizers can check a
+// committee has its leads before the event starts. Role counts leave out cancelled assignments.
+func AssignmentStats(pool *pgxpool.Pool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid id")
+		}
+		stats := models.CommitteeAssignmentStats{CommitteeID: id}
+		if s := c.Query("event_id", ""); s != "" {
+			eventID, err := strconv.ParseInt(s, 10, 64)
+			if err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "invalid event_id")
+			}
+			stats.EventID = &eventID
+		}
+
+		// The LEFT JOIN keeps a committee without assignments (all zeros); no row at all means no committee
+		err = pool.QueryRow(ctx, `
+			SELECT COUNT(va.id),
+			       COUNT(*) FILTER (WHERE va.role = 'volunteer' AND va.status <> 'cancelled'),
+			       COUNT(*) FILTER (WHERE va.role = 'lead' AND va.status <> 'cancelled'),
+			       COUNT(*) FILTER (WHERE va.role = 'support' AND va.status <> 'cancelled'),
+			       COUNT(*) FILTER (WHERE va.status = 'assigned'),
+			       COUNT(*) FILTER (WHERE va.status = 'standby'),
+			       COUNT(*) FILTER (WHERE va.status = 'cancelled')
+			FROM committees c
+			LEFT JOIN volunteer_assignments va ON va.committee_id = c.id AND ($2::bigint IS NULL OR va.event_id = $2)
+			WHERE c.id = $1
+			GROUP BY c.id
+		`, id, stats.EventID).Scan(&stats.Total,
+			&stats.ByRole.Volunteer, &stats.ByRole.Lead, &stats.ByRole.Support,
+			&stats.ByStatus.Assigned, &stats.ByStatus.Standby, &stats.ByStatus.Cancelled)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fiber.NewError(fiber.StatusNotFound, "committee not found")
+			}
+			return err
+		}
+		return c.JSON(stats)
 	}
 }
 
+// querier is satisfied by both *pgxpool.Pool and pgx.Tx.
+type querier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// nameTaken reports whether another committee in the event already uses name (case-insensitive).
+// exceptID excludes the committee being renamed; pass 0 on create.
+func nameTaken(ctx context.Context, q querier, eventID int64, name string, exceptID int64) (bool, error) {
+	var taken bool
+	err := q.QueryRow(ctx, `
+		SELECT EXISTS(SELECT 1 FROM committees WHERE event_id = $1 AND lower(name) = lower($2) AND id <> $3)
+	`, eventID, name, exceptID).Scan(&taken)
+	return taken, err
+}
+
 // helpers (moved to common/utils or kept local)
 func clampInt(v, lo, hi int) int {
 	if v < lo {
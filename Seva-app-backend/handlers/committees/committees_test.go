@@ -0,0 +1,67 @@
+package committees
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"Seva-app-backend/db/dbtest"
+	"Seva-app-backend/models"
+)
+
+// testApp mounts the committees handlers with the auth guards replaced by claims for user sub.
+func testApp(pool *pgxpool.Pool, sub int64, role models.UserRole) *fiber.App {
+	app := dbtest.App()
+	Register(app.Group("/committees"), pool, dbtest.As(sub, role), dbtest.Pass)
+	return app
+}
+
+func TestEmptyListsAreArrays(t *testing.T) {
+	pool := dbtest.Migrated(t)
+	event := strconv.FormatInt(dbtest.ID(t, pool, `INSERT INTO events (name) VALUES ('Empty event') RETURNING id`), 10)
+	app := testApp(pool, 0, models.UserRoleAdmin)
+	for _, p := range []string{
+		"/committees?event_id=" + event,
+		"/committees?event_id=" + event + "&sort=name",
+	} {
+		code, body := dbtest.Do(t, app, "GET", p, "")
+		if code != fiber.StatusOK || strings.TrimSpace(string(body)) != "[]" {
+			t.Errorf("GET %s = %d %s, want 200 []", p, code, body)
+		}
+	}
+}
+
+func TestForceDeleteReportsWhatWasRemoved(t *testing.T) {
+	pool := dbtest.Migrated(t)
+	app := testApp(pool, 0, models.UserRoleAdmin)
+	eventID := dbtest.ID(t, pool, `INSERT INTO events (name) VALUES ('Test event') RETURNING id`)
+	committeeID := dbtest.ID(t, pool, `INSERT INTO committees (event_id, name) VALUES ($1, 'Food') RETURNING id`, eventID)
+	volunteerID := dbtest.ID(t, pool, `INSERT INTO volunteers (name) VALUES ('Asha') RETURNING id`)
+	assignmentID := dbtest.ID(t, pool, `
+		INSERT INTO volunteer_assignments (event_id, committee_id, volunteer_id) VALUES ($1, $2, $3) RETURNING id`,
+		eventID, committeeID, volunteerID)
+	dbtest.Exec(t, pool, `INSERT INTO attendance (assignment_id, check_in_time) VALUES ($1, NOW())`, assignmentID)
+	path := "/committees/" + strconv.FormatInt(committeeID, 10)
+
+	if code, body := dbtest.Do(t, app, "DELETE", path, ""); code != fiber.StatusConflict {
+		t.Fatalf("DELETE without force = %d %s, want 409", code, body)
+	}
+	code, body := dbtest.Do(t, app, "DELETE", path+"?force=true", "")
+	if code != fiber.StatusOK {
+		t.Fatalf("DELETE ?force=true = %d %s", code, body)
+	}
+	var summary map[string]int64
+	if err := json.Unmarshal(body, &summary); err != nil {
+		t.Fatal(err)
+	}
+	if summary["removed_assignments"] != 1 || summary["removed_attendance"] != 1 || summary["removed_announcements"] != 0 {
+		t.Errorf("summary = %v, want 1 assignment, 1 attendance, 0 announcements", summary)
+	}
+	if code, body := dbtest.Do(t, app, "DELETE", path, ""); code != fiber.StatusNotFound {
+		t.Errorf("DELETE again = %d %s, want 404", code, body)
+	}
+}
@@ -0,0 +1,107 @@
+package departments
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"Seva-app-backend/db"
+	"Seva-app-backend/models"
+)
+
+// Register mounts department routes under /departments
+func Register(g fiber.Router, pool *pgxpool.Pool, jwtGuard fiber.Handler, requireAdmin fiber.Handler) {
+	// Public read access (used to populate dropdowns)
+	g.Get("/", List(pool))
+
+	// Admin-only write access
+	g.Post("/", jwtGuard, requireAdmin, Create(pool))
+}
+
+// List - GET /departments (Public)
+func List(pool *pgxpool.Pool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		rows, err := pool.Query(c.Context(), `SELECT id, name, created_at FROM departments ORDER BY name`)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		out := []models.Department{}
+		for rows.Next() {
+			var d models.Department
+			if err := rows.Scan(&d.ID, &d.Name, &d.CreatedAt); err != nil {
+				return err
+			}
+			out = append(out, d)
+		}
+		return c.JSON(out)
+	}
+}
+
+// Create - POST /departments (Admin-only)
+func Create(pool *pgxpool.Pool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var b models.CreateDepartmentRequest
+		if err := c.BodyParser(&b); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "bad json")
+		}
+		name := strings.TrimSpace(b.Name)
+		if name == "" {
+			return fiber.NewError(fiber.StatusBadRequest, "name is required")
+		}
+
+		var d models.Department
+		err := pool.QueryRow(c.Context(), `
+			INSERT INTO departments(name) VALUES ($1)
+			RETURNING id, name, created_at
+		`, name).Scan(&d.ID, &d.Name, &d.CreatedAt)
+		if err != nil {
+			if db.IsUniqueViolation(err, db.ConstraintDepartmentsNameLower) {
+				return fiber.NewError(fiber.StatusConflict, "Department already exists")
+			}
+			return err
+		}
+		return c.Status(fiber.StatusCreated).JSON(d)
+	}
+}
+
+// querier is satisfied by both *pgxpool.Pool and pgx.Tx.
+type querier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// StrictMode reports whether department values must match a known department (DEPARTMENT_STRICT=true).
+func StrictMode() bool {
+	return strings.ToLower(os.Getenv("DEPARTMENT_STRICT")) == "true"
+}
+
+// Resolve validates a department value for volunteer/faculty writes.
+// Nil or blank input is passed through as nil. Outside strict mode the trimmed value is
+// returned as-is; in strict mode it must match a known department case-insensitively and
+// the canonical stored spelling is returned, otherwise a 400 is returned.
+func Resolve(ctx context.Context, q querier, dept *string) (*string, error) {
+	if dept == nil || strings.TrimSpace(*dept) == "" {
+		return nil, nil
+	}
+	d := strings.TrimSpace(*dept)
+	if !StrictMode() {
+		return &d, nil
+	}
+
+	var canonical string
+	err := q.QueryRow(ctx, `SELECT name FROM departments WHERE lower(name) = lower($1)`, d).Scan(&canonical)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fiber.NewError(fiber.StatusBadRequest, "Unknown department '"+d+"'. See GET /departments for valid values.")
+		}
+		return nil, err
+	}
+	return &canonical, nil
+}
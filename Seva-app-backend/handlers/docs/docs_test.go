@@ -0,0 +1,47 @@
+package docs
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestUIServesVendoredAssets(t *testing.T) {
+	app := fiber.New()
+	app.Get("/swagger", UI())
+	app.Get("/swagger/:file", Asset())
+
+	get := func(path string) (int, string, string) {
+		t.Helper()
+		res, err := app.Test(httptest.NewRequest("GET", path, nil), -1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer res.Body.Close()
+		b, err := io.ReadAll(res.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res.StatusCode, res.Header.Get(fiber.HeaderContentType), string(b)
+	}
+
+	_, _, page := get("/swagger")
+	if strings.Contains(page, "https://") {
+		t.Errorf("UI page loads assets from another origin:\n%s", page)
+	}
+	for name, contentType := range uiAssets {
+		if !strings.Contains(page, `"/swagger/`+name+`"`) {
+			t.Errorf("UI page does not reference /swagger/%s", name)
+		}
+		code, gotType, body := get("/swagger/" + name)
+		if code != fiber.StatusOK || gotType != contentType || body == "" {
+			t.Errorf("GET /swagger/%s = %d %q (%d bytes)", name, code, gotType, len(body))
+		}
+	}
+	if code, _, _ := get("/swagger/index.html"); code != fiber.StatusNotFound {
+		t.Errorf("GET /swagger/index.html = %d, want 404", code)
+	}
+}
@@ -0,0 +1,153 @@
+package events
+
+import (
+	"database/sql"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	mw "Seva-app-backend/middleware"
+)
+
+// Register mounts event routes under /events
+func Register(g fiber.Router, pool *pgxpool.Pool, jwtGuard fiber.Handler, requireFaculty fiber.Handler) {
+	g.Get("/:id/stats", jwtGuard, requireFaculty, mw.RequireEventScope(pool, eventFromParam), Stats(pool))
+}
+
+// eventFromParam resolves the :id event for mw.RequireEventScope.
+func eventFromParam(c *fiber.Ctx) (int64, bool, error) {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, false, fiber.NewError(fiber.StatusBadRequest, "invalid event id")
+	}
+	return id, true, nil
+}
+
+// sqlExpectedToday matches non-cancelled, non-standby assignments whose shift starts today in the event's
+// timezone; sqlCheckedInToday matches those with a check-in made today. Both need va and e in scope.
+const (
+	sqlExpectedToday = `va.status = 'assigned'
+		AND (COALESCE(va.start_time, va.reporting_time) AT TIME ZONE e.tz)::date = (NOW() AT TIME ZONE e.tz)::date`
+	sqlCheckedInToday = `EXISTS (SELECT 1 FROM attendance a WHERE a.assignment_id = va.id
+		AND (a.check_in_time AT TIME ZONE e.tz)::date = (NOW() AT TIME ZONE e.tz)::date)`
+)
+
+type committeeStats struct {
+	CommitteeID      int64    `json:"committee_id"`
+	CommitteeName    string   `json:"committee_name"`
+	Volunteers       int64    `json:"volunteers"`
+	Assignments      int64    `json:"assignments"`
+	ExpectedToday    int64    `json:"expected_today"`
+	CheckedInToday   int64    `json:"checked_in_today"`
+	CheckInRate      *float64 `json:"check_in_rate"` // null when nobody is expected today
+	QuestionsPending int64    `json:"questions_pending"`
+}
+
+// Stats - GET /events/:id/stats (Faculty/Admin)
+// An organizer's overview of one event: volunteers and assignments (by status), today's check-in
+// rate in the event's timezone, unanswered questions and active announcements, plus the same
+// figures per committee. Cancelled assignments are excluded from volunteer and assignment counts;
+// "expected today" counts assigned (not standby) shifts starting today.
+func Stats(pool *pgxpool.Pool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		eventID, _, err := eventFromParam(c)
+		if err != nil {
+			return err
+		}
+
+		var (
+			name, tz                                 string
+			volunteers, assigned, standby, cancelled int64
+			expectedToday, checkedInToday            int64
+			questionsPending, announcementsActive    int64
+		)
+		err = pool.QueryRow(c.Context(), `
+			SELECT e.name, e.tz,
+			       (SELECT COUNT(DISTINCT va.volunteer_id) FROM volunteer_assignments va WHERE va.event_id = e.id AND va.status <> 'cancelled'),
+			       (SELECT COUNT(*) FROM volunteer_assignments va WHERE va.event_id = e.id AND va.status = 'assigned'),
+			       (SELECT COUNT(*) FROM volunteer_assignments va WHERE va.event_id = e.id AND va.status = 'standby'),
+			       (SELECT COUNT(*) FROM volunteer_assignments va WHERE va.event_id = e.id AND va.status = 'cancelled'),
+			       (SELECT COUNT(*) FROM volunteer_assignments va WHERE va.event_id = e.id AND `+sqlExpectedToday+`),
+			       (SELECT COUNT(*) FROM volunteer_assignments va WHERE va.event_id = e.id AND `+sqlExpectedToday+` AND `+sqlCheckedInToday+`),
+			       (SELECT COUNT(*) FROM questions q WHERE q.event_id = e.id AND q.answer_text IS NULL),
+			       (SELECT COUNT(*) FROM announcements an WHERE an.event_id = e.id
+			           AND (an.publish_at IS NULL OR an.publish_at <= NOW())
+			           AND (an.expires_at IS NULL OR an.expires_at > NOW()))
+			FROM events e
+			WHERE e.id = $1
+		`, eventID).Scan(&name, &tz, &volunteers, &assigned, &standby, &cancelled,
+			&expectedToday, &checkedInToday, &questionsPending, &announcementsActive)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fiber.NewError(fiber.StatusNotFound, "Event not found")
+			}
+			return err
+		}
+
+		rows, err := pool.Query(c.Context(), `
+			SELECT c.id, c.name,
+			       COUNT(DISTINCT va.volunteer_id) FILTER (WHERE va.status <> 'cancelled'),
+			       COUNT(va.id) FILTER (WHERE va.status <> 'cancelled'),
+			       COUNT(va.id) FILTER (WHERE `+sqlExpectedToday+`),
+			       COUNT(va.id) FILTER (WHERE `+sqlExpectedToday+` AND `+sqlCheckedInToday+`),
+			       (SELECT COUNT(*) FROM questions q WHERE q.committee_id = c.id AND q.answer_text IS NULL)
+			FROM committees c
+			JOIN events e ON e.id = c.event_id
+			LEFT JOIN volunteer_assignments va ON va.committee_id = c.id AND va.event_id = e.id
+			WHERE c.event_id = $1
+			GROUP BY c.id, c.name, e.tz
+			ORDER BY c.name
+		`, eventID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		committees := []committeeStats{}
+		for rows.Next() {
+			var cs committeeStats
+			if err := rows.Scan(&cs.CommitteeID, &cs.CommitteeName, &cs.Volunteers, &cs.Assignments,
+				&cs.ExpectedToday, &cs.CheckedInToday, &cs.QuestionsPending); err != nil {
+				return err
+			}
+			cs.CheckInRate = rate(cs.CheckedInToday, cs.ExpectedToday)
+			committees = append(committees, cs)
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		c.Set(fiber.HeaderCacheControl, "no-store")
+		return c.JSON(fiber.Map{
+			"event_id":   eventID,
+			"event_name": name,
+			"tz":         tz,
+			"volunteers": volunteers,
+			"assignments": fiber.Map{
+				"total":     assigned + standby,
+				"assigned":  assigned,
+				"standby":   standby,
+				"cancelled": cancelled,
+			},
+			"expected_today":       expectedToday,
+			"checked_in_today":     checkedInToday,
+			"check_in_rate":        rate(checkedInToday, expectedToday),
+			"questions_pending":    questionsPending,
+			"announcements_active": announcementsActive,
+			"committees":           committees,
+			"as_of":                time.Now().UTC(),
+		})
+	}
+}
+
+// rate returns part/whole rounded to 4 decimals, or nil when whole is zero.
+func rate(part, whole int64) *float64 {
+	if whole == 0 {
+		return nil
+	}
+	r := float64(int64(float64(part)/float64(whole)*10000+0.5)) / 10000
+	return &r
+}
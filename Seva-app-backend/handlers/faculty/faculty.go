@@ -0,0 +1,408 @@
+package faculty
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"Seva-app-backend/db"
+	hAuth "Seva-app-backend/handlers/auth" // For the shared email lock
+	hDepartments "Seva-app-backend/handlers/departments"
+	mw "Seva-app-backend/middleware"
+	"Seva-app-backend/models"
+)
+
+// Register mounts faculty management routes under /faculty (all admin-only)
+func Register(g fiber.Router, pool *pgxpool.Pool, jwtGuard fiber.Handler, requireAdmin fiber.Handler) {
+	g.Get("/", jwtGuard, requireAdmin, List(pool))
+	g.Get("/:id", jwtGuard, requireAdmin, Get(pool))
+	g.Put("/:id", jwtGuard, requireAdmin, Update(pool))
+	g.Delete("/:id", jwtGuard, requireAdmin, Del(pool))
+
+	// Event scoping: a faculty member with grants only sees those events
+	g.Get("/:id/events", jwtGuard, requireAdmin, ListEvents(pool))
+	g.Post("/:id/events", jwtGuard, requireAdmin, GrantEvent(pool))
+	g.Delete("/:id/events/:event_id", jwtGuard, requireAdmin, RevokeEvent(pool))
+}
+
+// List - GET /faculty?role=&q=&limit=100&offset=0 (Admin)
+// role filters to 'admin' or 'faculty'; q matches name or email (case-insensitive substring).
+func List(pool *pgxpool.Pool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		limit := clampInt(c.QueryInt("limit", 100), 1, 500)
+		offset := maxInt(c.QueryInt("offset", 0), 0)
+
+		whereConditions := []string{"TRUE"}
+		args := []any{limit, offset}
+		if role := strings.ToLower(strings.TrimSpace(c.Query("role", ""))); role != "" {
+			if models.UserRole(role) != models.UserRoleAdmin && models.UserRole(role) != models.UserRoleFaculty {
+				return fiber.NewError(fiber.StatusBadRequest, "Invalid role. Must be 'admin' or 'faculty'.")
+			}
+			args = append(args, role)
+			whereConditions = append(whereConditions, "role = $"+itoa(len(args)))
+		}
+		if q := strings.TrimSpace(c.Query("q", "")); q != "" {
+			args = append(args, "%"+q+"%")
+			whereConditions = append(whereConditions, "(name ILIKE $"+itoa(len(args))+" OR email ILIKE $"+itoa(len(args))+")")
+		}
+
+		rows, err := pool.Query(c.Context(), `
+			SELECT id, name, email, phone, department, role, last_login_at
+			FROM faculty
+			WHERE `+strings.Join(whereConditions, " AND ")+`
+			ORDER BY name
+			LIMIT $1 OFFSET $2
+		`, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		out := make([]models.Faculty, 0, limit)
+		for rows.Next() {
+			var f models.Faculty
+			if err := rows.Scan(&f.ID, &f.Name, &f.Email, &f.Phone, &f.Department, &f.Role, &f.LastLoginAt); err != nil {
+				return err
+			}
+			out = append(out, f)
+		}
+		return c.JSON(out)
+	}
+}
+
+// Get - GET /faculty/:id (Admin)
+func Get(pool *pgxpool.Pool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil || id <= 0 {
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid faculty ID")
+		}
+
+		var f models.Faculty
+		err = pool.QueryRow(c.Context(), `
+			SELECT id, name, email, phone, department, role, last_login_at
+			FROM faculty WHERE id = $1
+		`, id).Scan(&f.ID, &f.Name, &f.Email, &f.Phone, &f.Department, &f.Role, &f.LastLoginAt)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fiber.NewError(fiber.StatusNotFound, "Faculty not found")
+			}
+			return err
+		}
+		return c.JSON(f)
+	}
+}
+
+// Update - PUT /faculty/:id (Admin)
+// Updates name, email, phone, department and/or role. Passwords are not managed here.
+// The last remaining admin cannot be demoted.
+func Update(pool *pgxpool.Pool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil || id <= 0 {
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid faculty ID")
+		}
+
+		var b models.UpdateFacultyRequest
+		if err := c.BodyParser(&b); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "Bad JSON")
+		}
+
+		tx, err := pool.Begin(c.Context())
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback(c.Context())
+
+		sets := []string{}
+		args := []any{}
+		i := 1
+
+		if b.Name != nil {
+			name := strings.TrimSpace(*b.Name)
+			if name == "" {
+				return fiber.NewError(fiber.StatusBadRequest, "Name cannot be empty")
+			}
+			sets = append(sets, "name=$"+itoa(i))
+			args = append(args, name)
+			i++
+		}
+		if b.Email != nil {
+			email := strings.ToLower(strings.TrimSpace(*b.Email))
+			if email == "" {
+				return fiber.NewError(fiber.StatusBadRequest, "Email cannot be empty")
+			}
+			if err := hAuth.LockEmail(c.Context(), tx, email); err != nil {
+				return err
+			}
+			var exists int
+			err = tx.QueryRow(c.Context(), `
+				SELECT 1 FROM faculty WHERE lower(email) = $1 AND id != $2
+				UNION ALL
+				SELECT 1 FROM volunteers WHERE lower(email) = $1
+				LIMIT 1
+			`, email, id).Scan(&exists)
+			if err == nil {
+				return fiber.NewError(fiber.StatusConflict, "Email already registered")
+			} else if !errors.Is(err, sql.ErrNoRows) {
+				return err
+			}
+			sets = append(sets, "email=$"+itoa(i))
+			args = append(args, email)
+			i++
+		}
+		if b.Phone != nil {
+			var phone *string
+			if p := strings.TrimSpace(*b.Phone); p != "" {
+				phone = &p
+			}
+			sets = append(sets, "phone=$"+itoa(i))
+			args = append(args, phone)
+			i++
+		}
+		if b.Department != nil {
+			dept, err := hDepartments.Resolve(c.Context(), tx, b.Department)
+			if err != nil {
+				return err
+			}
+			sets = append(sets, "department=$"+itoa(i))
+			args = append(args, dept)
+			i++
+		}
+		if b.Role != nil {
+			r := models.UserRole(strings.ToLower(strings.TrimSpace(string(*b.Role))))
+			if r != models.UserRoleAdmin && r != models.UserRoleFaculty {
+				return fiber.NewError(fiber.StatusBadRequest, "Invalid role. Must be 'admin' or 'faculty'.")
+			}
+			if r != models.UserRoleAdmin {
+				var current models.UserRole
+				err := tx.QueryRow(c.Context(), `SELECT role FROM faculty WHERE id = $1`, id).Scan(&current)
+				if err != nil {
+					if errors.Is(err, sql.ErrNoRows) {
+						return fiber.NewError(fiber.StatusNotFound, "Faculty not found")
+					}
+					return err
+				}
+				if current == models.UserRoleAdmin {
+					if err := requireOtherAdmin(c.Context(), tx, "Cannot demote the last remaining admin account"); err != nil {
+						return err
+					}
+				}
+			}
+			sets = append(sets, "role=$"+itoa(i))
+			args = append(args, r)
+			i++
+		}
+
+		if len(sets) == 0 {
+			return fiber.NewError(fiber.StatusBadRequest, "No fields to update")
+		}
+		args = append(args, id)
+
+		cmd, err := tx.Exec(c.Context(), `UPDATE faculty SET `+strings.Join(sets, ", ")+` WHERE id=$`+itoa(i), args...)
+		if err != nil {
+			if db.IsUniqueViolation(err, db.ConstraintFacultyEmail) {
+				return fiber.NewError(fiber.StatusConflict, "Email already registered for a faculty account")
+			}
+			return err
+		}
+		if cmd.RowsAffected() == 0 {
+			return fiber.NewError(fiber.StatusNotFound, "Faculty not found")
+		}
+		if err := tx.Commit(c.Context()); err != nil {
+			return err
+		}
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+}
+
+// Del - DELETE /faculty/:id (Admin)
+// Revokes the account's refresh sessions and deletes it. The last remaining admin cannot be deleted.
+func Del(pool *pgxpool.Pool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil || id <= 0 {
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid faculty ID")
+		}
+
+		tx, err := pool.Begin(c.Context())
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback(c.Context())
+
+		var role models.UserRole
+		err = tx.QueryRow(c.Context(), `SELECT role FROM faculty WHERE id = $1`, id).Scan(&role)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fiber.NewError(fiber.StatusNotFound, "Faculty not found")
+			}
+			return err
+		}
+		if role == models.UserRoleAdmin {
+			if err := requireOtherAdmin(c.Context(), tx, "Cannot delete the last remaining admin account"); err != nil {
+				return err
+			}
+		}
+
+		if _, err := tx.Exec(c.Context(), `UPDATE auth_sessions SET revoked_at = NOW() WHERE faculty_id = $1 AND revoked_at IS NULL`, id); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(c.Context(), `DELETE FROM faculty WHERE id = $1`, id); err != nil {
+			return err
+		}
+		if err := tx.Commit(c.Context()); err != nil {
+			return err
+		}
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+}
+
+// ListEvents - GET /faculty/:id/events (Admin)
+// An empty list means the account is unscoped and can see every event.
+func ListEvents(pool *pgxpool.Pool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil || id <= 0 {
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid faculty ID")
+		}
+
+		rows, err := pool.Query(c.Context(), `
+			SELECT r.faculty_id, r.event_id, e.name, r.granted_by, r.granted_at
+			FROM faculty_event_roles r
+			JOIN events e ON e.id = r.event_id
+			WHERE r.faculty_id = $1
+			ORDER BY e.name
+		`, id)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		out := []models.FacultyEventRole{}
+		for rows.Next() {
+			var r models.FacultyEventRole
+			if err := rows.Scan(&r.FacultyID, &r.EventID, &r.EventName, &r.GrantedBy, &r.GrantedAt); err != nil {
+				return err
+			}
+			out = append(out, r)
+		}
+		return c.JSON(out)
+	}
+}
+
+// GrantEvent - POST /faculty/:id/events {event_id} (Admin)
+// Granting an event that is already granted is a no-op.
+func GrantEvent(pool *pgxpool.Pool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil || id <= 0 {
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid faculty ID")
+		}
+		var b models.GrantEventScopeRequest
+		if err := c.BodyParser(&b); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "Bad JSON")
+		}
+		if b.EventID <= 0 {
+			return fiber.NewError(fiber.StatusBadRequest, "event_id is required")
+		}
+		adminID, err := mw.GetUserIDFromClaims(c)
+		if err != nil {
+			return fiber.NewError(fiber.StatusUnauthorized, "Admin ID not found in token")
+		}
+
+		var role models.UserRole
+		err = pool.QueryRow(c.Context(), `SELECT role FROM faculty WHERE id = $1`, id).Scan(&role)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fiber.NewError(fiber.StatusNotFound, "Faculty not found")
+			}
+			return err
+		}
+		if role == models.UserRoleAdmin {
+			return fiber.NewError(fiber.StatusBadRequest, "Admins are not event-scoped")
+		}
+
+		_, err = pool.Exec(c.Context(), `
+			INSERT INTO faculty_event_roles (faculty_id, event_id, granted_by)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (faculty_id, event_id) DO NOTHING
+		`, id, b.EventID, adminID)
+		if err != nil {
+			if db.IsForeignKeyViolation(err, "") {
+				return fiber.NewError(fiber.StatusUnprocessableEntity, "Event not found")
+			}
+			return err
+		}
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+}
+
+// RevokeEvent - DELETE /faculty/:id/events/:event_id (Admin)
+// Revoking the last grant makes the account unscoped again (access to all events).
+func RevokeEvent(pool *pgxpool.Pool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil || id <= 0 {
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid faculty ID")
+		}
+		eventID, err := strconv.ParseInt(c.Params("event_id"), 10, 64)
+		if err != nil || eventID <= 0 {
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid event ID")
+		}
+
+		cmd, err := pool.Exec(c.Context(), `DELETE FROM faculty_event_roles WHERE faculty_id = $1 AND event_id = $2`, id, eventID)
+		if err != nil {
+			return err
+		}
+		if cmd.RowsAffected() == 0 {
+			return fiber.NewError(fiber.StatusNotFound, "Event scope not found")
+		}
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+}
+
+// requireOtherAdmin returns 409 with msg unless at least two admins exist. It locks every admin
+// row until tx ends, so two concurrent demotions/deletions can't both see "another admin left".
+func requireOtherAdmin(ctx context.Context, tx pgx.Tx, msg string) error {
+	rows, err := tx.Query(ctx, `SELECT id FROM faculty WHERE role = 'admin' FOR UPDATE`)
+	if err != nil {
+		return err
+	}
+	admins := 0
+	for rows.Next() {
+		admins++
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if admins <= 1 {
+		return fiber.NewError(fiber.StatusConflict, msg)
+	}
+	return nil
+}
+
+// Helpers
+func clampInt(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+func itoa(i int) string { return strconv.FormatInt(int64(i), 10) }
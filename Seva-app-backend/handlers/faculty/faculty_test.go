@@ -0,0 +1,34 @@
+package faculty
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"Seva-app-backend/db/dbtest"
+	"Seva-app-backend/models"
+)
+
+// testApp mounts the faculty handlers with the auth guards replaced by claims for user sub.
+func testApp(pool *pgxpool.Pool, sub int64, role models.UserRole) *fiber.App {
+	app := dbtest.App()
+	Register(app.Group("/faculty"), pool, dbtest.As(sub, role), dbtest.Pass)
+	return app
+}
+
+func TestEmptyListsAreArrays(t *testing.T) {
+	pool := dbtest.Migrated(t)
+	facultyID := dbtest.ID(t, pool, `INSERT INTO faculty (name) VALUES ('Prof') RETURNING id`)
+	app := testApp(pool, facultyID, models.UserRoleAdmin)
+	for _, p := range []string{
+		"/faculty/" + strconv.FormatInt(facultyID, 10) + "/events",
+	} {
+		code, body := dbtest.Do(t, app, "GET", p, "")
+		if code != fiber.StatusOK || strings.TrimSpace(string(body)) != "[]" {
+			t.Errorf("GET %s = %d %s, want 200 []", p, code, body)
+		}
+	}
+}
@@ -1,8 +1,14 @@
 package locations
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"log"
 	"strconv"
 	"strings"
@@ -12,22 +18,31 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
+	"Seva-app-backend/db"
+	mw "Seva-app-backend/middleware"
 	"Seva-app-backend/models" // Using models.ErrorResponse and other models
+	"Seva-app-backend/validation"
 )
 
 // Register mounts location routes under /locations
 func Register(g fiber.Router, pool *pgxpool.Pool, jwtGuard fiber.Handler, requireAdmin fiber.Handler) {
 	// Public read access (anyone can list/get locations, perhaps for event maps)
 	g.Get("/", ListLocations(pool))
+	g.Get("/nearest", NearestLocations(pool))
+	g.Get("/geojson", ExportGeoJSON(pool))
+	g.Get("/types", ListTypes())
 	g.Get("/:id", GetLocationByID(pool))
 
 	// Admin-only write access
 	g.Post("/", jwtGuard, requireAdmin, CreateLocation(pool))
+	g.Post("/bulk", jwtGuard, requireAdmin, mw.MaxBodySize(bulkImportMaxBytes()+mw.MultipartOverhead), BulkImportLocations(pool))
+	g.Get("/bulk/template.csv", jwtGuard, requireAdmin, BulkImportTemplate())
 	g.Put("/:id", jwtGuard, requireAdmin, UpdateLocation(pool))
 	g.Delete("/:id", jwtGuard, requireAdmin, DeleteLocation(pool))
 }
 
 // CreateLocation - POST /locations (Admin-only)
+// Invalid fields are a 422 validation_failed naming them.
 func CreateLocation(pool *pgxpool.Pool) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		req := new(models.CreateLocationRequest)
@@ -35,8 +50,21 @@ func CreateLocation(pool *pgxpool.Pool) fiber.Handler {
 			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{Error: "Invalid request body"})
 		}
 
-		if req.EventID == 0 || req.Name == "" || req.Type == "" || req.Lat == 0 || req.Lng == 0 {
-			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{Error: "Event ID, name, type, latitude, and longitude are required"})
+		req.Type = models.LocationType(strings.ToLower(strings.TrimSpace(string(req.Type))))
+		var v validation.Validator
+		v.Check(req.EventID > 0, "event_id", validation.FieldRequired)
+		v.Required("name", req.Name)
+		if v.Required("type", string(req.Type)) {
+			v.Check(models.ValidLocationType(req.Type), "type", validation.FieldInvalidValue)
+		}
+		if v.Check(req.Lat != nil, "lat", validation.FieldRequired) {
+			v.Check(*req.Lat >= -90 && *req.Lat <= 90, "lat", validation.FieldOutOfRange)
+		}
+		if v.Check(req.Lng != nil, "lng", validation.FieldRequired) {
+			v.Check(*req.Lng >= -180 && *req.Lng <= 180, "lng", validation.FieldOutOfRange)
+		}
+		if err := v.Err(); err != nil {
+			return err
 		}
 
 		ctx, cancel := context.WithTimeout(c.Context(), 5*time.Second)
@@ -46,14 +74,14 @@ func CreateLocation(pool *pgxpool.Pool) fiber.Handler {
 		err := pool.QueryRow(ctx, `
 			INSERT INTO locations (event_id, name, type, description, lat, lng)
 			VALUES ($1, $2, $3, $4, $5, $6)
-			RETURNING id, event_id, name, type, description, lat, lng
-		`, req.EventID, req.Name, req.Type, req.Description, req.Lat, req.Lng).Scan(
+			RETURNING id, event_id, name, type, COALESCE(description,''), lat, lng
+		`, req.EventID, req.Name, req.Type, req.Description, *req.Lat, *req.Lng).Scan(
 			&newLocation.ID, &newLocation.EventID, &newLocation.Name, &newLocation.Type,
 			&newLocation.Description, &newLocation.Lat, &newLocation.Lng,
 		)
 		if err != nil {
 			log.Printf("Error creating location: %v", err)
-			if strings.Contains(err.Error(), "locations_event_id_name_key") { // Check for unique constraint violation
+			if db.IsUniqueViolation(err, db.ConstraintLocationsEventName) {
 				return fiber.NewError(fiber.StatusConflict, "Location name already exists for this event")
 			}
 			return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{Error: "Failed to create location"})
@@ -63,7 +91,10 @@ func CreateLocation(pool *pgxpool.Pool) fiber.Handler {
 	}
 }
 
-// ListLocations - GET /locations?event_id= (Public)
+// ListLocations - GET /locations?event_id=&type=&near_lat=&near_lng=&radius_m=&min_lat=&max_lat=&min_lng=&max_lng= (Public)
+// With near_lat/near_lng, each location gets distance_m (haversine) and results are nearest first;
+// radius_m additionally drops anything farther away.
+// min_lat/max_lat/min_lng/max_lng (all four together) restrict results to a map viewport.
 func ListLocations(pool *pgxpool.Pool) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		ctx, cancel := context.WithTimeout(c.Context(), 5*time.Second)
@@ -79,14 +110,67 @@ func ListLocations(pool *pgxpool.Pool) fiber.Handler {
 			eventID = sql.NullInt64{Int64: id, Valid: true}
 		}
 
-		var locations []models.Location
+		args := []any{eventID}
+		whereConditions := []string{"($1::BIGINT IS NULL OR event_id = $1)"}
+
+		if t := strings.ToLower(strings.TrimSpace(c.Query("type"))); t != "" {
+			if !models.ValidLocationType(models.LocationType(t)) {
+				return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{Error: "Invalid type query parameter"})
+			}
+			args = append(args, t)
+			whereConditions = append(whereConditions, "type = $"+strconv.Itoa(len(args))+"::location_type")
+		}
+
+		// Optional bounding box for map viewports
+		if c.Query("min_lat") != "" || c.Query("max_lat") != "" || c.Query("min_lng") != "" || c.Query("max_lng") != "" {
+			minLat, err1 := strconv.ParseFloat(c.Query("min_lat"), 64)
+			maxLat, err2 := strconv.ParseFloat(c.Query("max_lat"), 64)
+			minLng, err3 := strconv.ParseFloat(c.Query("min_lng"), 64)
+			maxLng, err4 := strconv.ParseFloat(c.Query("max_lng"), 64)
+			if err1 != nil || err2 != nil || err3 != nil || err4 != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{Error: "min_lat, max_lat, min_lng and max_lng must all be numbers"})
+			}
+			if minLat >= maxLat || minLng >= maxLng {
+				return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{Error: "min_lat must be less than max_lat and min_lng less than max_lng"})
+			}
+			args = append(args, minLat, maxLat, minLng, maxLng)
+			n := len(args)
+			whereConditions = append(whereConditions,
+				"lat BETWEEN $"+strconv.Itoa(n-3)+" AND $"+strconv.Itoa(n-2)+" AND lng BETWEEN $"+strconv.Itoa(n-1)+" AND $"+strconv.Itoa(n))
+		}
+
+		// Optional nearby search: distance column, radius filter and distance ordering
+		distanceExpr := "NULL::DOUBLE PRECISION"
+		order := "ORDER BY name ASC"
+		if c.Query("near_lat") != "" || c.Query("near_lng") != "" {
+			nearLat, err1 := strconv.ParseFloat(c.Query("near_lat"), 64)
+			nearLng, err2 := strconv.ParseFloat(c.Query("near_lng"), 64)
+			if err1 != nil || err2 != nil || nearLat < -90 || nearLat > 90 || nearLng < -180 || nearLng > 180 {
+				return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{Error: "near_lat and near_lng must both be valid coordinates"})
+			}
+			args = append(args, nearLat, nearLng)
+			distanceExpr = haversineSQL("$"+strconv.Itoa(len(args)-1), "$"+strconv.Itoa(len(args)))
+			order = "ORDER BY distance_m ASC, name ASC"
+
+			if r := c.Query("radius_m"); r != "" {
+				radius, err := strconv.ParseFloat(r, 64)
+				if err != nil || radius <= 0 {
+					return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{Error: "radius_m must be a positive number"})
+				}
+				args = append(args, radius)
+				whereConditions = append(whereConditions, distanceExpr+" <= $"+strconv.Itoa(len(args)))
+			}
+		} else if c.Query("radius_m") != "" {
+			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{Error: "radius_m requires near_lat and near_lng"})
+		}
+
+		locations := []models.Location{}
 		query := `
-			SELECT id, event_id, name, type, description, lat, lng
+			SELECT id, event_id, name, type, COALESCE(description,''), lat, lng, ` + distanceExpr + ` AS distance_m
 			FROM locations
-			WHERE ($1::BIGINT IS NULL OR event_id = $1)
-			ORDER BY name ASC
-		`
-		rows, err := pool.Query(ctx, query, eventID)
+			WHERE ` + strings.Join(whereConditions, " AND ") + `
+			` + order
+		rows, err := pool.Query(ctx, query, args...)
 		if err != nil {
 			log.Printf("Error querying locations: %v", err)
 			return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{Error: "Failed to retrieve locations"})
@@ -97,7 +181,7 @@ func ListLocations(pool *pgxpool.Pool) fiber.Handler {
 			var location models.Location
 			err := rows.Scan(
 				&location.ID, &location.EventID, &location.Name, &location.Type,
-				&location.Description, &location.Lat, &location.Lng,
+				&location.Description, &location.Lat, &location.Lng, &location.DistanceM,
 			)
 			if err != nil {
 				log.Printf("Error scanning location row: %v", err)
@@ -115,6 +199,143 @@ func ListLocations(pool *pgxpool.Pool) fiber.Handler {
 	}
 }
 
+// NearestLocations - GET /locations/nearest?lat=&lng=&event_id=&type=&limit=5 (Public)
+// Closest locations to the given point (optionally of one type), nearest first, with distance_m.
+func NearestLocations(pool *pgxpool.Pool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		lat, err1 := strconv.ParseFloat(c.Query("lat"), 64)
+		lng, err2 := strconv.ParseFloat(c.Query("lng"), 64)
+		if err1 != nil || err2 != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{Error: "lat and lng are required"})
+		}
+		if lat < -90 || lat > 90 || lng < -180 || lng > 180 {
+			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{Error: "lat must be within -90..90 and lng within -180..180"})
+		}
+		limit := c.QueryInt("limit", 5)
+		if limit < 1 {
+			limit = 1
+		} else if limit > 50 {
+			limit = 50
+		}
+
+		args := []any{lat, lng, limit}
+		whereConditions := []string{"TRUE"}
+		if eventIDStr := c.Query("event_id"); eventIDStr != "" {
+			eventID, err := strconv.ParseInt(eventIDStr, 10, 64)
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{Error: "Invalid event_id query parameter"})
+			}
+			args = append(args, eventID)
+			whereConditions = append(whereConditions, "event_id = $"+strconv.Itoa(len(args)))
+		}
+		if t := strings.ToLower(strings.TrimSpace(c.Query("type"))); t != "" {
+			if !models.ValidLocationType(models.LocationType(t)) {
+				return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{Error: "Invalid type query parameter"})
+			}
+			args = append(args, t)
+			whereConditions = append(whereConditions, "type = $"+strconv.Itoa(len(args))+"::location_type")
+		}
+
+		ctx, cancel := context.WithTimeout(c.Context(), 5*time.Second)
+		defer cancel()
+
+		rows, err := pool.Query(ctx, `
+			SELECT id, event_id, name, type, COALESCE(description,''), lat, lng, `+haversineSQL("$1", "$2")+` AS distance_m
+			FROM locations
+			WHERE `+strings.Join(whereConditions, " AND ")+`
+			ORDER BY distance_m ASC, name ASC
+			LIMIT $3
+		`, args...)
+		if err != nil {
+			log.Printf("Error querying nearest locations: %v", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{Error: "Failed to retrieve locations"})
+		}
+		defer rows.Close()
+
+		locations := []models.Location{}
+		for rows.Next() {
+			var location models.Location
+			if err := rows.Scan(
+				&location.ID, &location.EventID, &location.Name, &location.Type,
+				&location.Description, &location.Lat, &location.Lng, &location.DistanceM,
+			); err != nil {
+				log.Printf("Error scanning location row: %v", err)
+				return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{Error: "Failed to process location data"})
+			}
+			locations = append(locations, location)
+		}
+		if err := rows.Err(); err != nil {
+			log.Printf("Error iterating location rows: %v", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{Error: "Failed to retrieve locations"})
+		}
+
+		return c.JSON(locations)
+	}
+}
+
+// ExportGeoJSON - GET /locations/geojson?event_id= (Public)
+// Same data as ListLocations, as a GeoJSON FeatureCollection of Points for Leaflet/Mapbox.
+func ExportGeoJSON(pool *pgxpool.Pool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var eventID sql.NullInt64
+		if eventIDStr := c.Query("event_id"); eventIDStr != "" {
+			id, err := strconv.ParseInt(eventIDStr, 10, 64)
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{Error: "Invalid event_id query parameter"})
+			}
+			eventID = sql.NullInt64{Int64: id, Valid: true}
+		}
+
+		ctx, cancel := context.WithTimeout(c.Context(), 5*time.Second)
+		defer cancel()
+
+		rows, err := pool.Query(ctx, `
+			SELECT id, name, type, description, lat, lng
+			FROM locations
+			WHERE ($1::BIGINT IS NULL OR event_id = $1)
+			ORDER BY name ASC
+		`, eventID)
+		if err != nil {
+			log.Printf("Error querying locations for GeoJSON: %v", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{Error: "Failed to retrieve locations"})
+		}
+		defer rows.Close()
+
+		fc := models.LocationFeatureCollection{Type: "FeatureCollection", Features: []models.LocationFeature{}}
+		for rows.Next() {
+			var p models.LocationProperties
+			var lat, lng float64
+			if err := rows.Scan(&p.ID, &p.Name, &p.Type, &p.Description, &lat, &lng); err != nil {
+				log.Printf("Error scanning location row: %v", err)
+				return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{Error: "Failed to process location data"})
+			}
+			fc.Features = append(fc.Features, models.LocationFeature{
+				Type:       "Feature",
+				Geometry:   models.GeoJSONPoint{Type: "Point", Coordinates: [2]float64{lng, lat}},
+				Properties: p,
+			})
+		}
+		if err := rows.Err(); err != nil {
+			log.Printf("Error iterating location rows: %v", err)
+			return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{Error: "Failed to retrieve locations"})
+		}
+
+		return c.JSON(fc, "application/geo+json")
+	}
+}
+
+// ListTypes - GET /locations/types (Public)
+// Valid location types with display labels, so map UIs don't hardcode the list.
+func ListTypes() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		out := make([]fiber.Map, 0, len(models.LocationTypes))
+		for _, t := range models.LocationTypes {
+			out = append(out, fiber.Map{"value": t, "label": t.Label()})
+		}
+		return c.JSON(out)
+	}
+}
+
 // GetLocationByID - GET /locations/:id (Public)
 func GetLocationByID(pool *pgxpool.Pool) fiber.Handler {
 	return func(c *fiber.Ctx) error {
@@ -129,7 +350,7 @@ func GetLocationByID(pool *pgxpool.Pool) fiber.Handler {
 
 		var location models.Location
 		err = pool.QueryRow(ctx, `
-			SELECT id, event_id, name, type, description, lat, lng
+			SELECT id, event_id, name, type, COALESCE(description,''), lat, lng
 			FROM locations WHERE id = $1
 		`, locationID).Scan(
 			&location.ID, &location.EventID, &location.Name, &location.Type,
@@ -148,6 +369,8 @@ func GetLocationByID(pool *pgxpool.Pool) fiber.Handler {
 }
 
 // UpdateLocation - PUT /locations/:id (Admin-only)
+// Only description can be cleared: send "description": null (or ""). name, type, lat and lng are
+// required columns, so null for those means "no change". Invalid fields are a 422 validation_failed.
 func UpdateLocation(pool *pgxpool.Pool) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		locationIDStr := c.Params("id")
@@ -161,15 +384,36 @@ func UpdateLocation(pool *pgxpool.Pool) fiber.Handler {
 			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{Error: "Invalid request body"})
 		}
 
+		var v validation.Validator
+		if req.Name != nil {
+			v.Required("name", *req.Name)
+		}
+		var t models.LocationType
+		if req.Type != nil {
+			t = models.LocationType(strings.ToLower(strings.TrimSpace(string(*req.Type))))
+			v.Check(models.ValidLocationType(t), "type", validation.FieldInvalidValue)
+		}
+		if req.Lat != nil {
+			v.Check(*req.Lat >= -90 && *req.Lat <= 90, "lat", validation.FieldOutOfRange)
+		}
+		if req.Lng != nil {
+			v.Check(*req.Lng >= -180 && *req.Lng <= 180, "lng", validation.FieldOutOfRange)
+		}
+		if err := v.Err(); err != nil {
+			return err
+		}
+
 		updates := make(map[string]interface{})
 		if req.Name != nil {
 			updates["name"] = *req.Name
 		}
 		if req.Type != nil {
-			updates["type"] = *req.Type
+			updates["type"] = t
 		}
-		if req.Description != nil {
+		if req.Description != nil && strings.TrimSpace(*req.Description) != "" {
 			updates["description"] = *req.Description
+		} else if req.Description != nil || jsonFieldIsNull(c.Body(), "description") {
+			updates["description"] = nil
 		}
 		if req.Lat != nil {
 			updates["lat"] = *req.Lat
@@ -201,7 +445,7 @@ func UpdateLocation(pool *pgxpool.Pool) fiber.Handler {
 		cmdTag, err := pool.Exec(ctx, query, args...)
 		if err != nil {
 			log.Printf("Error updating location %d: %v", locationID, err)
-			if strings.Contains(err.Error(), "locations_event_id_name_key") {
+			if db.IsUniqueViolation(err, db.ConstraintLocationsEventName) {
 				return fiber.NewError(fiber.StatusConflict, "Location name already exists for this event")
 			}
 			return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{Error: "Failed to update location"})
@@ -239,3 +483,273 @@ func DeleteLocation(pool *pgxpool.Pool) fiber.Handler {
 		return c.JSON(fiber.Map{"message": "Location deleted successfully", "id": locationID})
 	}
 }
+
+// BulkImportLocations - POST /locations/bulk?event_id= (Admin-only)
+// Multipart "file" is either a CSV with a name,type,description,lat,lng header or a GeoJSON
+// FeatureCollection of Points (properties carry name/type/description). Invalid rows are
+// reported per line (CSV) or per feature index (GeoJSON, 1-based) and skipped; the rest are
+// inserted in a single transaction.
+func BulkImportLocations(pool *pgxpool.Pool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		eventID, err := strconv.ParseInt(c.Query("event_id", ""), 10, 64)
+		if err != nil || eventID <= 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{Error: "event_id is required"})
+		}
+
+		formFile, err := c.FormFile("file")
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{Error: "file is required"})
+		}
+		if max := bulkImportMaxBytes(); formFile.Size > max {
+			return c.Status(fiber.StatusRequestEntityTooLarge).JSON(models.ErrorResponse{Error: fmt.Sprintf("file exceeds the %d byte limit", max)})
+		}
+		f, err := formFile.Open()
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		data, err := io.ReadAll(f)
+		if err != nil {
+			return err
+		}
+
+		var rows []bulkLocationRow
+		if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+			rows, err = parseGeoJSONLocations(trimmed)
+		} else {
+			rows, err = parseCSVLocations(data)
+		}
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{Error: err.Error()})
+		}
+
+		ctx, cancel := context.WithTimeout(c.Context(), 30*time.Second)
+		defer cancel()
+
+		tx, err := pool.Begin(ctx)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback(ctx)
+
+		var eventExists bool
+		if err := tx.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM events WHERE id = $1)`, eventID).Scan(&eventExists); err != nil {
+			return err
+		}
+		if !eventExists {
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(models.ErrorResponse{Error: "Event not found"})
+		}
+
+		type rowErr struct {
+			line int
+			msg  string
+		}
+		var rowErrors []rowErr
+		created := []models.Location{}
+
+		for _, r := range rows {
+			if r.err != "" {
+				rowErrors = append(rowErrors, rowErr{r.line, r.err})
+				continue
+			}
+			if msg := r.validate(); msg != "" {
+				rowErrors = append(rowErrors, rowErr{r.line, msg})
+				continue
+			}
+
+			var loc models.Location
+			err := tx.QueryRow(ctx, `
+				INSERT INTO locations (event_id, name, type, description, lat, lng)
+				VALUES ($1, $2, $3::location_type, $4, $5, $6)
+				ON CONFLICT (event_id, name) DO NOTHING
+				RETURNING id, event_id, name, type, COALESCE(description,''), lat, lng
+			`, eventID, r.name, r.typ, r.description, r.lat, r.lng).Scan(
+				&loc.ID, &loc.EventID, &loc.Name, &loc.Type, &loc.Description, &loc.Lat, &loc.Lng,
+			)
+			if errors.Is(err, pgx.ErrNoRows) {
+				rowErrors = append(rowErrors, rowErr{r.line, fmt.Sprintf("location '%s' already exists for this event", r.name)})
+				continue
+			}
+			if err != nil {
+				log.Printf("Error bulk inserting location (line %d): %v", r.line, err)
+				return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{Error: "Failed to import locations"})
+			}
+			created = append(created, loc)
+		}
+
+		if err := tx.Commit(ctx); err != nil {
+			return err
+		}
+
+		errs := make([]fiber.Map, 0, len(rowErrors))
+		for _, e := range rowErrors {
+			errs = append(errs, fiber.Map{"line": e.line, "error": e.msg})
+		}
+		return c.JSON(fiber.Map{
+			"created":   len(created),
+			"locations": created,
+			"errors":    errs,
+		})
+	}
+}
+
+// bulkLocationRow is one parsed CSV line or GeoJSON feature. err holds a parse problem for that row.
+type bulkLocationRow struct {
+	line        int
+	name        string
+	typ         string
+	description *string
+	lat, lng    float64
+	err         string
+}
+
+func (r bulkLocationRow) validate() string {
+	if r.name == "" {
+		return "missing name"
+	}
+	if !models.ValidLocationType(models.LocationType(r.typ)) {
+		return fmt.Sprintf("unknown location type '%s'", r.typ)
+	}
+	if r.lat < -90 || r.lat > 90 || r.lng < -180 || r.lng > 180 {
+		return "coordinates out of range"
+	}
+	return ""
+}
+
+// bulkImportColumns is the CSV header BulkImportLocations reads; description is optional.
+var bulkImportColumns = []string{"name", "type", "description", "lat", "lng"}
+
+// BulkImportTemplate - GET /locations/bulk/template.csv (Admin-only)
+// Returns an empty CSV with the header row BulkImportLocations expects.
+func BulkImportTemplate() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Set("Content-Type", "text/csv")
+		c.Set("Content-Disposition", `attachment; filename="locations_template.csv"`)
+		w := csv.NewWriter(c.Response().BodyWriter())
+		if err := w.Write(bulkImportColumns); err != nil {
+			return err
+		}
+		w.Flush()
+		return w.Error()
+	}
+}
+
+// bulkImportMaxBytes caps the BulkImportLocations file (BULK_UPLOAD_MAX_BYTES, default 5 MiB, shared
+// with the volunteer CSV upload).
+func bulkImportMaxBytes() int64 { return mw.EnvBytes("BULK_UPLOAD_MAX_BYTES", 5<<20) }
+
+func parseCSVLocations(data []byte) ([]bulkLocationRow, error) {
+	rd := csv.NewReader(bytes.NewReader(data))
+	rd.FieldsPerRecord = -1
+
+	header, err := rd.Read()
+	if err != nil {
+		return nil, errors.New("empty or invalid csv")
+	}
+	idx := map[string]int{}
+	for i, h := range header {
+		idx[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+	for _, col := range []string{"name", "type", "lat", "lng"} {
+		if _, ok := idx[col]; !ok {
+			return nil, fmt.Errorf("csv is missing the '%s' column", col)
+		}
+	}
+	get := func(rec []string, key string) string {
+		i, ok := idx[key]
+		if !ok || i >= len(rec) {
+			return ""
+		}
+		return strings.TrimSpace(rec[i])
+	}
+
+	var rows []bulkLocationRow
+	line := 1 // header
+	for {
+		rec, err := rd.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		line++
+		if err != nil {
+			rows = append(rows, bulkLocationRow{line: line, err: fmt.Sprintf("read error: %v", err)})
+			continue
+		}
+
+		r := bulkLocationRow{
+			line: line,
+			name: get(rec, "name"),
+			typ:  strings.ToLower(get(rec, "type")),
+		}
+		if d := get(rec, "description"); d != "" {
+			r.description = &d
+		}
+		lat, err1 := strconv.ParseFloat(get(rec, "lat"), 64)
+		lng, err2 := strconv.ParseFloat(get(rec, "lng"), 64)
+		if err1 != nil || err2 != nil {
+			r.err = "lat and lng must be numbers"
+		}
+		r.lat, r.lng = lat, lng
+		rows = append(rows, r)
+	}
+	return rows, nil
+}
+
+func parseGeoJSONLocations(data []byte) ([]bulkLocationRow, error) {
+	var fc struct {
+		Type     string `json:"type"`
+		Features []struct {
+			Geometry *struct {
+				Type        string    `json:"type"`
+				Coordinates []float64 `json:"coordinates"`
+			} `json:"geometry"`
+			Properties struct {
+				Name        string  `json:"name"`
+				Type        string  `json:"type"`
+				Description *string `json:"description"`
+			} `json:"properties"`
+		} `json:"features"`
+	}
+	if err := json.Unmarshal(data, &fc); err != nil || fc.Type != "FeatureCollection" {
+		return nil, errors.New("invalid GeoJSON: expected a FeatureCollection")
+	}
+
+	rows := make([]bulkLocationRow, 0, len(fc.Features))
+	for i, feat := range fc.Features {
+		r := bulkLocationRow{
+			line:        i + 1,
+			name:        strings.TrimSpace(feat.Properties.Name),
+			typ:         strings.ToLower(strings.TrimSpace(feat.Properties.Type)),
+			description: feat.Properties.Description,
+		}
+		// GeoJSON positions are [longitude, latitude]
+		if feat.Geometry == nil || feat.Geometry.Type != "Point" || len(feat.Geometry.Coordinates) < 2 {
+			r.err = "geometry must be a Point"
+		} else {
+			r.lng, r.lat = feat.Geometry.Coordinates[0], feat.Geometry.Coordinates[1]
+		}
+		rows = append(rows, r)
+	}
+	return rows, nil
+}
+
+// jsonFieldIsNull reports whether body has key set to an explicit JSON null, which BodyParser
+// can't distinguish from an absent field.
+func jsonFieldIsNull(body []byte, key string) bool {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return false
+	}
+	v, ok := raw[key]
+	return ok && string(bytes.TrimSpace(v)) == "null"
+}
+
+// haversineSQL returns a SQL expression for the great-circle distance in metres between
+// each row's (lat, lng) and the point given by the latParam/lngParam placeholders.
+func haversineSQL(latParam, lngParam string) string {
+	return `(2 * 6371000 * asin(sqrt(
+		power(sin(radians(lat - ` + latParam + `::DOUBLE PRECISION) / 2), 2) +
+		cos(radians(` + latParam + `::DOUBLE PRECISION)) * cos(radians(lat)) *
+		power(sin(radians(lng - ` + lngParam + `::DOUBLE PRECISION) / 2), 2)
+	)))`
+}
@@ -0,0 +1,142 @@
+package locations
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"Seva-app-backend/db/dbtest"
+	"Seva-app-backend/models"
+	"Seva-app-backend/validation"
+)
+
+// testApp mounts the location handlers without the auth guards.
+func testApp(pool *pgxpool.Pool) *fiber.App {
+	app := dbtest.App()
+	Register(app.Group("/locations"), pool, dbtest.Pass, dbtest.Pass)
+	return app
+}
+
+func TestNullDescriptionReadsAsEmpty(t *testing.T) {
+	pool := dbtest.Migrated(t)
+	app := testApp(pool)
+	eventID := dbtest.ID(t, pool, `INSERT INTO events (name) VALUES ('Test event') RETURNING id`)
+	id := dbtest.ID(t, pool, `
+		INSERT INTO locations (event_id, name, type, description, lat, lng)
+		VALUES ($1, 'Gate', 'poi', 'Main gate', 9.09, 76.49) RETURNING id`, eventID)
+	path := "/locations/" + strconv.FormatInt(id, 10)
+
+	// Clearing the description stores NULL
+	if code, body := dbtest.Do(t, app, "PUT", path, `{"description": null}`); code != fiber.StatusOK {
+		t.Fatalf("PUT = %d %s", code, body)
+	}
+
+	code, body := dbtest.Do(t, app, "GET", path, "")
+	if code != fiber.StatusOK {
+		t.Fatalf("GET /locations/:id = %d %s", code, body)
+	}
+	var loc models.Location
+	if err := json.Unmarshal(body, &loc); err != nil {
+		t.Fatal(err)
+	}
+	if loc.Description != "" {
+		t.Fatalf("description = %q, want empty", loc.Description)
+	}
+
+	for _, p := range []string{
+		"/locations?event_id=" + strconv.FormatInt(eventID, 10),
+		"/locations/nearest?lat=9.09&lng=76.49",
+		"/locations/geojson",
+	} {
+		if code, body := dbtest.Do(t, app, "GET", p, ""); code != fiber.StatusOK {
+			t.Fatalf("GET %s = %d %s", p, code, body)
+		}
+	}
+}
+
+func TestBulkImportWithoutDescription(t *testing.T) {
+	pool := dbtest.Migrated(t)
+	app := testApp(pool)
+	eventID := dbtest.ID(t, pool, `INSERT INTO events (name) VALUES ('Test event') RETURNING id`)
+
+	body := "--b\r\n" +
+		`Content-Disposition: form-data; name="file"; filename="locations.csv"` + "\r\n" +
+		"Content-Type: text/csv\r\n\r\n" +
+		"name,type,description,lat,lng\nWater point,water,,9.1,76.5\n" +
+		"\r\n--b--\r\n"
+	req := httptest.NewRequest("POST", "/locations/bulk?event_id="+strconv.FormatInt(eventID, 10), strings.NewReader(body))
+	req.Header.Set(fiber.HeaderContentType, "multipart/form-data; boundary=b")
+	res, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	b, _ := io.ReadAll(res.Body)
+	if res.StatusCode != fiber.StatusOK {
+		t.Fatalf("bulk import = %d %s", res.StatusCode, b)
+	}
+	var out struct {
+		Created int `json:"created"`
+	}
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Created != 1 {
+		t.Fatalf("created = %d, want 1 (%s)", out.Created, b)
+	}
+}
+
+func TestEmptyListsAreArrays(t *testing.T) {
+	pool := dbtest.Migrated(t)
+	app := testApp(pool)
+	eventID := dbtest.ID(t, pool, `INSERT INTO events (name) VALUES ('Test event') RETURNING id`)
+	for _, p := range []string{
+		"/locations?event_id=" + strconv.FormatInt(eventID, 10),
+		"/locations/nearest?lat=9.09&lng=76.49&event_id=" + strconv.FormatInt(eventID, 10),
+	} {
+		code, body := dbtest.Do(t, app, "GET", p, "")
+		if code != fiber.StatusOK || strings.TrimSpace(string(body)) != "[]" {
+			t.Errorf("GET %s = %d %s, want 200 []", p, code, body)
+		}
+	}
+}
+
+func TestCreateAtEquatorAndPrimeMeridian(t *testing.T) {
+	pool := dbtest.Migrated(t)
+	app := testApp(pool)
+	eventID := dbtest.ID(t, pool, `INSERT INTO events (name) VALUES ('Test event') RETURNING id`)
+
+	body := `{"event_id": ` + strconv.FormatInt(eventID, 10) + `, "name": "Null Island", "type": "poi", "lat": 0, "lng": 0}`
+	code, b := dbtest.Do(t, app, "POST", "/locations", body)
+	if code != fiber.StatusCreated {
+		t.Fatalf("POST /locations at 0,0 = %d %s", code, b)
+	}
+	var loc models.Location
+	if err := json.Unmarshal(b, &loc); err != nil {
+		t.Fatal(err)
+	}
+	if loc.Lat != 0 || loc.Lng != 0 {
+		t.Fatalf("created at %v,%v, want 0,0", loc.Lat, loc.Lng)
+	}
+}
+
+func TestCreateRequiresCoordinates(t *testing.T) {
+	app := testApp(nil) // rejected before any query
+	code, b := dbtest.Do(t, app, "POST", "/locations", `{"event_id": 1, "name": "Gate", "type": "poi"}`)
+	if code != fiber.StatusUnprocessableEntity {
+		t.Fatalf("POST /locations without lat/lng = %d %s, want 422", code, b)
+	}
+	var res models.ValidationErrorResponse
+	if err := json.Unmarshal(b, &res); err != nil {
+		t.Fatal(err)
+	}
+	if res.Fields["lat"] != validation.FieldRequired || res.Fields["lng"] != validation.FieldRequired {
+		t.Fatalf("fields = %v, want lat and lng required", res.Fields)
+	}
+}
@@ -1,6 +1,10 @@
 package questions
 
 import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
 	"strconv"
 	"strings"
 	"time"
@@ -8,27 +12,42 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/jackc/pgx/v5/pgxpool"
 
+	"Seva-app-backend/db"
+	"Seva-app-backend/email"
 	mw "Seva-app-backend/middleware"
 	"Seva-app-backend/models"
+	"Seva-app-backend/notify"
+	"Seva-app-backend/validation"
 )
 
 // Register mounts question routes under /questions
-func Register(g fiber.Router, pool *pgxpool.Pool, jwtGuard fiber.Handler, requireAdmin fiber.Handler, requireVolunteer fiber.Handler) {
+// publicLimiter throttles the anonymous FAQ listing.
+func Register(g fiber.Router, pool *pgxpool.Pool, jwtGuard fiber.Handler, requireAdmin fiber.Handler, requireFaculty fiber.Handler, requireVolunteer fiber.Handler, publicLimiter fiber.Handler, notifier *notify.Dispatcher, mailer email.Sender) {
 	// Volunteer Endpoints
 	g.Post("/", jwtGuard, requireVolunteer, AskQuestion(pool))
 	g.Get("/me", jwtGuard, requireVolunteer, ListMyQuestions(pool))
-	g.Get("/answered", ListAnsweredQuestions(pool)) // Public/Logged-in can see general FAQ
+	g.Get("/me/unanswered-count", jwtGuard, requireVolunteer, MyUnansweredCount(pool))
+	g.Get("/answered", publicLimiter, ListAnsweredQuestions(pool)) // Public/Logged-in can see general FAQ
+	g.Post("/:id/vote", jwtGuard, requireVolunteer, VoteQuestion(pool))
+
+	// Faculty Endpoints
+	g.Get("/assigned/me", jwtGuard, requireFaculty, ListAssignedToMe(pool))
+	g.Put("/:id/answer", jwtGuard, requireFaculty, AnswerQuestion(pool, notifier, mailer)) // Admins, or the faculty member it is assigned to
 
 	// Admin Endpoints
 	g.Get("/all", jwtGuard, requireAdmin, ListAllQuestions(pool))
 	g.Get("/pending", jwtGuard, requireAdmin, ListPendingQuestions(pool))
-	g.Put("/:id/answer", jwtGuard, requireAdmin, AnswerQuestion(pool))
+	g.Post("/:id/reopen", jwtGuard, requireAdmin, ReopenQuestion(pool))
+	g.Post("/:id/assign", jwtGuard, requireAdmin, AssignQuestion(pool))
 	g.Delete("/:id", jwtGuard, requireAdmin, DeleteQuestion(pool))
 }
 
 // AskQuestion - POST /questions (Volunteer)
 func AskQuestion(pool *pgxpool.Pool) fiber.Handler {
 	return func(c *fiber.Ctx) error {
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
 		volunteerID, err := mw.GetUserIDFromClaims(c)
 		if err != nil {
 			return fiber.NewError(fiber.StatusUnauthorized, "Volunteer ID not found in token")
@@ -41,19 +60,30 @@ func AskQuestion(pool *pgxpool.Pool) fiber.Handler {
 		if strings.TrimSpace(req.QuestionText) == "" {
 			return fiber.NewError(fiber.StatusBadRequest, "Question text is required")
 		}
+		var v validation.Validator
+		v.MaxLen("question_text", req.QuestionText, models.MaxQuestionLen)
+		if err := v.Err(); err != nil {
+			return err
+		}
+		category, ok := normCategory(string(req.Category))
+		if !ok {
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid category")
+		}
 
 		var newQuestion models.Question
-		err = pool.QueryRow(c.Context(), `
-			INSERT INTO questions(volunteer_id, question_text, event_id, committee_id)
-			VALUES ($1, $2, $3, $4)
-			RETURNING id, volunteer_id, question_text, asked_at, event_id, committee_id
-		`, volunteerID, req.QuestionText, req.EventID, req.CommitteeID).Scan(
+		var categoryStr string
+		err = pool.QueryRow(ctx, `
+			INSERT INTO questions(volunteer_id, question_text, event_id, committee_id, category)
+			VALUES ($1, $2, $3, $4, $5::question_category)
+			RETURNING id, volunteer_id, question_text, asked_at, event_id, committee_id, category::text
+		`, volunteerID, req.QuestionText, req.EventID, req.CommitteeID, category).Scan(
 			&newQuestion.ID, &newQuestion.VolunteerID, &newQuestion.QuestionText, &newQuestion.AskedAt,
-			&newQuestion.EventID, &newQuestion.CommitteeID,
+			&newQuestion.EventID, &newQuestion.CommitteeID, &categoryStr,
 		)
 		if err != nil {
 			return err
 		}
+		newQuestion.Category = models.QuestionCategory(categoryStr)
 		return c.Status(fiber.StatusCreated).JSON(newQuestion)
 	}
 }
@@ -61,6 +91,9 @@ func AskQuestion(pool *pgxpool.Pool) fiber.Handler {
 // ListMyQuestions - GET /questions/me (Volunteer)
 func ListMyQuestions(pool *pgxpool.Pool) fiber.Handler {
 	return func(c *fiber.Ctx) error {
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
 		volunteerID, err := mw.GetUserIDFromClaims(c)
 		if err != nil {
 			return fiber.NewError(fiber.StatusUnauthorized, "Volunteer ID not found in token")
@@ -69,12 +102,15 @@ func ListMyQuestions(pool *pgxpool.Pool) fiber.Handler {
 		limit := clampInt(c.QueryInt("limit", 100), 1, 500)
 		offset := maxInt(c.QueryInt("offset", 0), 0)
 
-		rows, err := pool.Query(c.Context(), `
+		rows, err := pool.Query(ctx, `
 			SELECT q.id, q.volunteer_id, v.name, q.question_text, q.asked_at,
-				   q.event_id, q.committee_id, q.answered_by, f.name, q.answer_text, q.answered_at
+				   q.event_id, q.committee_id, q.answered_by, f.name, q.answer_text, q.answered_at, q.category::text,
+				   (SELECT COUNT(*) FROM question_votes qv WHERE qv.question_id = q.id) AS vote_count,
+				   q.assigned_to, fa.name, q.assigned_at
 			FROM questions q
 			JOIN volunteers v ON v.id = q.volunteer_id
 			LEFT JOIN faculty f ON f.id = q.answered_by
+			LEFT JOIN faculty fa ON fa.id = q.assigned_to
 			WHERE q.volunteer_id = $1
 			ORDER BY q.asked_at DESC
 			LIMIT $2 OFFSET $3
@@ -87,35 +123,84 @@ func ListMyQuestions(pool *pgxpool.Pool) fiber.Handler {
 		questions := []models.Question{}
 		for rows.Next() {
 			var q models.Question
+			var categoryStr string
 			if err := rows.Scan(
 				&q.ID, &q.VolunteerID, &q.VolunteerName, &q.QuestionText, &q.AskedAt,
-				&q.EventID, &q.CommitteeID, &q.AnsweredBy, &q.AnsweredByName, &q.AnswerText, &q.AnsweredAt,
+				&q.EventID, &q.CommitteeID, &q.AnsweredBy, &q.AnsweredByName, &q.AnswerText, &q.AnsweredAt, &categoryStr, &q.VoteCount,
+				&q.AssignedTo, &q.AssignedToName, &q.AssignedAt,
 			); err != nil {
 				return err
 			}
+			q.Category = models.QuestionCategory(categoryStr)
 			questions = append(questions, q)
 		}
 		return c.JSON(questions)
 	}
 }
 
-// ListAnsweredQuestions - GET /questions/answered (Public/Volunteer)
+// MyUnansweredCount - GET /questions/me/unanswered-count (Volunteer)
+// Number of the volunteer's questions still awaiting an answer, for badging in the app.
+func MyUnansweredCount(pool *pgxpool.Pool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
+		volunteerID, err := mw.GetUserIDFromClaims(c)
+		if err != nil {
+			return fiber.NewError(fiber.StatusUnauthorized, "Volunteer ID not found in token")
+		}
+
+		var count int
+		err = pool.QueryRow(ctx, `
+			SELECT COUNT(*) FROM questions WHERE volunteer_id = $1 AND answer_text IS NULL
+		`, volunteerID).Scan(&count)
+		if err != nil {
+			return err
+		}
+		return c.JSON(fiber.Map{"count": count})
+	}
+}
+
+// ListAnsweredQuestions - GET /questions/answered?event_id=&committee_id=&sort=recent|popular&limit=&offset= (Public/Volunteer)
 // Shows all questions that have been answered. Can be used as a public FAQ.
+// sort=popular orders by vote_count so the most-needed answers come first.
 func ListAnsweredQuestions(pool *pgxpool.Pool) fiber.Handler {
 	return func(c *fiber.Ctx) error {
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
 		limit := clampInt(c.QueryInt("limit", 100), 1, 500)
 		offset := maxInt(c.QueryInt("offset", 0), 0)
 
-		rows, err := pool.Query(c.Context(), `
+		order := "ORDER BY q.answered_at DESC"
+		switch strings.ToLower(c.Query("sort", "recent")) {
+		case "recent":
+		case "popular":
+			order = "ORDER BY vote_count DESC, q.answered_at DESC"
+		default:
+			return fiber.NewError(fiber.StatusBadRequest, "sort must be 'recent' or 'popular'")
+		}
+
+		whereConditions := []string{"q.answer_text IS NOT NULL"}
+		args := []any{limit, offset}
+		whereConditions, args, err := appendContextFilters(c, whereConditions, args)
+		if err != nil {
+			return err
+		}
+
+		rows, err := pool.Query(ctx, `
 			SELECT q.id, q.volunteer_id, v.name, q.question_text, q.asked_at,
-				   q.event_id, q.committee_id, q.answered_by, f.name, q.answer_text, q.answered_at
+				   q.event_id, q.committee_id, q.answered_by, f.name, q.answer_text, q.answered_at, q.category::text,
+				   (SELECT COUNT(*) FROM question_votes qv WHERE qv.question_id = q.id) AS vote_count,
+				   q.assigned_to, fa.name, q.assigned_at
 			FROM questions q
 			LEFT JOIN volunteers v ON v.id = q.volunteer_id
 			LEFT JOIN faculty f ON f.id = q.answered_by
-			WHERE q.answer_text IS NOT NULL
-			ORDER BY q.answered_at DESC
+			LEFT JOIN faculty fa ON fa.id = q.assigned_to
+			WHERE `+strings.Join(whereConditions, " AND ")+`
+			`+order+`
 			LIMIT $1 OFFSET $2
-		`, limit, offset)
+		`, args...)
 		if err != nil {
 			return err
 		}
@@ -124,33 +209,102 @@ func ListAnsweredQuestions(pool *pgxpool.Pool) fiber.Handler {
 		questions := []models.Question{}
 		for rows.Next() {
 			var q models.Question
+			var categoryStr string
 			if err := rows.Scan(
 				&q.ID, &q.VolunteerID, &q.VolunteerName, &q.QuestionText, &q.AskedAt,
-				&q.EventID, &q.CommitteeID, &q.AnsweredBy, &q.AnsweredByName, &q.AnswerText, &q.AnsweredAt,
+				&q.EventID, &q.CommitteeID, &q.AnsweredBy, &q.AnsweredByName, &q.AnswerText, &q.AnsweredAt, &categoryStr, &q.VoteCount,
+				&q.AssignedTo, &q.AssignedToName, &q.AssignedAt,
 			); err != nil {
 				return err
 			}
+			q.Category = models.QuestionCategory(categoryStr)
 			questions = append(questions, q)
 		}
 		return c.JSON(questions)
 	}
 }
 
-// ListAllQuestions - GET /questions/all (Admin)
+// VoteQuestion - POST /questions/:id/vote (Volunteer)
+// Upvotes a question for the logged-in volunteer. Voting again is a no-op.
+func VoteQuestion(pool *pgxpool.Pool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
+		questionID, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil || questionID <= 0 {
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid question ID")
+		}
+		volunteerID, err := mw.GetUserIDFromClaims(c)
+		if err != nil {
+			return fiber.NewError(fiber.StatusUnauthorized, "Volunteer ID not found in token")
+		}
+
+		var exists bool
+		if err := pool.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM questions WHERE id = $1)`, questionID).Scan(&exists); err != nil {
+			return err
+		}
+		if !exists {
+			return fiber.NewError(fiber.StatusNotFound, "Question not found")
+		}
+
+		_, err = pool.Exec(ctx, `
+			INSERT INTO question_votes(question_id, volunteer_id) VALUES ($1, $2)
+			ON CONFLICT (question_id, volunteer_id) DO NOTHING
+		`, questionID, volunteerID)
+		if err != nil {
+			return err
+		}
+
+		var voteCount int
+		if err := pool.QueryRow(ctx, `SELECT COUNT(*) FROM question_votes WHERE question_id = $1`, questionID).Scan(&voteCount); err != nil {
+			return err
+		}
+		return c.JSON(fiber.Map{"question_id": questionID, "voted": true, "vote_count": voteCount})
+	}
+}
+
+// ListAllQuestions - GET /questions/all?event_id=&committee_id=&category=&limit=&offset= (Admin)
 func ListAllQuestions(pool *pgxpool.Pool) fiber.Handler {
 	return func(c *fiber.Ctx) error {
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
 		limit := clampInt(c.QueryInt("limit", 100), 1, 500)
 		offset := maxInt(c.QueryInt("offset", 0), 0)
 
-		rows, err := pool.Query(c.Context(), `
+		whereConditions := []string{}
+		args := []any{limit, offset}
+		if cat := c.Query("category", ""); cat != "" {
+			category, ok := normCategory(cat)
+			if !ok {
+				return fiber.NewError(fiber.StatusBadRequest, "Invalid category")
+			}
+			whereConditions = append(whereConditions, "q.category = $"+itoa(len(args)+1)+"::question_category")
+			args = append(args, category)
+		}
+		whereConditions, args, err := appendContextFilters(c, whereConditions, args)
+		if err != nil {
+			return err
+		}
+		whereClause := ""
+		if len(whereConditions) > 0 {
+			whereClause = "WHERE " + strings.Join(whereConditions, " AND ")
+		}
+
+		rows, err := pool.Query(ctx, `
 			SELECT q.id, q.volunteer_id, v.name, q.question_text, q.asked_at,
-				   q.event_id, q.committee_id, q.answered_by, f.name, q.answer_text, q.answered_at
+				   q.event_id, q.committee_id, q.answered_by, f.name, q.answer_text, q.answered_at, q.category::text,
+				   (SELECT COUNT(*) FROM question_votes qv WHERE qv.question_id = q.id) AS vote_count,
+				   q.assigned_to, fa.name, q.assigned_at
 			FROM questions q
 			LEFT JOIN volunteers v ON v.id = q.volunteer_id
 			LEFT JOIN faculty f ON f.id = q.answered_by
+			LEFT JOIN faculty fa ON fa.id = q.assigned_to
+			`+whereClause+`
 			ORDER BY q.asked_at DESC
 			LIMIT $1 OFFSET $2
-		`, limit, offset)
+		`, args...)
 		if err != nil {
 			return err
 		}
@@ -159,34 +313,71 @@ func ListAllQuestions(pool *pgxpool.Pool) fiber.Handler {
 		questions := []models.Question{}
 		for rows.Next() {
 			var q models.Question
+			var categoryStr string
 			if err := rows.Scan(
 				&q.ID, &q.VolunteerID, &q.VolunteerName, &q.QuestionText, &q.AskedAt,
-				&q.EventID, &q.CommitteeID, &q.AnsweredBy, &q.AnsweredByName, &q.AnswerText, &q.AnsweredAt,
+				&q.EventID, &q.CommitteeID, &q.AnsweredBy, &q.AnsweredByName, &q.AnswerText, &q.AnsweredAt, &categoryStr, &q.VoteCount,
+				&q.AssignedTo, &q.AssignedToName, &q.AssignedAt,
 			); err != nil {
 				return err
 			}
+			q.Category = models.QuestionCategory(categoryStr)
 			questions = append(questions, q)
 		}
 		return c.JSON(questions)
 	}
 }
 
-// ListPendingQuestions - GET /questions/pending (Admin)
+// ListPendingQuestions - GET /questions/pending?event_id=&committee_id=&category=&assigned_to=&limit=&offset= (Admin)
+// assigned_to takes a faculty ID, or "none" for questions nobody has picked up yet.
 func ListPendingQuestions(pool *pgxpool.Pool) fiber.Handler {
 	return func(c *fiber.Ctx) error {
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
 		limit := clampInt(c.QueryInt("limit", 100), 1, 500)
 		offset := maxInt(c.QueryInt("offset", 0), 0)
 
-		rows, err := pool.Query(c.Context(), `
+		whereConditions := []string{"q.answer_text IS NULL"}
+		args := []any{limit, offset}
+		if cat := c.Query("category", ""); cat != "" {
+			category, ok := normCategory(cat)
+			if !ok {
+				return fiber.NewError(fiber.StatusBadRequest, "Invalid category")
+			}
+			whereConditions = append(whereConditions, "q.category = $"+itoa(len(args)+1)+"::question_category")
+			args = append(args, category)
+		}
+		if assignee := strings.ToLower(strings.TrimSpace(c.Query("assigned_to", ""))); assignee != "" {
+			if assignee == "none" {
+				whereConditions = append(whereConditions, "q.assigned_to IS NULL")
+			} else {
+				facultyID, err := strconv.ParseInt(assignee, 10, 64)
+				if err != nil || facultyID <= 0 {
+					return fiber.NewError(fiber.StatusBadRequest, "assigned_to must be a faculty ID or 'none'")
+				}
+				whereConditions = append(whereConditions, "q.assigned_to = $"+itoa(len(args)+1))
+				args = append(args, facultyID)
+			}
+		}
+		whereConditions, args, err := appendContextFilters(c, whereConditions, args)
+		if err != nil {
+			return err
+		}
+
+		rows, err := pool.Query(ctx, `
 			SELECT q.id, q.volunteer_id, v.name, q.question_text, q.asked_at,
-				   q.event_id, q.committee_id, q.answered_by, f.name, q.answer_text, q.answered_at
+				   q.event_id, q.committee_id, q.answered_by, f.name, q.answer_text, q.answered_at, q.category::text,
+				   (SELECT COUNT(*) FROM question_votes qv WHERE qv.question_id = q.id) AS vote_count,
+				   q.assigned_to, fa.name, q.assigned_at
 			FROM questions q
 			LEFT JOIN volunteers v ON v.id = q.volunteer_id
 			LEFT JOIN faculty f ON f.id = q.answered_by
-			WHERE q.answer_text IS NULL
+			LEFT JOIN faculty fa ON fa.id = q.assigned_to
+			WHERE `+strings.Join(whereConditions, " AND ")+`
 			ORDER BY q.asked_at DESC
 			LIMIT $1 OFFSET $2
-		`, limit, offset)
+		`, args...)
 		if err != nil {
 			return err
 		}
@@ -195,30 +386,153 @@ func ListPendingQuestions(pool *pgxpool.Pool) fiber.Handler {
 		questions := []models.Question{}
 		for rows.Next() {
 			var q models.Question
+			var categoryStr string
 			if err := rows.Scan(
 				&q.ID, &q.VolunteerID, &q.VolunteerName, &q.QuestionText, &q.AskedAt,
-				&q.EventID, &q.CommitteeID, &q.AnsweredBy, &q.AnsweredByName, &q.AnswerText, &q.AnsweredAt,
+				&q.EventID, &q.CommitteeID, &q.AnsweredBy, &q.AnsweredByName, &q.AnswerText, &q.AnsweredAt, &categoryStr, &q.VoteCount,
+				&q.AssignedTo, &q.AssignedToName, &q.AssignedAt,
 			); err != nil {
 				return err
 			}
+			q.Category = models.QuestionCategory(categoryStr)
 			questions = append(questions, q)
 		}
 		return c.JSON(questions)
 	}
 }
 
-// AnswerQuestion - PUT /questions/:id/answer (Admin)
-func AnswerQuestion(pool *pgxpool.Pool) fiber.Handler {
+// ListAssignedToMe - GET /questions/assigned/me?status=pending|answered|all&limit=&offset= (Faculty)
+// Questions routed to the logged-in faculty member; pending only by default, oldest first.
+func ListAssignedToMe(pool *pgxpool.Pool) fiber.Handler {
 	return func(c *fiber.Ctx) error {
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
+		facultyID, err := mw.GetUserIDFromClaims(c)
+		if err != nil {
+			return fiber.NewError(fiber.StatusUnauthorized, "Faculty ID not found in token")
+		}
+
+		limit := clampInt(c.QueryInt("limit", 100), 1, 500)
+		offset := maxInt(c.QueryInt("offset", 0), 0)
+
+		whereConditions := []string{"q.assigned_to = $3"}
+		order := "ORDER BY q.asked_at ASC"
+		switch strings.ToLower(c.Query("status", "pending")) {
+		case "pending":
+			whereConditions = append(whereConditions, "q.answer_text IS NULL")
+		case "answered":
+			whereConditions = append(whereConditions, "q.answer_text IS NOT NULL")
+			order = "ORDER BY q.answered_at DESC"
+		case "all":
+			order = "ORDER BY q.asked_at DESC"
+		default:
+			return fiber.NewError(fiber.StatusBadRequest, "status must be 'pending', 'answered' or 'all'")
+		}
+
+		rows, err := pool.Query(ctx, `
+			SELECT q.id, q.volunteer_id, v.name, q.question_text, q.asked_at,
+				   q.event_id, q.committee_id, q.answered_by, f.name, q.answer_text, q.answered_at, q.category::text,
+				   (SELECT COUNT(*) FROM question_votes qv WHERE qv.question_id = q.id) AS vote_count,
+				   q.assigned_to, fa.name, q.assigned_at
+			FROM questions q
+			LEFT JOIN volunteers v ON v.id = q.volunteer_id
+			LEFT JOIN faculty f ON f.id = q.answered_by
+			LEFT JOIN faculty fa ON fa.id = q.assigned_to
+			WHERE `+strings.Join(whereConditions, " AND ")+`
+			`+order+`
+			LIMIT $1 OFFSET $2
+		`, limit, offset, facultyID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		questions := []models.Question{}
+		for rows.Next() {
+			var q models.Question
+			var categoryStr string
+			if err := rows.Scan(
+				&q.ID, &q.VolunteerID, &q.VolunteerName, &q.QuestionText, &q.AskedAt,
+				&q.EventID, &q.CommitteeID, &q.AnsweredBy, &q.AnsweredByName, &q.AnswerText, &q.AnsweredAt, &categoryStr, &q.VoteCount,
+				&q.AssignedTo, &q.AssignedToName, &q.AssignedAt,
+			); err != nil {
+				return err
+			}
+			q.Category = models.QuestionCategory(categoryStr)
+			questions = append(questions, q)
+		}
+		return c.JSON(questions)
+	}
+}
+
+// AssignQuestion - POST /questions/:id/assign (Admin)
+// Routes a question to a faculty member; {"faculty_id": null} returns it to the shared queue.
+func AssignQuestion(pool *pgxpool.Pool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
 		questionID, err := strconv.ParseInt(c.Params("id"), 10, 64)
 		if err != nil || questionID <= 0 {
 			return fiber.NewError(fiber.StatusBadRequest, "Invalid question ID")
 		}
 
-		adminID, err := mw.GetUserIDFromClaims(c)
+		var req models.AssignQuestionRequest
+		if err := c.BodyParser(&req); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "Bad JSON")
+		}
+		if req.FacultyID != nil {
+			if *req.FacultyID <= 0 {
+				return fiber.NewError(fiber.StatusBadRequest, "Invalid faculty ID")
+			}
+			var exists bool
+			if err := pool.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM faculty WHERE id = $1)`, *req.FacultyID).Scan(&exists); err != nil {
+				return err
+			}
+			if !exists {
+				return fiber.NewError(fiber.StatusUnprocessableEntity, "Faculty not found")
+			}
+		}
+
+		cmd, err := pool.Exec(ctx, `
+			UPDATE questions
+			SET assigned_to = $1, assigned_at = CASE WHEN $1::bigint IS NULL THEN NULL ELSE NOW() END
+			WHERE id = $2
+		`, req.FacultyID, questionID)
+		if err != nil {
+			return err
+		}
+		if cmd.RowsAffected() == 0 {
+			return fiber.NewError(fiber.StatusNotFound, "Question not found")
+		}
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+}
+
+// AnswerQuestion - PUT /questions/:id/answer[?overwrite=true] (Admin, Faculty)
+// Records the answer; answered_by/answered_at always reflect the latest edit.
+// Faculty may only answer questions assigned to them (403 otherwise); admins may answer any.
+// An already-answered question is left untouched and 409 is returned, unless overwrite=true
+// asks to correct the existing answer.
+// The asker is notified (live stream, notify provider and email) best-effort in the background;
+// delivery problems never fail the answer.
+func AnswerQuestion(pool *pgxpool.Pool, notifier *notify.Dispatcher, mailer email.Sender) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
+		questionID, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil || questionID <= 0 {
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid question ID")
+		}
+
+		answererID, err := mw.GetUserIDFromClaims(c)
 		if err != nil {
-			return fiber.NewError(fiber.StatusUnauthorized, "Admin ID not found in token")
+			return fiber.NewError(fiber.StatusUnauthorized, "Faculty ID not found in token")
 		}
+		cls, _ := c.Locals("claims").(*mw.Claims)
+		isAdmin := cls != nil && cls.Role == models.UserRoleAdmin
 
 		var req models.AnswerQuestionRequest
 		if err := c.BodyParser(&req); err != nil {
@@ -227,37 +541,95 @@ func AnswerQuestion(pool *pgxpool.Pool) fiber.Handler {
 		if strings.TrimSpace(req.AnswerText) == "" {
 			return fiber.NewError(fiber.StatusBadRequest, "Answer text is required")
 		}
+		var v validation.Validator
+		v.MaxLen("answer_text", req.AnswerText, models.MaxAnswerLen)
+		if err := v.Err(); err != nil {
+			return err
+		}
+
+		overwrite := c.QueryBool("overwrite")
 
 		now := time.Now()
-		cmd, err := pool.Exec(c.Context(), `
-			UPDATE questions
+		var askerID *int64
+		var wasAnswered bool
+		err = pool.QueryRow(ctx, `
+			UPDATE questions q
 			SET answer_text = $1, answered_by = $2, answered_at = $3
-			WHERE id = $4 AND answer_text IS NULL
-		`, req.AnswerText, adminID, now, questionID)
+			FROM (SELECT id, assigned_to, answer_text IS NOT NULL AS was_answered FROM questions WHERE id = $4 FOR UPDATE) prev
+			WHERE q.id = prev.id AND ($5 OR NOT prev.was_answered) AND ($6 OR prev.assigned_to = $2)
+			RETURNING q.volunteer_id, prev.was_answered
+		`, req.AnswerText, answererID, now, questionID, overwrite, isAdmin).Scan(&askerID, &wasAnswered)
 		if err != nil {
-			return err
+			if !errors.Is(err, sql.ErrNoRows) {
+				return err
+			}
+			var assignedTo *int64
+			if err := pool.QueryRow(ctx, `SELECT assigned_to FROM questions WHERE id = $1`, questionID).Scan(&assignedTo); err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					return fiber.NewError(fiber.StatusNotFound, "Question not found")
+				}
+				return err
+			}
+			if !isAdmin && (assignedTo == nil || *assignedTo != answererID) {
+				return fiber.NewError(fiber.StatusForbidden, "Question is not assigned to you")
+			}
+			return fiber.NewError(fiber.StatusConflict, "Question already answered; use overwrite=true to replace the answer")
 		}
-		if cmd.RowsAffected() == 0 {
-			var exists bool
-			_ = pool.QueryRow(c.Context(), `SELECT EXISTS(SELECT 1 FROM questions WHERE id = $1)`, questionID).Scan(&exists)
-			if !exists {
-				return fiber.NewError(fiber.StatusNotFound, "Question not found")
+
+		if askerID != nil {
+			title := "Your question was answered"
+			if wasAnswered {
+				title = "The answer to your question was updated"
 			}
-			return fiber.NewError(fiber.StatusConflict, "Question already answered")
+			go notifyAsker(pool, notifier, mailer, *askerID, notify.Message{
+				Kind:       notify.KindQuestionAnswered,
+				QuestionID: questionID,
+				Title:      title,
+				Body:       req.AnswerText,
+			})
 		}
 		return c.Status(fiber.StatusNoContent).JSON(fiber.Map{"message": "Question answered successfully", "answered_at": now})
 	}
 }
 
+// ReopenQuestion - POST /questions/:id/reopen (Admin)
+// Clears the answer so the question shows up as pending again.
+func ReopenQuestion(pool *pgxpool.Pool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
+		questionID, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil || questionID <= 0 {
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid question ID")
+		}
+
+		cmd, err := pool.Exec(ctx, `
+			UPDATE questions SET answer_text = NULL, answered_by = NULL, answered_at = NULL
+			WHERE id = $1
+		`, questionID)
+		if err != nil {
+			return err
+		}
+		if cmd.RowsAffected() == 0 {
+			return fiber.NewError(fiber.StatusNotFound, "Question not found")
+		}
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+}
+
 // DeleteQuestion - DELETE /questions/:id (Admin)
 func DeleteQuestion(pool *pgxpool.Pool) fiber.Handler {
 	return func(c *fiber.Ctx) error {
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
 		questionID, err := strconv.ParseInt(c.Params("id"), 10, 64)
 		if err != nil || questionID <= 0 {
 			return fiber.NewError(fiber.StatusBadRequest, "Invalid question ID")
 		}
 
-		cmd, err := pool.Exec(c.Context(), `DELETE FROM questions WHERE id = $1`, questionID)
+		cmd, err := pool.Exec(ctx, `DELETE FROM questions WHERE id = $1`, questionID)
 		if err != nil {
 			return err
 		}
@@ -268,6 +640,32 @@ func DeleteQuestion(pool *pgxpool.Pool) fiber.Handler {
 	}
 }
 
+// notifyAsker looks up the volunteer and hands m to the notifier (live stream + external provider)
+// and emails it when the volunteer has an address. Failures are only logged.
+func notifyAsker(pool *pgxpool.Pool, notifier *notify.Dispatcher, mailer email.Sender, volunteerID int64, m notify.Message) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	r := notify.Recipient{VolunteerID: volunteerID}
+	err := pool.QueryRow(ctx, `SELECT name, phone, email, sms_opt_out FROM volunteers WHERE id = $1`, volunteerID).Scan(&r.Name, &r.Phone, &r.Email, &r.SMSOptOut)
+	if err != nil {
+		log.Printf("notify: failed to load volunteer %d for %s: %v", volunteerID, m.ID(), err)
+		return
+	}
+	if notifier != nil {
+		recipients := []notify.Recipient{r}
+		notifier.Publish(recipients, m)
+		notifier.Dispatch(recipients, m)
+	}
+	if r.Email != nil {
+		email.SendAsync(mailer, email.Message{
+			To:      *r.Email,
+			Subject: m.Title,
+			Body:    "Hi " + r.Name + ",\n\n" + m.Body + "\n",
+		})
+	}
+}
+
 // Helpers
 func clampInt(v, lo, hi int) int {
 	if v < lo {
@@ -284,3 +682,39 @@ func maxInt(a, b int) int {
 	}
 	return b
 }
+func itoa(i int) string { return strconv.FormatInt(int64(i), 10) }
+
+// appendContextFilters adds the optional event_id/committee_id query filters, numbering
+// placeholders after the existing args.
+func appendContextFilters(c *fiber.Ctx, whereConditions []string, args []any) ([]string, []any, error) {
+	for _, f := range []struct{ param, column string }{
+		{"event_id", "q.event_id"},
+		{"committee_id", "q.committee_id"},
+	} {
+		v := c.Query(f.param, "")
+		if v == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, nil, fiber.NewError(fiber.StatusBadRequest, "invalid "+f.param)
+		}
+		whereConditions = append(whereConditions, f.column+"=$"+itoa(len(args)+1))
+		args = append(args, id)
+	}
+	return whereConditions, args, nil
+}
+
+// normCategory lower-cases a question category; "" defaults to general. ok is false for unknown values.
+func normCategory(cat string) (string, bool) {
+	c := strings.ToLower(strings.TrimSpace(cat))
+	switch models.QuestionCategory(c) {
+	case "":
+		return string(models.CategoryGeneral), true
+	case models.CategoryGeneral, models.CategoryLogistics, models.CategoryFood, models.CategoryMedical,
+		models.CategoryAccommodation, models.CategoryTransport, models.CategoryOther:
+		return c, true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,78 @@
+package questions
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"Seva-app-backend/db/dbtest"
+	"Seva-app-backend/models"
+)
+
+// testApp mounts the questions handlers with the auth guards replaced by claims for user sub.
+func testApp(pool *pgxpool.Pool, sub int64, role models.UserRole) *fiber.App {
+	app := dbtest.App()
+	Register(app.Group("/questions"), pool, dbtest.As(sub, role), dbtest.Pass, dbtest.Pass, dbtest.Pass, dbtest.Pass, nil, nil)
+	return app
+}
+
+func TestEmptyListsAreArrays(t *testing.T) {
+	pool := dbtest.Migrated(t)
+	event := strconv.FormatInt(dbtest.ID(t, pool, `INSERT INTO events (name) VALUES ('Empty event') RETURNING id`), 10)
+	app := testApp(pool, dbtest.ID(t, pool, `INSERT INTO volunteers (name) VALUES ('Asha') RETURNING id`), models.UserRoleVolunteer)
+	for _, p := range []string{
+		"/questions/answered?event_id=" + event,
+		"/questions/me",
+		"/questions/all?event_id=" + event,
+		"/questions/pending?event_id=" + event,
+		"/questions/assigned/me",
+	} {
+		code, body := dbtest.Do(t, app, "GET", p, "")
+		if code != fiber.StatusOK || strings.TrimSpace(string(body)) != "[]" {
+			t.Errorf("GET %s = %d %s, want 200 []", p, code, body)
+		}
+	}
+}
+
+func TestAssignedFacultyCanAnswer(t *testing.T) {
+	pool := dbtest.Migrated(t)
+	assignee := dbtest.ID(t, pool, `INSERT INTO faculty (name, email) VALUES ('Priya', 'priya@example.com') RETURNING id`)
+	other := dbtest.ID(t, pool, `INSERT INTO faculty (name, email) VALUES ('Ravi', 'ravi@example.com') RETURNING id`)
+	// Anonymous questions, so nobody needs notifying
+	assigned := dbtest.ID(t, pool, `INSERT INTO questions (question_text, assigned_to) VALUES ('Where is lunch?', $1) RETURNING id`, assignee)
+	unassigned := dbtest.ID(t, pool, `INSERT INTO questions (question_text) VALUES ('Where is parking?') RETURNING id`)
+	answer := `{"answer_text": "Hall B"}`
+	path := func(id int64) string { return "/questions/" + strconv.FormatInt(id, 10) + "/answer" }
+
+	if code, body := dbtest.Do(t, testApp(pool, other, models.UserRoleFaculty), "PUT", path(assigned), answer); code != fiber.StatusForbidden {
+		t.Errorf("other faculty answering = %d %s, want 403", code, body)
+	}
+	if code, body := dbtest.Do(t, testApp(pool, assignee, models.UserRoleFaculty), "PUT", path(unassigned), answer); code != fiber.StatusForbidden {
+		t.Errorf("faculty answering an unassigned question = %d %s, want 403", code, body)
+	}
+	if code, body := dbtest.Do(t, testApp(pool, assignee, models.UserRoleFaculty), "PUT", path(assigned), answer); code != fiber.StatusNoContent {
+		t.Fatalf("assignee answering = %d %s, want 204", code, body)
+	}
+	var answeredBy int64
+	if err := pool.QueryRow(context.Background(), `SELECT answered_by FROM questions WHERE id = $1`, assigned).Scan(&answeredBy); err != nil {
+		t.Fatal(err)
+	}
+	if answeredBy != assignee {
+		t.Errorf("answered_by = %d, want %d", answeredBy, assignee)
+	}
+	if code, body := dbtest.Do(t, testApp(pool, other, models.UserRoleAdmin), "PUT", path(unassigned), answer); code != fiber.StatusNoContent {
+		t.Errorf("admin answering = %d %s, want 204", code, body)
+	}
+
+	// A second answer needs overwrite=true
+	if code, body := dbtest.Do(t, testApp(pool, assignee, models.UserRoleFaculty), "PUT", path(assigned), answer); code != fiber.StatusConflict {
+		t.Errorf("answering again = %d %s, want 409", code, body)
+	}
+	if code, body := dbtest.Do(t, testApp(pool, assignee, models.UserRoleFaculty), "PUT", path(assigned)+"?overwrite=true", answer); code != fiber.StatusNoContent {
+		t.Errorf("answering again with overwrite=true = %d %s, want 204", code, body)
+	}
+}
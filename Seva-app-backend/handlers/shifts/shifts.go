@@ -0,0 +1,302 @@
+package shifts
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"Seva-app-backend/db"
+	mw "Seva-app-backend/middleware"
+	"Seva-app-backend/models"
+	"Seva-app-backend/validation"
+)
+
+// Register mounts shift routes under /shifts
+func Register(g fiber.Router, pool *pgxpool.Pool, jwtGuard fiber.Handler, requireFaculty fiber.Handler, requireAdmin fiber.Handler) {
+	// Faculty read access (shift pickers for check-out and reports), limited to their events when scoped
+	g.Get("/", jwtGuard, requireFaculty, mw.RequireEventScope(pool, mw.EventIDFromQuery), List(pool))
+	g.Get("/:id", jwtGuard, requireFaculty, mw.RequireEventScope(pool, eventOfShift(pool)), Get(pool))
+
+	// Admin-only write access
+	g.Post("/", jwtGuard, requireAdmin, Create(pool))
+	g.Put("/:id", jwtGuard, requireAdmin, Update(pool))
+	g.Delete("/:id", jwtGuard, requireAdmin, Del(pool))
+}
+
+// eventOfShift resolves the event of the :id shift for mw.RequireEventScope.
+func eventOfShift(pool *pgxpool.Pool) mw.EventIDResolver {
+	return func(c *fiber.Ctx) (int64, bool, error) {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil || id <= 0 {
+			return 0, false, fiber.NewError(fiber.StatusBadRequest, "invalid id")
+		}
+		var eventID int64
+		if err := pool.QueryRow(c.Context(), `SELECT event_id FROM shifts WHERE id = $1`, id).Scan(&eventID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return 0, false, fiber.NewError(fiber.StatusNotFound, "shift not found")
+			}
+			return 0, false, err
+		}
+		return eventID, true, nil
+	}
+}
+
+const shiftColumns = `id, event_id, name, start_time, end_time, created_at, updated_at`
+
+func scanShift(row pgx.Row, s *models.Shift) error {
+	return row.Scan(&s.ID, &s.EventID, &s.Name, &s.StartTime, &s.EndTime, &s.CreatedAt, &s.UpdatedAt)
+}
+
+// List - GET /shifts?event_id= (Faculty/Admin)
+// Lists shifts ordered by start time (shifts without one last), then name.
+func List(pool *pgxpool.Pool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
+		where := ""
+		args := []any{}
+		if s := c.Query("event_id", ""); s != "" {
+			eventID, err := strconv.ParseInt(s, 10, 64)
+			if err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "invalid event_id")
+			}
+			where = "WHERE event_id = $1"
+			args = append(args, eventID)
+		}
+
+		rows, err := pool.Query(ctx, `
+			SELECT `+shiftColumns+` FROM shifts
+			`+where+`
+			ORDER BY start_time NULLS LAST, name
+		`, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		out := []models.Shift{}
+		for rows.Next() {
+			var s models.Shift
+			if err := scanShift(rows, &s); err != nil {
+				return err
+			}
+			out = append(out, s)
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		return c.JSON(out)
+	}
+}
+
+// Get - GET /shifts/:id (Faculty/Admin)
+func Get(pool *pgxpool.Pool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil || id <= 0 {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid id")
+		}
+		var s models.Shift
+		if err := scanShift(pool.QueryRow(ctx, `SELECT `+shiftColumns+` FROM shifts WHERE id = $1`, id), &s); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fiber.NewError(fiber.StatusNotFound, "shift not found")
+			}
+			return err
+		}
+		return c.JSON(s)
+	}
+}
+
+// Create - POST /shifts (Admin-only)
+// Existing assignments of the event whose free-text shift matches the name (case-insensitively) and
+// that aren't linked yet are linked to the new shift.
+func Create(pool *pgxpool.Pool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
+		var b models.CreateShiftRequest
+		if err := mw.StrictBodyParser(c, &b); err != nil {
+			return err
+		}
+		if b.EventID <= 0 {
+			return fiber.NewError(fiber.StatusBadRequest, "event_id is required")
+		}
+		name, err := normName(b.Name)
+		if err != nil {
+			return err
+		}
+		if err := checkTimes(b.StartTime, b.EndTime); err != nil {
+			return err
+		}
+
+		tx, err := pool.Begin(ctx)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback(ctx)
+
+		var eventExists bool
+		if err := tx.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM events WHERE id = $1)`, b.EventID).Scan(&eventExists); err != nil {
+			return err
+		}
+		if !eventExists {
+			return fiber.NewError(fiber.StatusNotFound, "event not found")
+		}
+
+		var s models.Shift
+		err = scanShift(tx.QueryRow(ctx, `
+			INSERT INTO shifts (event_id, name, start_time, end_time) VALUES ($1, $2, $3, $4)
+			RETURNING `+shiftColumns, b.EventID, name, b.StartTime, b.EndTime), &s)
+		if err != nil {
+			if db.IsUniqueViolation(err, db.ConstraintShiftsEventNameLower) {
+				return fiber.NewError(fiber.StatusConflict, "Shift name already exists for this event")
+			}
+			return err
+		}
+		if _, err := tx.Exec(ctx, `
+			UPDATE volunteer_assignments SET shift_id = $1, shift = $2
+			WHERE event_id = $3 AND shift_id IS NULL AND lower(shift) = lower($2)
+		`, s.ID, s.Name, s.EventID); err != nil {
+			return err
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return err
+		}
+		return c.Status(fiber.StatusCreated).JSON(s)
+	}
+}
+
+// Update - PUT /shifts/:id (Admin-only)
+// Renaming a shift also renames the shift text on the assignments linked to it.
+func Update(pool *pgxpool.Pool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil || id <= 0 {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid id")
+		}
+		var b models.UpdateShiftRequest
+		if err := mw.StrictBodyParser(c, &b); err != nil {
+			return err
+		}
+		if b.Name == nil && b.StartTime == nil && b.EndTime == nil {
+			return fiber.NewError(fiber.StatusBadRequest, "no fields to update")
+		}
+
+		tx, err := pool.Begin(ctx)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback(ctx)
+
+		var s models.Shift
+		if err := scanShift(tx.QueryRow(ctx, `SELECT `+shiftColumns+` FROM shifts WHERE id = $1 FOR UPDATE`, id), &s); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fiber.NewError(fiber.StatusNotFound, "shift not found")
+			}
+			return err
+		}
+		if b.Name != nil {
+			if s.Name, err = normName(*b.Name); err != nil {
+				return err
+			}
+		}
+		if b.StartTime != nil {
+			s.StartTime = b.StartTime
+		}
+		if b.EndTime != nil {
+			s.EndTime = b.EndTime
+		}
+		if err := checkTimes(s.StartTime, s.EndTime); err != nil {
+			return err
+		}
+
+		err = scanShift(tx.QueryRow(ctx, `
+			UPDATE shifts SET name = $2, start_time = $3, end_time = $4 WHERE id = $1
+			RETURNING `+shiftColumns, id, s.Name, s.StartTime, s.EndTime), &s)
+		if err != nil {
+			if db.IsUniqueViolation(err, db.ConstraintShiftsEventNameLower) {
+				return fiber.NewError(fiber.StatusConflict, "Shift name already exists for this event")
+			}
+			return err
+		}
+		if b.Name != nil {
+			if _, err := tx.Exec(ctx, `UPDATE volunteer_assignments SET shift = $2 WHERE shift_id = $1 AND shift IS DISTINCT FROM $2`, id, s.Name); err != nil {
+				return err
+			}
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return err
+		}
+		return c.JSON(s)
+	}
+}
+
+// Del - DELETE /shifts/:id (Admin-only)
+// Linked assignments keep their shift text and are simply unlinked.
+func Del(pool *pgxpool.Pool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil || id <= 0 {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid id")
+		}
+		cmd, err := pool.Exec(ctx, `DELETE FROM shifts WHERE id = $1`, id)
+		if err != nil {
+			return err
+		}
+		if cmd.RowsAffected() == 0 {
+			return fiber.NewError(fiber.StatusNotFound, "shift not found")
+		}
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+}
+
+// querier is satisfied by both *pgxpool.Pool and pgx.Tx.
+type querier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// Resolve checks that shiftID is a shift of eventID and returns its name, for assignment writes
+// that link a shift. A shift of another event (or no shift) is a 422.
+func Resolve(ctx context.Context, q querier, eventID, shiftID int64) (string, error) {
+	var name string
+	err := q.QueryRow(ctx, `SELECT name FROM shifts WHERE id = $1 AND event_id = $2`, shiftID, eventID).Scan(&name)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", fiber.NewError(fiber.StatusUnprocessableEntity, "shift_id "+strconv.FormatInt(shiftID, 10)+" is not a shift of this event")
+	}
+	return name, err
+}
+
+// normName trims a shift name and collapses runs of whitespace, like assignment shift text.
+func normName(s string) (string, error) {
+	name := strings.Join(strings.Fields(s), " ")
+	var v validation.Validator
+	if v.Required("name", name) {
+		v.MaxLen("name", name, models.MaxNameLen)
+	}
+	return name, v.Err()
+}
+
+func checkTimes(start, end *time.Time) error {
+	if start != nil && end != nil && !end.After(*start) {
+		return fiber.NewError(fiber.StatusBadRequest, "end_time must be after start_time")
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package shifts
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"Seva-app-backend/db/dbtest"
+	"Seva-app-backend/models"
+)
+
+// testApp mounts the shifts handlers with the auth guards replaced by claims for user sub.
+func testApp(pool *pgxpool.Pool, sub int64, role models.UserRole) *fiber.App {
+	app := dbtest.App()
+	Register(app.Group("/shifts"), pool, dbtest.As(sub, role), dbtest.Pass, dbtest.Pass)
+	return app
+}
+
+func TestEmptyListsAreArrays(t *testing.T) {
+	pool := dbtest.Migrated(t)
+	event := strconv.FormatInt(dbtest.ID(t, pool, `INSERT INTO events (name) VALUES ('Empty event') RETURNING id`), 10)
+	app := testApp(pool, 0, models.UserRoleAdmin)
+	for _, p := range []string{
+		"/shifts?event_id=" + event,
+	} {
+		code, body := dbtest.Do(t, app, "GET", p, "")
+		if code != fiber.StatusOK || strings.TrimSpace(string(body)) != "[]" {
+			t.Errorf("GET %s = %d %s, want 200 []", p, code, body)
+		}
+	}
+}
+
+func TestCreateAndUpdateRejectUnknownFields(t *testing.T) {
+	app := testApp(nil, 1, models.UserRoleAdmin) // rejected before any query
+	for _, r := range []struct{ method, path, body string }{
+		{"POST", "/shifts", `{"event_id": 1, "name": "Morning", "start_time": "06:00", "end_time": "12:00", "strat_time": "07:00"}`},
+		{"PUT", "/shifts/1", `{"nmae": "Evening"}`},
+	} {
+		code, body := dbtest.Do(t, app, r.method, r.path, r.body)
+		if code != fiber.StatusBadRequest || !strings.Contains(string(body), "Unrecognized field") {
+			t.Errorf("%s %s = %d %s, want 400 naming the unknown field", r.method, r.path, code, body)
+		}
+	}
+}
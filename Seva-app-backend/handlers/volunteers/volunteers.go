@@ -1,67 +1,109 @@
 package volunteers
 
 import (
+	"context"
 	"database/sql"
 	"encoding/csv"
 	"errors"
 	"fmt"
 	"io"
 	"log"
+	"math"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
+	"Seva-app-backend/db"
+	hAnnouncements "Seva-app-backend/handlers/announcements"
 	hAuth "Seva-app-backend/handlers/auth" // For bcrypt functions
+	hDepartments "Seva-app-backend/handlers/departments"
+	hShifts "Seva-app-backend/handlers/shifts"
 	mw "Seva-app-backend/middleware"
 	"Seva-app-backend/models"
+	"Seva-app-backend/validation"
 )
 
 // Register mounts routes under /volunteers
-func Register(g fiber.Router, pool *pgxpool.Pool, jwtGuard fiber.Handler, requireAdmin fiber.Handler, requireVolunteer fiber.Handler) {
+func Register(g fiber.Router, pool *pgxpool.Pool, jwtGuard fiber.Handler, requireAdmin fiber.Handler, requireFaculty fiber.Handler, requireVolunteer fiber.Handler, idempotent fiber.Handler) {
 	// --- Admin-only Volunteer Management ---
-	g.Post("/", jwtGuard, requireAdmin, CreateSingle(pool))         // Admin creates a volunteer
-	g.Get("/", jwtGuard, requireAdmin, ListVolunteers(pool))        // Admin lists all volunteers, now with committee filter
-	g.Get("/:id", jwtGuard, requireAdmin, GetVolunteerByID(pool))   // Admin gets a volunteer by ID
-	g.Put("/:id", jwtGuard, requireAdmin, UpdateVolunteer(pool))    // Admin updates a volunteer
-	g.Delete("/:id", jwtGuard, requireAdmin, DeleteVolunteer(pool)) // Admin deletes a volunteer
+	g.Post("/", jwtGuard, requireAdmin, CreateSingle(pool))            // Admin creates a volunteer
+	g.Get("/", jwtGuard, requireAdmin, ListVolunteers(pool))           // Admin lists all volunteers, now with committee filter
+	g.Get("/:id", jwtGuard, requireAdmin, GetVolunteerByID(pool))      // Admin gets a volunteer by ID
+	g.Get("/:id/full", jwtGuard, requireAdmin, GetVolunteerFull(pool)) // Admin gets a volunteer with assignments, committees and attendance
+	g.Put("/:id", jwtGuard, requireAdmin, UpdateVolunteer(pool))       // Admin updates a volunteer
+	g.Delete("/:id", jwtGuard, requireAdmin, DeleteVolunteer(pool))    // Admin deletes a volunteer
 
 	// --- Admin-only Bulk Operations ---
-	g.Post("/bulk", jwtGuard, requireAdmin, BulkUpload(pool))                            // Admin bulk uploads volunteers
-	g.Get("/export_csv", jwtGuard, requireAdmin, ExportVolunteersCSV(pool))              // Admin exports volunteers
-	g.Get("/assignments/export_csv", jwtGuard, requireAdmin, ExportAssignmentsCSV(pool)) // Admin exports assignments
+	g.Post("/bulk", jwtGuard, requireAdmin, mw.MaxBodySize(bulkUploadMaxBytes()+mw.MultipartOverhead), BulkUpload(pool)) // Admin bulk uploads volunteers
+	g.Get("/bulk/template.csv", jwtGuard, requireAdmin, BulkUploadTemplate())                                            // Empty CSV with the bulk upload header
+	g.Post("/batch-delete", jwtGuard, requireAdmin, BatchDeleteVolunteers(pool))                                         // Admin deletes several volunteers
+	g.Post("/merge", jwtGuard, requireAdmin, MergeVolunteers(pool))                                                      // Admin merges a duplicate volunteer
+	g.Get("/export_csv", jwtGuard, requireAdmin, ExportVolunteersCSV(pool))                                              // Admin exports volunteers
+	g.Get("/assignments/export_csv", jwtGuard, requireAdmin, ExportAssignmentsCSV(pool))                                 // Admin exports assignments
+	g.Get("/unassigned", jwtGuard, requireAdmin, ListUnassignedVolunteers(pool))                                         // Volunteers with no assignment (optionally per event)
+
+	// --- Faculty (limited to their events when scoped) ---
+	g.Get("/shifts", jwtGuard, requireFaculty, mw.RequireEventScope(pool, mw.EventIDFromQuery), ListShifts(pool)) // Distinct shift names for filter dropdowns
 
 	// --- Admin-only Assignment Management ---
-	g.Post("/assignments", jwtGuard, requireAdmin, CreateAssignment(pool))       // Admin creates a new assignment
-	g.Get("/assignments", jwtGuard, requireAdmin, ListAssignments(pool))         // Admin lists all assignments, now with shift/date filters
-	g.Get("/assignments/:id", jwtGuard, requireAdmin, GetAssignmentByID(pool))   // Admin gets an assignment by ID
-	g.Put("/assignments/:id", jwtGuard, requireAdmin, UpdateAssignment(pool))    // Admin updates an assignment
-	g.Delete("/assignments/:id", jwtGuard, requireAdmin, DeleteAssignment(pool)) // Admin deletes an assignment
+	g.Post("/assignments", jwtGuard, requireAdmin, idempotent, CreateAssignment(pool))     // Admin creates a new assignment (Idempotency-Key aware)
+	g.Post("/assignments/copy", jwtGuard, requireAdmin, CopyAssignments(pool))             // Admin copies a committee's roster
+	g.Get("/assignments", jwtGuard, requireAdmin, ListAssignments(pool))                   // Admin lists all assignments, now with shift/date filters
+	g.Get("/assignments/:id", jwtGuard, requireAdmin, GetAssignmentByID(pool))             // Admin gets an assignment by ID
+	g.Put("/assignments/:id", jwtGuard, requireAdmin, UpdateAssignment(pool))              // Admin updates an assignment
+	g.Patch("/assignments/:id/reassign", jwtGuard, requireAdmin, ReassignAssignment(pool)) // Admin moves an assignment to another committee
+	g.Delete("/assignments/:id", jwtGuard, requireAdmin, DeleteAssignment(pool))           // Admin deletes an assignment
 
 	// --- Volunteer (student) Specific Routes ---
 	g.Get("/me", jwtGuard, requireVolunteer, GetMyProfile(pool))
 	g.Post("/me/set-password", jwtGuard, requireVolunteer, SetMyPassword(pool))
+	g.Patch("/me/photo", jwtGuard, requireVolunteer, UpdateMyPhoto(pool))
+	g.Patch("/me/sms-opt-out", jwtGuard, requireVolunteer, UpdateMySMSOptOut(pool))
 	g.Get("/me/assignments", jwtGuard, requireVolunteer, GetMyAssignments(pool)) // Now shows shift info
+	g.Get("/me/schedule", jwtGuard, requireVolunteer, GetMySchedule(pool))       // Upcoming shifts grouped by day
+	g.Get("/me/attendance", jwtGuard, requireVolunteer, GetMyAttendance(pool))   // Own attendance history grouped by day
 	g.Get("/me/committees", jwtGuard, requireVolunteer, GetMyCommittees(pool))
+	g.Get("/me/dashboard", jwtGuard, requireVolunteer, GetMyDashboard(pool))
 }
 
 // --- Admin-Only Volunteer CRUD ---
 
 // CreateSingle - POST /volunteers (Admin)
-// Allows admin to create a new volunteer record.
+// Allows admin to create a new volunteer record. Invalid fields are a 422 validation_failed naming them.
 func CreateSingle(pool *pgxpool.Pool) fiber.Handler {
 	return func(c *fiber.Ctx) error {
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
 		var b models.CreateVolunteerRequest
-		if err := c.BodyParser(&b); err != nil {
-			return fiber.NewError(fiber.StatusBadRequest, "Bad JSON")
+		if err := mw.StrictBodyParser(c, &b); err != nil {
+			return err
+		}
+		var v validation.Validator
+		if v.Required("name", b.Name) {
+			v.MaxLen("name", strings.TrimSpace(b.Name), models.MaxNameLen)
+		}
+		if b.Email != nil && v.Required("email", *b.Email) {
+			v.Email("email", strings.TrimSpace(*b.Email))
+		}
+		var photoURL *string
+		if b.PhotoURL != nil {
+			var err error
+			photoURL, err = normalizePhotoURL(*b.PhotoURL)
+			v.Check(err == nil, "photo_url", validation.FieldInvalidFormat)
 		}
-		if strings.TrimSpace(b.Name) == "" {
-			return fiber.NewError(fiber.StatusBadRequest, "Name is required")
+		if err := v.Err(); err != nil {
+			return err
 		}
-		if b.Email != nil && strings.TrimSpace(*b.Email) == "" {
-			return fiber.NewError(fiber.StatusBadRequest, "Email cannot be empty if provided")
+
+		dept, err := hDepartments.Resolve(ctx, pool, b.Dept)
+		if err != nil {
+			return err
 		}
 
 		var passwordHash *string
@@ -73,14 +115,27 @@ func CreateSingle(pool *pgxpool.Pool) fiber.Handler {
 			passwordHash = &hash
 		}
 
-		// Check if email already exists in faculty or volunteers table
+		tx, err := pool.Begin(ctx)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback(ctx)
+
+		// Check if email already exists in faculty or volunteers table.
+		// The advisory lock is held until commit so a concurrent request for the same
+		// email (here or in faculty registration) cannot slip in between check and insert.
 		if b.Email != nil {
+			if err := hAuth.LockEmail(ctx, tx, *b.Email); err != nil {
+				return err
+			}
+
 			var exists int
-			err := pool.QueryRow(c.Context(), `
+			err := tx.QueryRow(ctx, `
 				SELECT 1 FROM faculty WHERE lower(email) = $1
 				UNION ALL
 				SELECT 1 FROM volunteers WHERE lower(email) = $1
-			`, strings.ToLower(*b.Email)).Scan(&exists)
+				LIMIT 1
+			`, strings.ToLower(strings.TrimSpace(*b.Email))).Scan(&exists)
 
 			if err == nil {
 				return fiber.NewError(fiber.StatusConflict, "Email already registered")
@@ -90,20 +145,26 @@ func CreateSingle(pool *pgxpool.Pool) fiber.Handler {
 		}
 
 		var vID int64
-		err := pool.QueryRow(c.Context(), `
-			INSERT INTO volunteers(name, email, phone, dept, college_id, password_hash, role)
-			VALUES ($1,$2,$3,$4,$5,$6, $7)
+		err = tx.QueryRow(ctx, `
+			INSERT INTO volunteers(name, email, phone, dept, college_id, password_hash, role, photo_url)
+			VALUES ($1,$2,$3,$4,$5,$6, $7, $8)
 			RETURNING id
-		`, b.Name, b.Email, b.Phone, b.Dept, b.CollegeID, passwordHash, models.UserRoleVolunteer).Scan(&vID)
+		`, b.Name, b.Email, b.Phone, dept, b.CollegeID, passwordHash, models.UserRoleVolunteer, photoURL).Scan(&vID)
 		if err != nil {
-			if strings.Contains(err.Error(), "volunteers_college_id_key") {
+			if db.IsUniqueViolation(err, db.ConstraintVolunteersEmail) {
+				return fiber.NewError(fiber.StatusConflict, "Email already registered")
+			}
+			if db.IsUniqueViolation(err, db.ConstraintVolunteersCollegeID) {
 				return fiber.NewError(fiber.StatusConflict, "Volunteer with this college ID already exists")
 			}
 			return err
 		}
+		if err := tx.Commit(ctx); err != nil {
+			return err
+		}
 
 		return c.Status(fiber.StatusCreated).JSON(fiber.Map{
-			"id": vID, "name": b.Name, "email": b.Email, "phone": b.Phone, "dept": b.Dept, "college_id": b.CollegeID,
+			"id": vID, "name": b.Name, "email": b.Email, "phone": b.Phone, "dept": dept, "college_id": b.CollegeID, "photo_url": photoURL,
 		})
 	}
 }
@@ -112,6 +173,9 @@ func CreateSingle(pool *pgxpool.Pool) fiber.Handler {
 // Lists all volunteer records, with optional committee filter.
 func ListVolunteers(pool *pgxpool.Pool) fiber.Handler {
 	return func(c *fiber.Ctx) error {
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
 		limit := clampInt(c.QueryInt("limit", 100), 1, 500)
 		offset := maxInt(c.QueryInt("offset", 0), 0)
 
@@ -136,14 +200,72 @@ func ListVolunteers(pool *pgxpool.Pool) fiber.Handler {
 		}
 
 		query := `
-			SELECT v.id, v.name, v.email, v.phone, v.dept, v.college_id, v.created_at
+			SELECT v.id, v.name, v.email, v.phone, v.dept, v.college_id, v.photo_url, v.created_at, v.updated_at, v.last_login_at
 			FROM volunteers v
 			` + whereClause + `
 			ORDER BY v.name
 			LIMIT $1 OFFSET $2
 		`
 
-		rows, err := pool.Query(c.Context(), query, args...)
+		rows, err := pool.Query(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		out := make([]models.Volunteer, 0, limit)
+		for rows.Next() {
+			var v models.Volunteer
+			if err := rows.Scan(&v.ID, &v.Name, &v.Email, &v.Phone, &v.Dept, &v.CollegeID, &v.PhotoURL, &v.CreatedAt, &v.UpdatedAt, &v.LastLoginAt); err != nil {
+				return err
+			}
+			out = append(out, v)
+		}
+		return c.JSON(out)
+	}
+}
+
+// ListUnassignedVolunteers - GET /volunteers/unassigned?event_id=&q=&limit=100&offset=0 (Admin)
+// Lists volunteers with no live (non-cancelled) assignment, optionally only counting assignments
+// in event_id. q matches name, email or college_id.
+func ListUnassignedVolunteers(pool *pgxpool.Pool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
+		limit := clampInt(c.QueryInt("limit", 100), 1, 500)
+		offset := maxInt(c.QueryInt("offset", 0), 0)
+
+		args := []any{limit, offset}
+		assignmentFilter := ""
+		if s := c.Query("event_id", ""); s != "" {
+			eventID, err := strconv.ParseInt(s, 10, 64)
+			if err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "invalid event_id")
+			}
+			args = append(args, eventID)
+			assignmentFilter = " AND va.event_id = $" + itoa(len(args))
+		}
+
+		whereClauses := []string{`NOT EXISTS (
+				SELECT 1 FROM volunteer_assignments va
+				WHERE va.volunteer_id = v.id AND va.status <> 'cancelled'` + assignmentFilter + `
+			)`}
+		if q := strings.TrimSpace(c.Query("q", "")); q != "" {
+			args = append(args, "%"+q+"%")
+			n := itoa(len(args))
+			whereClauses = append(whereClauses, "(v.name ILIKE $"+n+" OR v.email ILIKE $"+n+" OR v.college_id ILIKE $"+n+")")
+		}
+
+		query := `
+			SELECT v.id, v.name, v.email, v.phone, v.dept, v.college_id, v.photo_url, v.created_at, v.updated_at, v.last_login_at
+			FROM volunteers v
+			WHERE ` + strings.Join(whereClauses, " AND ") + `
+			ORDER BY v.name
+			LIMIT $1 OFFSET $2
+		`
+
+		rows, err := pool.Query(ctx, query, args...)
 		if err != nil {
 			return err
 		}
@@ -152,11 +274,14 @@ func ListVolunteers(pool *pgxpool.Pool) fiber.Handler {
 		out := make([]models.Volunteer, 0, limit)
 		for rows.Next() {
 			var v models.Volunteer
-			if err := rows.Scan(&v.ID, &v.Name, &v.Email, &v.Phone, &v.Dept, &v.CollegeID, &v.CreatedAt); err != nil {
+			if err := rows.Scan(&v.ID, &v.Name, &v.Email, &v.Phone, &v.Dept, &v.CollegeID, &v.PhotoURL, &v.CreatedAt, &v.UpdatedAt, &v.LastLoginAt); err != nil {
 				return err
 			}
 			out = append(out, v)
 		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
 		return c.JSON(out)
 	}
 }
@@ -164,50 +289,147 @@ func ListVolunteers(pool *pgxpool.Pool) fiber.Handler {
 // GetVolunteerByID - GET /volunteers/:id (Admin)
 func GetVolunteerByID(pool *pgxpool.Pool) fiber.Handler {
 	return func(c *fiber.Ctx) error {
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
 		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
 		if err != nil || id <= 0 {
 			return fiber.NewError(fiber.StatusBadRequest, "Invalid volunteer ID")
 		}
 
-		var v models.Volunteer
-		err = pool.QueryRow(c.Context(), `
-			SELECT id, name, email, phone, dept, college_id, created_at
-			FROM volunteers WHERE id = $1
-		`, id).Scan(&v.ID, &v.Name, &v.Email, &v.Phone, &v.Dept, &v.CollegeID, &v.CreatedAt)
+		v, err := volunteerByID(ctx, pool, id)
 		if err != nil {
-			if errors.Is(err, sql.ErrNoRows) {
-				return fiber.NewError(fiber.StatusNotFound, "Volunteer not found")
-			}
 			return err
 		}
 		return c.JSON(v)
 	}
 }
 
+// volunteerByID loads a volunteer as admins see it (including last_login_at); 404 if it doesn't exist.
+func volunteerByID(ctx context.Context, pool *pgxpool.Pool, id int64) (models.Volunteer, error) {
+	var v models.Volunteer
+	err := pool.QueryRow(ctx, `
+		SELECT id, name, email, phone, dept, college_id, photo_url, created_at, updated_at, last_login_at
+		FROM volunteers WHERE id = $1
+	`, id).Scan(&v.ID, &v.Name, &v.Email, &v.Phone, &v.Dept, &v.CollegeID, &v.PhotoURL, &v.CreatedAt, &v.UpdatedAt, &v.LastLoginAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return v, fiber.NewError(fiber.StatusNotFound, "Volunteer not found")
+		}
+		return v, err
+	}
+	return v, nil
+}
+
+// fullProfileRecentAttendance is how many attendance records GetVolunteerFull includes.
+const fullProfileRecentAttendance = 20
+
+// GetVolunteerFull - GET /volunteers/:id/full (Admin)
+// The volunteer with all their assignments, committees and most recent attendance in one call, for the
+// admin detail view. GET /volunteers/:id stays lean for list-row clicks.
+func GetVolunteerFull(pool *pgxpool.Pool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil || id <= 0 {
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid volunteer ID")
+		}
+
+		profile, err := volunteerByID(ctx, pool, id)
+		if err != nil {
+			return err
+		}
+		assignments, err := myAssignments(ctx, pool, id, false, 500, 0)
+		if err != nil {
+			return err
+		}
+		committees, err := myCommittees(ctx, pool, id, 500, 0)
+		if err != nil {
+			return err
+		}
+
+		rows, err := pool.Query(ctx, `
+			SELECT `+attendanceHistoryColumns+attendanceHistoryFrom+`
+			WHERE va.volunteer_id = $1
+			ORDER BY a.check_in_time DESC
+			LIMIT $2
+		`, id, fullProfileRecentAttendance)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		attendance := []models.AttendanceHistoryRow{}
+		for rows.Next() {
+			var r models.AttendanceHistoryRow
+			if err := scanAttendanceHistoryRow(rows, &r); err != nil {
+				return err
+			}
+			attendance = append(attendance, r)
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		return c.JSON(fiber.Map{
+			"profile":           profile,
+			"assignments":       assignments,
+			"committees":        committees,
+			"recent_attendance": attendance,
+		})
+	}
+}
+
 // UpdateVolunteer - PUT /volunteers/:id (Admin)
+// With expected_updated_at in the body the update only applies if nobody changed the volunteer since;
+// otherwise 409 "modified by another user".
 func UpdateVolunteer(pool *pgxpool.Pool) fiber.Handler {
 	return func(c *fiber.Ctx) error {
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
 		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
 		if err != nil || id <= 0 {
 			return fiber.NewError(fiber.StatusBadRequest, "Invalid volunteer ID")
 		}
 
 		var b models.UpdateVolunteerRequest
-		if err := c.BodyParser(&b); err != nil {
-			return fiber.NewError(fiber.StatusBadRequest, "Bad JSON")
+		if err := mw.StrictBodyParser(c, &b); err != nil {
+			return err
+		}
+		var v validation.Validator
+		if b.Name != nil && v.Required("name", *b.Name) {
+			v.MaxLen("name", strings.TrimSpace(*b.Name), models.MaxNameLen)
+		}
+		if b.Email != nil && strings.TrimSpace(*b.Email) != "" { // "" clears the email
+			v.Email("email", strings.TrimSpace(*b.Email))
+		}
+		var photoURL *string
+		if b.PhotoURL != nil {
+			photoURL, err = normalizePhotoURL(*b.PhotoURL)
+			v.Check(err == nil, "photo_url", validation.FieldInvalidFormat)
+		}
+		if b.Role != nil {
+			v.Check(strings.ToLower(string(*b.Role)) == string(models.UserRoleVolunteer), "role", validation.FieldInvalidValue) // Volunteers can only be 'volunteer'
+		}
+		if err := v.Err(); err != nil {
+			return err
+		}
+
+		tx, err := pool.Begin(ctx)
+		if err != nil {
+			return err
 		}
+		defer tx.Rollback(ctx)
 
 		sets := []string{}
 		args := []any{}
 		i := 1
 
 		if b.Name != nil {
-			name := strings.TrimSpace(*b.Name)
-			if name == "" {
-				return fiber.NewError(fiber.StatusBadRequest, "Name cannot be empty")
-			}
 			sets = append(sets, "name=$"+itoa(i))
-			args = append(args, name)
+			args = append(args, strings.TrimSpace(*b.Name))
 			i++
 		}
 		if b.Email != nil {
@@ -216,15 +438,19 @@ func UpdateVolunteer(pool *pgxpool.Pool) fiber.Handler {
 				sets = append(sets, "email=$"+itoa(i))
 				args = append(args, nil)
 			} else {
+				// Held until commit, so no other volunteer or faculty account can take the email in between
+				if err := hAuth.LockEmail(ctx, tx, email); err != nil {
+					return err
+				}
 				var existingUserID int64
-				err = pool.QueryRow(c.Context(), `SELECT id FROM volunteers WHERE lower(email) = $1 AND id != $2`, email, id).Scan(&existingUserID)
+				err = tx.QueryRow(ctx, `SELECT id FROM volunteers WHERE lower(email) = $1 AND id != $2`, email, id).Scan(&existingUserID)
 				if err == nil {
 					return fiber.NewError(fiber.StatusConflict, "Email already in use by another volunteer")
 				}
 				if !errors.Is(err, sql.ErrNoRows) {
 					return err
 				}
-				err = pool.QueryRow(c.Context(), `SELECT id FROM faculty WHERE lower(email) = $1`, email).Scan(&existingUserID)
+				err = tx.QueryRow(ctx, `SELECT id FROM faculty WHERE lower(email) = $1`, email).Scan(&existingUserID)
 				if err == nil {
 					return fiber.NewError(fiber.StatusConflict, "Email already in use by a faculty member")
 				}
@@ -243,8 +469,12 @@ func UpdateVolunteer(pool *pgxpool.Pool) fiber.Handler {
 			i++
 		}
 		if b.Dept != nil {
+			dept, err := hDepartments.Resolve(ctx, tx, b.Dept)
+			if err != nil {
+				return err
+			}
 			sets = append(sets, "dept=$"+itoa(i))
-			args = append(args, nullable(strings.TrimSpace(*b.Dept)))
+			args = append(args, dept)
 			i++
 		}
 		if b.CollegeID != nil {
@@ -254,7 +484,7 @@ func UpdateVolunteer(pool *pgxpool.Pool) fiber.Handler {
 				args = append(args, nil)
 			} else {
 				var existingUserID int64
-				err = pool.QueryRow(c.Context(), `SELECT id FROM volunteers WHERE college_id = $1 AND id != $2`, collegeID, id).Scan(&existingUserID)
+				err = tx.QueryRow(ctx, `SELECT id FROM volunteers WHERE college_id = $1 AND id != $2`, collegeID, id).Scan(&existingUserID)
 				if err == nil {
 					return fiber.NewError(fiber.StatusConflict, "College ID already in use by another volunteer")
 				}
@@ -267,6 +497,11 @@ func UpdateVolunteer(pool *pgxpool.Pool) fiber.Handler {
 			}
 			i++
 		}
+		if b.PhotoURL != nil {
+			sets = append(sets, "photo_url=$"+itoa(i))
+			args = append(args, photoURL)
+			i++
+		}
 		if b.Password != nil {
 			hash, err := hAuth.BcryptHash(*b.Password)
 			if err != nil {
@@ -277,12 +512,8 @@ func UpdateVolunteer(pool *pgxpool.Pool) fiber.Handler {
 			i++
 		}
 		if b.Role != nil {
-			roleStr := strings.ToLower(string(*b.Role))
-			if roleStr != string(models.UserRoleVolunteer) { // Only allow setting to 'volunteer' for volunteers
-				return fiber.NewError(fiber.StatusBadRequest, "Invalid role for volunteer update. Can only be 'volunteer'.")
-			}
 			sets = append(sets, "role=$"+itoa(i)+`::user_role`)
-			args = append(args, roleStr)
+			args = append(args, strings.ToLower(string(*b.Role)))
 			i++
 		}
 
@@ -290,33 +521,66 @@ func UpdateVolunteer(pool *pgxpool.Pool) fiber.Handler {
 			return fiber.NewError(fiber.StatusBadRequest, "No fields to update")
 		}
 		args = append(args, id)
+		where := ` WHERE id=$` + itoa(i)
+		if b.ExpectedUpdatedAt != nil {
+			i++
+			where += ` AND updated_at=$` + itoa(i)
+			args = append(args, *b.ExpectedUpdatedAt)
+		}
 
-		sqlQuery := `UPDATE volunteers SET ` + strings.Join(sets, ", ") + ` WHERE id=$` + itoa(i)
-		cmd, err := pool.Exec(c.Context(), sqlQuery, args...)
+		sqlQuery := `UPDATE volunteers SET ` + strings.Join(sets, ", ") + where
+		cmd, err := tx.Exec(ctx, sqlQuery, args...)
 		if err != nil {
-			if strings.Contains(err.Error(), "volunteers_email_key") {
+			if db.IsUniqueViolation(err, db.ConstraintVolunteersEmail) {
 				return fiber.NewError(fiber.StatusConflict, "Email already in use by another volunteer or faculty.")
 			}
-			if strings.Contains(err.Error(), "volunteers_college_id_key") {
+			if db.IsUniqueViolation(err, db.ConstraintVolunteersCollegeID) {
 				return fiber.NewError(fiber.StatusConflict, "College ID already in use by another volunteer.")
 			}
 			return err
 		}
 		if cmd.RowsAffected() == 0 {
+			if b.ExpectedUpdatedAt != nil {
+				return staleUpdate(ctx, c, pool, "volunteers", id, "Volunteer not found")
+			}
 			return fiber.NewError(fiber.StatusNotFound, "Volunteer not found")
 		}
+		if err := tx.Commit(ctx); err != nil {
+			return err
+		}
 		return c.SendStatus(fiber.StatusNoContent)
 	}
 }
 
+// staleUpdate explains why a conditional UPDATE on table matched no rows: 404 when the row is gone,
+// otherwise 409 with the current updated_at so the client can reload and retry. ctx is the handler's
+// db.WithTimeout context.
+func staleUpdate(ctx context.Context, c *fiber.Ctx, pool *pgxpool.Pool, table string, id int64, notFound string) error {
+	var current time.Time
+	err := pool.QueryRow(ctx, `SELECT updated_at FROM `+table+` WHERE id = $1`, id).Scan(&current)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fiber.NewError(fiber.StatusNotFound, notFound)
+		}
+		return err
+	}
+	return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+		"error":      "modified by another user",
+		"updated_at": current,
+	})
+}
+
 // DeleteVolunteer - DELETE /volunteers/:id (Admin)
 func DeleteVolunteer(pool *pgxpool.Pool) fiber.Handler {
 	return func(c *fiber.Ctx) error {
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
 		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
 		if err != nil || id <= 0 {
 			return fiber.NewError(fiber.StatusBadRequest, "Invalid volunteer ID")
 		}
-		cmd, err := pool.Exec(c.Context(), `DELETE FROM volunteers WHERE id=$1`, id)
+		cmd, err := pool.Exec(ctx, `DELETE FROM volunteers WHERE id=$1`, id)
 		if err != nil {
 			return err
 		}
@@ -326,6 +590,239 @@ func DeleteVolunteer(pool *pgxpool.Pool) fiber.Handler {
 		return c.SendStatus(fiber.StatusNoContent)
 	}
 }
+
+// BatchDeleteVolunteers - POST /volunteers/batch-delete  {ids: [...], soft?: bool} (Admin)
+// Deletes each volunteer independently and reports a per-ID result instead of aborting on the first failure:
+// "deleted", "not_found", "blocked" (has attendance history), "soft_deleted" (soft=true: record and history
+// kept, assignments cancelled) or "error". At most 500 IDs per request.
+func BatchDeleteVolunteers(pool *pgxpool.Pool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
+		var b models.BatchDeleteVolunteersRequest
+		if err := c.BodyParser(&b); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "Bad JSON")
+		}
+		if len(b.IDs) == 0 {
+			return fiber.NewError(fiber.StatusBadRequest, "ids is required")
+		}
+		if len(b.IDs) > 500 {
+			return fiber.NewError(fiber.StatusBadRequest, "At most 500 ids per request")
+		}
+
+		type result struct {
+			ID     int64  `json:"id"`
+			Result string `json:"result"`
+			Error  string `json:"error,omitempty"`
+		}
+		results := make([]result, 0, len(b.IDs))
+		counts := map[string]int{}
+		seen := map[int64]bool{}
+
+		tx, err := pool.Begin(ctx)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback(ctx)
+
+		for _, id := range b.IDs {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+
+			res := result{ID: id}
+			// Each ID runs in its own savepoint so one failure doesn't poison the batch
+			sp, err := tx.Begin(ctx)
+			if err != nil {
+				return err
+			}
+			res.Result, err = deleteVolunteerInBatch(ctx, sp, id, b.Soft)
+			if err != nil {
+				_ = sp.Rollback(ctx)
+				log.Printf("batch-delete volunteer %d: %v", id, err)
+				res.Result, res.Error = "error", "could not delete volunteer"
+			} else if err := sp.Commit(ctx); err != nil {
+				return err
+			}
+			counts[res.Result]++
+			results = append(results, res)
+		}
+
+		if err := tx.Commit(ctx); err != nil {
+			return err
+		}
+		return c.JSON(fiber.Map{"results": results, "summary": counts})
+	}
+}
+
+// deleteVolunteerInBatch removes one volunteer for BatchDeleteVolunteers and returns its result label.
+func deleteVolunteerInBatch(ctx context.Context, tx pgx.Tx, id int64, soft bool) (string, error) {
+	var exists bool
+	var attendance int64
+	err := tx.QueryRow(ctx, `
+		SELECT EXISTS(SELECT 1 FROM volunteers WHERE id = $1),
+		       (SELECT COUNT(*) FROM attendance a JOIN volunteer_assignments va ON va.id = a.assignment_id WHERE va.volunteer_id = $1)
+	`, id).Scan(&exists, &attendance)
+	if err != nil {
+		return "", err
+	}
+	if !exists {
+		return "not_found", nil
+	}
+	if attendance > 0 {
+		if !soft {
+			return "blocked", nil
+		}
+		if _, err := tx.Exec(ctx, `UPDATE volunteer_assignments SET status = 'cancelled' WHERE volunteer_id = $1`, id); err != nil {
+			return "", err
+		}
+		return "soft_deleted", nil
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM volunteers WHERE id = $1`, id); err != nil {
+		return "", err
+	}
+	return "deleted", nil
+}
+
+// MergeVolunteers - POST /volunteers/merge  {keep_id, merge_id} (Admin)
+// Moves everything from the duplicate (merge_id) onto keep_id and deletes the duplicate, in one transaction.
+// Where both volunteers hold an assignment for the same event+committee, the kept assignment wins and the
+// duplicate's attendance is moved onto it; if both are still checked in there on the same day the merge
+// is refused with 409 and the conflicting attendance IDs. Questions, votes, announcement targets and acks
+// follow as well.
+func MergeVolunteers(pool *pgxpool.Pool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
+		var b models.MergeVolunteersRequest
+		if err := c.BodyParser(&b); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "Bad JSON")
+		}
+		if b.KeepID <= 0 || b.MergeID <= 0 {
+			return fiber.NewError(fiber.StatusBadRequest, "keep_id and merge_id are required")
+		}
+		if b.KeepID == b.MergeID {
+			return fiber.NewError(fiber.StatusBadRequest, "keep_id and merge_id must differ")
+		}
+
+		tx, err := pool.Begin(ctx)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback(ctx)
+
+		var found int
+		if err := tx.QueryRow(ctx, `
+			SELECT COUNT(*) FROM (SELECT id FROM volunteers WHERE id IN ($1, $2) FOR UPDATE) v
+		`, b.KeepID, b.MergeID).Scan(&found); err != nil {
+			return err
+		}
+		if found != 2 {
+			return fiber.NewError(fiber.StatusNotFound, "Volunteer not found")
+		}
+
+		var attendance int64
+		if err := tx.QueryRow(ctx, `
+			SELECT COUNT(*) FROM attendance a JOIN volunteer_assignments va ON va.id = a.assignment_id WHERE va.volunteer_id = $1
+		`, b.MergeID).Scan(&attendance); err != nil {
+			return err
+		}
+
+		// Moving the duplicate's attendance must not leave the kept assignment with two open check-ins
+		// on the same day (ux_attendance_active_assignment_day); those have to be checked out first.
+		rows, err := tx.Query(ctx, `
+			SELECT a.id, ka.id
+			FROM attendance a
+			JOIN volunteer_assignments m ON m.id = a.assignment_id AND m.volunteer_id = $2
+			JOIN volunteer_assignments k ON k.event_id = m.event_id AND k.committee_id = m.committee_id AND k.volunteer_id = $1
+			JOIN attendance ka ON ka.assignment_id = k.id AND ka.check_in_day = a.check_in_day AND ka.check_out_time IS NULL
+			WHERE a.check_out_time IS NULL
+			ORDER BY a.id
+		`, b.KeepID, b.MergeID)
+		if err != nil {
+			return err
+		}
+		var conflicts []fiber.Map
+		for rows.Next() {
+			var mergeAttendance, keptAttendance int64
+			if err := rows.Scan(&mergeAttendance, &keptAttendance); err != nil {
+				rows.Close()
+				return err
+			}
+			conflicts = append(conflicts, fiber.Map{"attendance_id": mergeAttendance, "kept_attendance_id": keptAttendance})
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		if len(conflicts) > 0 {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error":     "both volunteers are checked in to the same committee on the same day; check one of them out first",
+				"conflicts": conflicts,
+			})
+		}
+
+		// Overlapping assignments: move attendance onto the kept assignment, then drop the duplicate's
+		if _, err := tx.Exec(ctx, `
+			UPDATE attendance a SET assignment_id = k.id
+			FROM volunteer_assignments m
+			JOIN volunteer_assignments k ON k.event_id = m.event_id AND k.committee_id = m.committee_id AND k.volunteer_id = $1
+			WHERE m.volunteer_id = $2 AND a.assignment_id = m.id
+		`, b.KeepID, b.MergeID); err != nil {
+			return err
+		}
+		combined, err := tx.Exec(ctx, `
+			DELETE FROM volunteer_assignments m
+			USING volunteer_assignments k
+			WHERE m.volunteer_id = $2 AND k.volunteer_id = $1 AND k.event_id = m.event_id AND k.committee_id = m.committee_id
+		`, b.KeepID, b.MergeID)
+		if err != nil {
+			return err
+		}
+		// The rest can simply change hands (their attendance comes along)
+		moved, err := tx.Exec(ctx, `UPDATE volunteer_assignments SET volunteer_id = $1 WHERE volunteer_id = $2`, b.KeepID, b.MergeID)
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(ctx, `UPDATE questions SET volunteer_id = $1 WHERE volunteer_id = $2`, b.KeepID, b.MergeID); err != nil {
+			return err
+		}
+		// Link tables keyed by (x, volunteer_id): re-point rows the kept volunteer doesn't already have;
+		// leftovers cascade away with the duplicate.
+		for _, link := range []struct{ table, key string }{
+			{"question_votes", "question_id"},
+			{"announcement_targets", "announcement_id"},
+			{"announcement_acks", "announcement_id"},
+		} {
+			if _, err := tx.Exec(ctx, `
+				UPDATE `+link.table+` t SET volunteer_id = $1
+				WHERE t.volunteer_id = $2
+				  AND NOT EXISTS (SELECT 1 FROM `+link.table+` k WHERE k.volunteer_id = $1 AND k.`+link.key+` = t.`+link.key+`)
+			`, b.KeepID, b.MergeID); err != nil {
+				return err
+			}
+		}
+
+		if _, err := tx.Exec(ctx, `DELETE FROM volunteers WHERE id = $1`, b.MergeID); err != nil {
+			return err
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return err
+		}
+		return c.JSON(fiber.Map{
+			"kept_id":                b.KeepID,
+			"merged_id":              b.MergeID,
+			"assignments_reassigned": moved.RowsAffected(),
+			"assignments_combined":   combined.RowsAffected(),
+			"attendance_reassigned":  attendance,
+		})
+	}
+}
+
 func createIndexer(headers []string) map[string]int {
 	idx := make(map[string]int)
 	for i, header := range headers {
@@ -339,8 +836,33 @@ func createIndexer(headers []string) map[string]int {
 
 // --- Admin-Only Bulk Operations ---
 
-// BulkUpload - POST /volunteers/bulk?event_id=1&committee_id=3 (Admin)
-// CSV header: name,email,phone,dept,college_id,reporting_time_iso,shift,start_time_iso,end_time_iso,role,status,notes
+// bulkUploadColumns is the CSV header BulkUpload reads (matched case-insensitively); only name is required.
+// "Roll No" is the college ID, and "Group No"/"Faculty" are folded into the assignment notes.
+var bulkUploadColumns = []string{
+	"name", "email", "phone", "dept", "Roll No", "shift",
+	"reporting_time_iso", "start_time_iso", "end_time_iso", "role", "status", "Group No", "Faculty",
+}
+
+// BulkUploadTemplate - GET /volunteers/bulk/template.csv (Admin)
+// Returns an empty CSV with the header row BulkUpload expects.
+func BulkUploadTemplate() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Set("Content-Type", "text/csv")
+		c.Set("Content-Disposition", `attachment; filename="volunteers_template.csv"`)
+		w := csv.NewWriter(c.Response().BodyWriter())
+		if err := w.Write(bulkUploadColumns); err != nil {
+			return err
+		}
+		w.Flush()
+		return w.Error()
+	}
+}
+
+// BulkUpload - POST /volunteers/bulk?event_id=1&committee_id=3[&override=true&reinstate=true] (Admin)
+// CSV header: see bulkUploadColumns (GET /volunteers/bulk/template.csv).
+// Empty role/status cells default to volunteer/assigned; unknown values are reported as row errors.
+// Rows that would put a shift over its capacity are skipped with a row error, unless override=true.
+// Rows matching a cancelled assignment update it but leave it cancelled, unless reinstate=true.
 func BulkUpload(pool *pgxpool.Pool) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		eventID, err := strconv.ParseInt(c.Query("event_id", ""), 10, 64)
@@ -351,11 +873,19 @@ func BulkUpload(pool *pgxpool.Pool) fiber.Handler {
 		if err != nil || committeeID <= 0 {
 			return fiber.NewError(fiber.StatusBadRequest, "committee_id is required")
 		}
+		override := c.QueryBool("override")
+		reinstate := c.QueryBool("reinstate")
 
 		formFile, err := c.FormFile("file")
 		if err != nil {
 			return fiber.NewError(fiber.StatusBadRequest, "file is required")
 		}
+		if max := bulkUploadMaxBytes(); formFile.Size > max {
+			return fiber.NewError(fiber.StatusRequestEntityTooLarge, fmt.Sprintf("file exceeds the %d byte limit", max))
+		}
+		if !isCSVUpload(formFile.Header.Get(fiber.HeaderContentType), formFile.Filename) {
+			return fiber.NewError(fiber.StatusUnsupportedMediaType, "file must be a CSV (text/csv)")
+		}
 		f, err := formFile.Open()
 		if err != nil {
 			return err
@@ -409,11 +939,15 @@ func BulkUpload(pool *pgxpool.Pool) fiber.Handler {
 
 			email := nullable(trim(get(rec, idx, "email")))
 			phone := nullable(trim(get(rec, idx, "phone")))
-			dept := nullable(trim(get(rec, idx, "dept")))
+			dept, err := hDepartments.Resolve(c.Context(), tx, nullable(trim(get(rec, idx, "dept"))))
+			if err != nil {
+				rowErrors = append(rowErrors, rowErr{line, fmt.Sprintf("dept: %v", err)})
+				continue
+			}
 			collegeID := nullable(trim(get(rec, idx, "Roll No")))
 
 			// Extract shift, group, and faculty coordinator
-			shift := nullable(trim(get(rec, idx, "shift")))
+			shift := normShift(get(rec, idx, "shift"))
 			groupNo := trim(get(rec, idx, "Group No"))
 			facultyCoordinator := trim(get(rec, idx, "Faculty"))
 			var notesArray []string
@@ -430,8 +964,17 @@ func BulkUpload(pool *pgxpool.Pool) fiber.Handler {
 				notes = &notesStr
 			}
 
-			assignRole := strings.ToLower(defaultIfEmpty(trim(get(rec, idx, "role")), "volunteer"))
-			assignStatus := strings.ToLower(defaultIfEmpty(trim(get(rec, idx, "status")), "assigned"))
+			parsedRole, err := normAssignmentRole(get(rec, idx, "role"))
+			if err != nil {
+				rowErrors = append(rowErrors, rowErr{line, err.Error()})
+				continue
+			}
+			parsedStatus, err := normAssignmentStatus(get(rec, idx, "status"))
+			if err != nil {
+				rowErrors = append(rowErrors, rowErr{line, err.Error()})
+				continue
+			}
+			assignRole, assignStatus := string(parsedRole), string(parsedStatus)
 
 			var rt, startTime, endTime *time.Time
 			if iso := trim(get(rec, idx, "reporting_time_iso")); iso != "" {
@@ -465,6 +1008,10 @@ func BulkUpload(pool *pgxpool.Pool) fiber.Handler {
 			// Try to find volunteer by email or college_id
 			foundVolunteer := false
 			if email != nil && *email != "" {
+				// Held until commit, so faculty registration can't take the email between the checks and the insert
+				if err := hAuth.LockEmail(c.Context(), tx, *email); err != nil {
+					return err
+				}
 				err = tx.QueryRow(c.Context(), `SELECT id FROM volunteers WHERE lower(email)=$1`, *email).Scan(&vID)
 				if err == nil {
 					foundVolunteer = true
@@ -506,9 +1053,9 @@ func BulkUpload(pool *pgxpool.Pool) fiber.Handler {
 					RETURNING id
 				`, name, email, phone, dept, collegeID, models.UserRoleVolunteer).Scan(&vID)
 				if err != nil {
-					if strings.Contains(err.Error(), "volunteers_college_id_key") && collegeID != nil && *collegeID != "" {
+					if db.IsUniqueViolation(err, db.ConstraintVolunteersCollegeID) && collegeID != nil && *collegeID != "" {
 						rowErrors = append(rowErrors, rowErr{line, fmt.Sprintf("Volunteer with college ID '%s' already exists.", *collegeID)})
-					} else if strings.Contains(err.Error(), "volunteers_email_key") && email != nil && *email != "" {
+					} else if db.IsUniqueViolation(err, db.ConstraintVolunteersEmail) && email != nil && *email != "" {
 						rowErrors = append(rowErrors, rowErr{line, fmt.Sprintf("Volunteer with email '%s' already exists.", *email)})
 					} else {
 						rowErrors = append(rowErrors, rowErr{line, fmt.Sprintf("insert volunteer: %v", err)})
@@ -520,28 +1067,22 @@ func BulkUpload(pool *pgxpool.Pool) fiber.Handler {
 
 			// Insert or update assignment
 			var assignmentID int64
-			var onConflictClause string
-			if assignRole == "lead" { // Example: If role is lead, maybe update existing lead assignment or create new
-				onConflictClause = `ON CONFLICT (event_id, committee_id, volunteer_id) DO UPDATE SET
-					role = EXCLUDED.role,
-					status = EXCLUDED.status,
-					reporting_time = EXCLUDED.reporting_time,
-					shift = EXCLUDED.shift,
-					start_time = EXCLUDED.start_time,
-					end_time = EXCLUDED.end_time,
-					notes = EXCLUDED.notes
-				`
-			} else {
-				// Default behavior, assumes unique constraint (event_id, committee_id, volunteer_id) handles updates
-				onConflictClause = `ON CONFLICT (event_id, committee_id, volunteer_id) DO UPDATE SET
-					role = EXCLUDED.role,
-					status = EXCLUDED.status,
-					reporting_time = EXCLUDED.reporting_time,
-					shift = EXCLUDED.shift,
-					start_time = EXCLUDED.start_time,
-					end_time = EXCLUDED.end_time,
-					notes = EXCLUDED.notes
-				`
+			onConflictClause := `ON CONFLICT (event_id, committee_id, volunteer_id) DO UPDATE SET
+				role = EXCLUDED.role,
+				status = ` + keepCancelledSQL("$11") + `,
+				reporting_time = EXCLUDED.reporting_time,
+				shift = EXCLUDED.shift,
+				shift_id = EXCLUDED.shift_id,
+				start_time = EXCLUDED.start_time,
+				end_time = EXCLUDED.end_time,
+				notes = EXCLUDED.notes
+			`
+
+			if assignStatus != string(models.StatusCancelled) && !override {
+				if err := checkShiftCapacity(c.Context(), tx, committeeID, vID, shift); err != nil {
+					rowErrors = append(rowErrors, rowErr{line, err.Error()})
+					continue
+				}
 			}
 
 			// Check if an existing assignment will be updated
@@ -552,11 +1093,11 @@ func BulkUpload(pool *pgxpool.Pool) fiber.Handler {
 			`, eventID, committeeID, vID).Scan(&existingAssignmentID)
 
 			err = tx.QueryRow(c.Context(), `
-				INSERT INTO volunteer_assignments(event_id, committee_id, volunteer_id, role, status, reporting_time, shift, start_time, end_time, notes)
-				VALUES ($1,$2,$3,$4::assignment_role,$5::assignment_status,$6,$7,$8,$9,$10)
+				INSERT INTO volunteer_assignments(event_id, committee_id, volunteer_id, role, status, reporting_time, shift, shift_id, start_time, end_time, notes)
+				VALUES ($1,$2,$3,$4::assignment_role,$5::assignment_status,$6,`+canonicalShiftSQL("$1", "$7", "NOT (s.committee_id = $2 AND s.volunteer_id = $3)")+`,`+shiftIDSQL("$1", "$7")+`,$8,$9,$10)
 				`+onConflictClause+`
 				RETURNING id
-			`, eventID, committeeID, vID, assignRole, assignStatus, rt, shift, startTime, endTime, notes).Scan(&assignmentID)
+			`, eventID, committeeID, vID, assignRole, assignStatus, rt, shift, startTime, endTime, notes, reinstate).Scan(&assignmentID)
 			if err != nil {
 				rowErrors = append(rowErrors, rowErr{line, fmt.Sprintf("insert/update assignment: %v", err)})
 				continue
@@ -592,7 +1133,7 @@ func BulkUpload(pool *pgxpool.Pool) fiber.Handler {
 func ExportVolunteersCSV(pool *pgxpool.Pool) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		rows, err := pool.Query(c.Context(), `
-			SELECT id, name, email, phone, dept, college_id, created_at
+			SELECT id, name, email, phone, dept, college_id, photo_url, created_at, updated_at
 			FROM volunteers ORDER BY name
 		`)
 		if err != nil {
@@ -607,7 +1148,7 @@ func ExportVolunteersCSV(pool *pgxpool.Pool) fiber.Handler {
 		defer writer.Flush()
 
 		// Write CSV header
-		header := []string{"ID", "Name", "Email", "Phone", "Department", "College ID", "Created At"}
+		header := []string{"ID", "Name", "Email", "Phone", "Department", "College ID", "Photo URL", "Created At"}
 		if err := writer.Write(header); err != nil {
 			log.Printf("Error writing CSV header: %v", err)
 			return fiber.NewError(fiber.StatusInternalServerError, "Failed to write CSV header")
@@ -615,7 +1156,7 @@ func ExportVolunteersCSV(pool *pgxpool.Pool) fiber.Handler {
 
 		for rows.Next() {
 			var v models.Volunteer
-			if err := rows.Scan(&v.ID, &v.Name, &v.Email, &v.Phone, &v.Dept, &v.CollegeID, &v.CreatedAt); err != nil {
+			if err := rows.Scan(&v.ID, &v.Name, &v.Email, &v.Phone, &v.Dept, &v.CollegeID, &v.PhotoURL, &v.CreatedAt, &v.UpdatedAt); err != nil {
 				log.Printf("Error scanning volunteer row for export: %v", err)
 				continue
 			}
@@ -627,6 +1168,7 @@ func ExportVolunteersCSV(pool *pgxpool.Pool) fiber.Handler {
 				derefString(v.Phone),
 				derefString(v.Dept),
 				derefString(v.CollegeID),
+				derefString(v.PhotoURL),
 				v.CreatedAt.Format(time.RFC3339),
 			}
 			if err := writer.Write(record); err != nil {
@@ -650,7 +1192,7 @@ func ExportAssignmentsCSV(pool *pgxpool.Pool) fiber.Handler {
 		rows, err := pool.Query(c.Context(), `
 			SELECT
 				va.id, va.event_id, va.committee_id, va.volunteer_id,
-				va.role::text, va.status::text, va.reporting_time, va.shift, va.start_time, va.end_time, va.notes, va.created_at,
+				va.role::text, va.status::text, va.reporting_time, va.shift, va.start_time, va.end_time, va.notes, va.created_at, va.updated_at,
 				v.name AS volunteer_name, v.email AS volunteer_email, v.college_id AS volunteer_college_id, -- NEW
 				c.name AS committee_name,
 				e.name AS event_name
@@ -688,7 +1230,7 @@ func ExportAssignmentsCSV(pool *pgxpool.Pool) fiber.Handler {
 			var volunteerEmail, volunteerCollegeID sql.NullString // NEW: For scanning college_id
 			if err := rows.Scan(
 				&a.ID, &a.EventID, &a.CommitteeID, &a.VolunteerID,
-				&roleStr, &statusStr, &a.ReportingTime, &a.Shift, &a.StartTime, &a.EndTime, &a.Notes, &a.CreatedAt,
+				&roleStr, &statusStr, &a.ReportingTime, &a.Shift, &a.StartTime, &a.EndTime, &a.Notes, &a.CreatedAt, &a.UpdatedAt,
 				&a.VolunteerName, &volunteerEmail, &volunteerCollegeID, // NEW: Scan into volunteerCollegeID
 				&a.CommitteeName, &a.EventName,
 			); err != nil {
@@ -735,78 +1277,174 @@ func ExportAssignmentsCSV(pool *pgxpool.Pool) fiber.Handler {
 
 // --- Admin-Only Assignment CRUD ---
 
-// CreateAssignment - POST /volunteers/assignments (Admin)
-// Creates a specific assignment for an existing volunteer.
+// CreateAssignment - POST /volunteers/assignments[?override=true&reinstate=true] (Admin)
+// Creates a specific assignment for an existing volunteer; invalid fields are a 422 validation_failed.
+// shift_id links one of the event's shifts
+// (and sets shift to its name); a plain shift is linked when the event defines a shift by that name.
+// Returns 409 when the committee's shift is at capacity, unless override=true.
+// An existing assignment is updated in place, but a cancelled one keeps its status unless reinstate=true.
 func CreateAssignment(pool *pgxpool.Pool) fiber.Handler {
 	return func(c *fiber.Ctx) error {
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
 		var b models.CreateVolunteerAssignmentRequest
+		if err := mw.StrictBodyParser(c, &b); err != nil {
+			return err
+		}
+		var v validation.Validator
+		v.Check(b.EventID > 0, "event_id", validation.FieldRequired)
+		v.Check(b.CommitteeID > 0, "committee_id", validation.FieldRequired)
+		v.Check(b.VolunteerID > 0, "volunteer_id", validation.FieldRequired)
+		role, err := normAssignmentRole(string(b.Role))
+		v.Check(err == nil, "role", validation.FieldInvalidValue)
+		status, err := normAssignmentStatus(string(b.Status))
+		v.Check(err == nil, "status", validation.FieldInvalidValue)
+		if err := v.Err(); err != nil {
+			return err
+		}
+
+		shift := normShiftPtr(b.Shift)
+
+		tx, err := pool.Begin(ctx)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback(ctx)
+
+		// A shift_id wins over the shift text; the shift's name becomes the assignment's shift
+		if b.ShiftID != nil {
+			name, err := hShifts.Resolve(ctx, tx, b.EventID, *b.ShiftID)
+			if err != nil {
+				return err
+			}
+			shift = &name
+		}
+
+		if status != models.StatusCancelled && !c.QueryBool("override") {
+			if err := checkShiftCapacity(ctx, tx, b.CommitteeID, b.VolunteerID, shift); err != nil {
+				return err
+			}
+		}
+
+		// Upsert and read back the enriched row in one statement
+		var assignment models.VolunteerAssignment
+		err = scanEnrichedAssignment(tx.QueryRow(ctx, `
+			WITH upserted AS (
+				INSERT INTO volunteer_assignments(event_id, committee_id, volunteer_id, role, status, reporting_time, shift, shift_id, start_time, end_time, notes)
+				VALUES ($1,$2,$3,$4::assignment_role,$5::assignment_status,$6,`+canonicalShiftSQL("$1", "$7", "NOT (s.committee_id = $2 AND s.volunteer_id = $3)")+`,`+shiftIDSQL("$1", "$7")+`,$8,$9,$10)
+				ON CONFLICT (event_id, committee_id, volunteer_id) DO UPDATE SET
+					role = EXCLUDED.role,
+					status = `+keepCancelledSQL("$11")+`,
+					reporting_time = EXCLUDED.reporting_time,
+					shift = EXCLUDED.shift,
+					shift_id = EXCLUDED.shift_id,
+					start_time = EXCLUDED.start_time,
+					end_time = EXCLUDED.end_time,
+					notes = EXCLUDED.notes
+				RETURNING *
+			)
+			SELECT `+enrichedAssignmentColumns+enrichedAssignmentFrom("upserted")+`
+		`, b.EventID, b.CommitteeID, b.VolunteerID, role, status, b.ReportingTime, shift, b.StartTime, b.EndTime, b.Notes,
+			c.QueryBool("reinstate")), &assignment)
+		if err != nil {
+			return err
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return err
+		}
+
+		return c.Status(fiber.StatusCreated).JSON(assignment)
+	}
+}
+
+// CopyAssignments - POST /volunteers/assignments/copy (Admin)
+// Copies every non-cancelled assignment of from_committee_id into to_committee_id/to_event_id,
+// keeping role and notes (shift is replaced by new_shift when given). Copies start out 'assigned'
+// with no reporting/start/end times, since the source event's times don't carry over.
+// Volunteers already assigned to the target are skipped.
+func CopyAssignments(pool *pgxpool.Pool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
+		var b models.CopyAssignmentsRequest
 		if err := c.BodyParser(&b); err != nil {
 			return fiber.NewError(fiber.StatusBadRequest, "Bad JSON")
 		}
-		if b.EventID <= 0 || b.CommitteeID <= 0 || b.VolunteerID <= 0 {
-			return fiber.NewError(fiber.StatusBadRequest, "Event ID, Committee ID, and Volunteer ID are required")
+		if b.FromCommitteeID <= 0 || b.ToCommitteeID <= 0 || b.ToEventID <= 0 {
+			return fiber.NewError(fiber.StatusBadRequest, "from_committee_id, to_committee_id and to_event_id are required")
+		}
+		if b.FromCommitteeID == b.ToCommitteeID {
+			return fiber.NewError(fiber.StatusBadRequest, "from_committee_id and to_committee_id must differ")
+		}
+		var newShift *string
+		if b.NewShift != nil {
+			newShift = nullable(strings.TrimSpace(*b.NewShift))
 		}
 
-		role := normAssignmentRole(string(b.Role))
-		status := normAssignmentStatus(string(b.Status))
+		tx, err := pool.Begin(ctx)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback(ctx)
 
-		var assignment models.VolunteerAssignment
-		var roleStr, statusStr string
-		var volunteerEmail, volunteerCollegeID sql.NullString // NEW: For enriched fields
-		// The RETURNING clause needs to match the structure of the SELECT below for enriched fields
-		err := pool.QueryRow(c.Context(), `
-			INSERT INTO volunteer_assignments(event_id, committee_id, volunteer_id, role, status, reporting_time, shift, start_time, end_time, notes)
-			VALUES ($1,$2,$3,$4::assignment_role,$5::assignment_status,$6,$7,$8,$9,$10)
-			ON CONFLICT (event_id, committee_id, volunteer_id) DO UPDATE SET
-				role = EXCLUDED.role,
-				status = EXCLUDED.status,
-				reporting_time = EXCLUDED.reporting_time,
-				shift = EXCLUDED.shift,
-				start_time = EXCLUDED.start_time,
-				end_time = EXCLUDED.end_time,
-				notes = EXCLUDED.notes
-			RETURNING id, event_id, committee_id, volunteer_id, role::text, status::text, 
-				reporting_time, shift, start_time, end_time, notes, created_at
-		`, b.EventID, b.CommitteeID, b.VolunteerID, role, status, b.ReportingTime, b.Shift, b.StartTime, b.EndTime, b.Notes).
-			Scan(&assignment.ID, &assignment.EventID, &assignment.CommitteeID, &assignment.VolunteerID,
-				&roleStr, &statusStr, &assignment.ReportingTime, &assignment.Shift, &assignment.StartTime, &assignment.EndTime, &assignment.Notes, &assignment.CreatedAt)
+		var fromExists, targetOK bool
+		err = tx.QueryRow(ctx, `
+			SELECT EXISTS(SELECT 1 FROM committees WHERE id = $1),
+			       EXISTS(SELECT 1 FROM committees WHERE id = $2 AND event_id = $3)
+		`, b.FromCommitteeID, b.ToCommitteeID, b.ToEventID).Scan(&fromExists, &targetOK)
 		if err != nil {
 			return err
 		}
-		assignment.Role = models.AssignmentRole(roleStr)
-		assignment.Status = models.AssignmentStatus(statusStr)
+		if !fromExists {
+			return fiber.NewError(fiber.StatusNotFound, "Source committee not found")
+		}
+		if !targetOK {
+			return fiber.NewError(fiber.StatusUnprocessableEntity, "to_committee_id does not belong to to_event_id")
+		}
+
+		var total int64
+		if err := tx.QueryRow(ctx, `
+			SELECT COUNT(*) FROM volunteer_assignments WHERE committee_id = $1 AND status <> 'cancelled'
+		`, b.FromCommitteeID).Scan(&total); err != nil {
+			return err
+		}
 
-		// Now fetch the enriched fields after the insert/update
-		err = pool.QueryRow(c.Context(), `
-			SELECT 
-				v.name AS volunteer_name, v.email AS volunteer_email, v.college_id AS volunteer_college_id,
-				c.name AS committee_name, e.name AS event_name
-			FROM volunteer_assignments va
-			JOIN volunteers v ON v.id = va.volunteer_id
-			JOIN committees c ON c.id = va.committee_id
-			JOIN events e ON e.id = va.event_id
-			WHERE va.id = $1
-		`, assignment.ID).Scan(
-			&assignment.VolunteerName, &volunteerEmail, &volunteerCollegeID,
-			&assignment.CommitteeName, &assignment.EventName,
-		)
+		cmd, err := tx.Exec(ctx, `
+			INSERT INTO volunteer_assignments(event_id, committee_id, volunteer_id, role, status, reporting_time, shift, shift_id, start_time, end_time, notes)
+			SELECT $2, $3, volunteer_id, role, 'assigned', NULL, COALESCE($4, shift), `+shiftIDSQL("$2", "COALESCE($4, shift)")+`, NULL, NULL, notes
+			FROM volunteer_assignments
+			WHERE committee_id = $1 AND status <> 'cancelled'
+			ON CONFLICT (event_id, committee_id, volunteer_id) DO NOTHING
+		`, b.FromCommitteeID, b.ToEventID, b.ToCommitteeID, newShift)
 		if err != nil {
-			// This would be an unexpected error if the assignment was just created/updated
-			log.Printf("Error fetching enriched assignment fields: %v", err)
-			// Decide how to handle this - either return error or proceed with partial data
+			return err
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return err
 		}
-		assignment.VolunteerEmail = derefNullString(volunteerEmail)
-		assignment.VolunteerCollegeID = derefNullString(volunteerCollegeID)
 
-		return c.Status(fiber.StatusCreated).JSON(assignment)
+		copied := cmd.RowsAffected()
+		return c.JSON(fiber.Map{
+			"copied":  copied,
+			"skipped": total - copied,
+		})
 	}
 }
 
-// ListAssignments - GET /volunteers/assignments?event_id=&committee_id=&volunteer_id=&shift=&start_date=YYYY-MM-DD&end_date=YYYY-MM-DD&limit=&offset= (Admin)
-// Lists all assignments, with optional filters.
+// ListAssignments - GET /volunteers/assignments?event_id=&committee_id=&volunteer_id=&shift_id=&shift=&exact_shift=false&start_date=YYYY-MM-DD&end_date=YYYY-MM-DD&limit=&offset= (Admin)
+// Lists all assignments, with optional filters. shift_id matches the linked shift exactly and takes
+// precedence over the free-text shift filter, which is a substring match unless exact_shift=true.
 func ListAssignments(pool *pgxpool.Pool) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		filters := buildAssignmentFilters(c) // New helper to build filters
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
+		filters, err := buildAssignmentFilters(c) // New helper to build filters
+		if err != nil {
+			return err
+		}
 
 		args := []any{}
 		whereClauses := []string{}
@@ -827,18 +1465,26 @@ func ListAssignments(pool *pgxpool.Pool) fiber.Handler {
 			args = append(args, filters.VolunteerID.Int64)
 			paramCounter++
 		}
-		if filters.Shift.Valid {
+		if filters.ShiftID.Valid {
+			whereClauses = append(whereClauses, "va.shift_id = $"+itoa(paramCounter))
+			args = append(args, filters.ShiftID.Int64)
+			paramCounter++
+		} else if filters.Shift.Valid && filters.ExactShift {
+			whereClauses = append(whereClauses, "lower(va.shift) = lower($"+itoa(paramCounter)+")")
+			args = append(args, filters.Shift.String)
+			paramCounter++
+		} else if filters.Shift.Valid {
 			whereClauses = append(whereClauses, "va.shift ILIKE $"+itoa(paramCounter))
 			args = append(args, "%"+filters.Shift.String+"%")
 			paramCounter++
 		}
 		if filters.StartDate.Valid {
-			whereClauses = append(whereClauses, "DATE(va.start_time) >= $"+itoa(paramCounter))
+			whereClauses = append(whereClauses, "(va.start_time AT TIME ZONE e.tz)::date >= $"+itoa(paramCounter))
 			args = append(args, filters.StartDate.Time)
 			paramCounter++
 		}
 		if filters.EndDate.Valid {
-			whereClauses = append(whereClauses, "DATE(va.start_time) <= $"+itoa(paramCounter))
+			whereClauses = append(whereClauses, "(va.start_time AT TIME ZONE e.tz)::date <= $"+itoa(paramCounter))
 			args = append(args, filters.EndDate.Time)
 			paramCounter++
 		}
@@ -849,22 +1495,13 @@ func ListAssignments(pool *pgxpool.Pool) fiber.Handler {
 		}
 
 		query := `
-			SELECT
-				va.id, va.event_id, va.committee_id, va.volunteer_id,
-				va.role::text, va.status::text, va.reporting_time, va.shift, va.start_time, va.end_time, va.notes, va.created_at,
-				v.name AS volunteer_name, v.email AS volunteer_email, v.college_id AS volunteer_college_id, -- NEW
-				c.name AS committee_name,
-				e.name AS event_name
-			FROM volunteer_assignments va
-			JOIN volunteers v ON v.id = va.volunteer_id
-			JOIN committees c ON c.id = va.committee_id
-			JOIN events e ON e.id = va.event_id
+			SELECT ` + enrichedAssignmentColumns + enrichedAssignmentFrom("volunteer_assignments") + `
 			` + where + `
 			ORDER BY va.start_time DESC, va.created_at DESC
 			LIMIT $` + itoa(paramCounter) + ` OFFSET $` + itoa(paramCounter+1)
 		args = append(args, filters.Limit, filters.Offset)
 
-		rows, err := pool.Query(c.Context(), query, args...)
+		rows, err := pool.Query(ctx, query, args...)
 		if err != nil {
 			log.Printf("Error querying all assignments: %v", err)
 			return err
@@ -874,20 +1511,10 @@ func ListAssignments(pool *pgxpool.Pool) fiber.Handler {
 		out := []models.VolunteerAssignment{}
 		for rows.Next() {
 			var a models.VolunteerAssignment
-			var roleStr, statusStr string
-			var volunteerEmail, volunteerCollegeID sql.NullString // NEW
-			if err := rows.Scan(
-				&a.ID, &a.EventID, &a.CommitteeID, &a.VolunteerID,
-				&roleStr, &statusStr, &a.ReportingTime, &a.Shift, &a.StartTime, &a.EndTime, &a.Notes, &a.CreatedAt,
-				&a.VolunteerName, &volunteerEmail, &volunteerCollegeID, &a.CommitteeName, &a.EventName, // NEW
-			); err != nil {
+			if err := scanEnrichedAssignment(rows, &a); err != nil {
 				log.Printf("Error scanning assignment row: %v", err)
 				return err
 			}
-			a.Role = models.AssignmentRole(roleStr)
-			a.Status = models.AssignmentStatus(statusStr)
-			a.VolunteerEmail = derefNullString(volunteerEmail)         // NEW
-			a.VolunteerCollegeID = derefNullString(volunteerCollegeID) // NEW
 			out = append(out, a)
 		}
 		if err := rows.Err(); err != nil {
@@ -898,60 +1525,157 @@ func ListAssignments(pool *pgxpool.Pool) fiber.Handler {
 	}
 }
 
+// ListShifts - GET /volunteers/shifts?event_id=&committee_id= (Admin, Faculty)
+// Event-scoped faculty must pass one of their own events as event_id.
+// Lists the distinct non-null shift names in scope with how many assignments use each, for shift filter dropdowns.
+func ListShifts(pool *pgxpool.Pool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
+		args := []any{}
+		whereClauses := []string{"shift IS NOT NULL"}
+		for _, key := range []string{"event_id", "committee_id"} {
+			raw := c.Query(key, "")
+			if raw == "" {
+				continue
+			}
+			id, err := strconv.ParseInt(raw, 10, 64)
+			if err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "invalid "+key)
+			}
+			args = append(args, id)
+			whereClauses = append(whereClauses, key+"=$"+itoa(len(args)))
+		}
+
+		rows, err := pool.Query(ctx, `
+			SELECT shift, COUNT(*)
+			FROM volunteer_assignments
+			WHERE `+strings.Join(whereClauses, " AND ")+`
+			GROUP BY shift
+			ORDER BY shift
+		`, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		out := []models.ShiftSummary{}
+		for rows.Next() {
+			var s models.ShiftSummary
+			if err := rows.Scan(&s.Shift, &s.AssignmentCount); err != nil {
+				return err
+			}
+			out = append(out, s)
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		return c.JSON(out)
+	}
+}
+
+// enrichedAssignmentColumns is the SELECT list read by scanEnrichedAssignment: the assignment plus
+// the volunteer, committee and event names. Use it with enrichedAssignmentFrom for the joins.
+const enrichedAssignmentColumns = `
+	va.id, va.event_id, va.committee_id, va.volunteer_id,
+	va.role::text, va.status::text, va.reporting_time, va.shift, va.shift_id, va.start_time, va.end_time, va.notes, va.created_at, va.updated_at,
+	v.name AS volunteer_name, v.email AS volunteer_email, v.college_id AS volunteer_college_id,
+	c.name AS committee_name, e.name AS event_name`
+
+// enrichedAssignmentFrom joins source (volunteer_assignments, or a CTE over it such as an
+// INSERT ... RETURNING *) aliased va to its volunteer v, committee c and event e.
+func enrichedAssignmentFrom(source string) string {
+	return `
+	FROM ` + source + ` va
+	JOIN volunteers v ON v.id = va.volunteer_id
+	JOIN committees c ON c.id = va.committee_id
+	JOIN events e ON e.id = va.event_id`
+}
+
+// scanEnrichedAssignment scans a row selected with enrichedAssignmentColumns into a; extra receives
+// any columns the query selects after them.
+func scanEnrichedAssignment(row pgx.Row, a *models.VolunteerAssignment, extra ...any) error {
+	var roleStr, statusStr string
+	var volunteerEmail, volunteerCollegeID sql.NullString
+	dest := append([]any{
+		&a.ID, &a.EventID, &a.CommitteeID, &a.VolunteerID,
+		&roleStr, &statusStr, &a.ReportingTime, &a.Shift, &a.ShiftID, &a.StartTime, &a.EndTime, &a.Notes, &a.CreatedAt, &a.UpdatedAt,
+		&a.VolunteerName, &volunteerEmail, &volunteerCollegeID, &a.CommitteeName, &a.EventName,
+	}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return err
+	}
+	a.Role = models.AssignmentRole(roleStr)
+	a.Status = models.AssignmentStatus(statusStr)
+	a.VolunteerEmail = derefNullString(volunteerEmail)
+	a.VolunteerCollegeID = derefNullString(volunteerCollegeID)
+	return nil
+}
+
 // GetAssignmentByID - GET /volunteers/assignments/:id (Admin)
 func GetAssignmentByID(pool *pgxpool.Pool) fiber.Handler {
 	return func(c *fiber.Ctx) error {
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
 		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
 		if err != nil || id <= 0 {
 			return fiber.NewError(fiber.StatusBadRequest, "Invalid assignment ID")
 		}
 
 		var a models.VolunteerAssignment
-		var roleStr, statusStr string
-		var volunteerEmail, volunteerCollegeID sql.NullString // NEW
-		err = pool.QueryRow(c.Context(), `
-			SELECT
-				va.id, va.event_id, va.committee_id, va.volunteer_id,
-				va.role::text, va.status::text, va.reporting_time, va.shift, va.start_time, va.end_time, va.notes, va.created_at,
-				v.name AS volunteer_name, v.email AS volunteer_email, v.college_id AS volunteer_college_id, -- NEW
-				c.name AS committee_name,
-				e.name AS event_name
-			FROM volunteer_assignments va
-			JOIN volunteers v ON v.id = va.volunteer_id
-			JOIN committees c ON c.id = va.committee_id
-			JOIN events e ON e.id = va.event_id
+		err = scanEnrichedAssignment(pool.QueryRow(ctx, `
+			SELECT `+enrichedAssignmentColumns+enrichedAssignmentFrom("volunteer_assignments")+`
 			WHERE va.id = $1
-		`, id).Scan(
-			&a.ID, &a.EventID, &a.CommitteeID, &a.VolunteerID,
-			&roleStr, &statusStr, &a.ReportingTime, &a.Shift, &a.StartTime, &a.EndTime, &a.Notes, &a.CreatedAt,
-			&a.VolunteerName, &volunteerEmail, &volunteerCollegeID, &a.CommitteeName, &a.EventName, // NEW
-		)
+		`, id), &a)
 		if err != nil {
 			if errors.Is(err, sql.ErrNoRows) {
 				return fiber.NewError(fiber.StatusNotFound, "Assignment not found")
 			}
 			return err
 		}
-		a.Role = models.AssignmentRole(roleStr)
-		a.Status = models.AssignmentStatus(statusStr)
-		a.VolunteerEmail = derefNullString(volunteerEmail)         // NEW
-		a.VolunteerCollegeID = derefNullString(volunteerCollegeID) // NEW
 		return c.JSON(a)
 	}
 }
 
-// UpdateAssignment - PUT /volunteers/assignments/:id (Admin)
+// UpdateAssignment - PUT /volunteers/assignments/:id[?reinstate=true] (Admin)
+// A cancelled assignment can only be moved back to assigned/standby with reinstate=true.
+// expected_updated_at guards against concurrent edits the same way as UpdateVolunteer.
 func UpdateAssignment(pool *pgxpool.Pool) fiber.Handler {
 	return func(c *fiber.Ctx) error {
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
 		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
 		if err != nil || id <= 0 {
 			return fiber.NewError(fiber.StatusBadRequest, "Invalid assignment ID")
 		}
 
 		var b models.UpdateVolunteerAssignmentRequest
-		if err := c.BodyParser(&b); err != nil {
-			return fiber.NewError(fiber.StatusBadRequest, "Bad JSON")
+		if err := mw.StrictBodyParser(c, &b); err != nil {
+			return err
+		}
+		var v validation.Validator
+		var role models.AssignmentRole
+		var status models.AssignmentStatus
+		if b.Role != nil {
+			role, err = normAssignmentRole(string(*b.Role))
+			v.Check(err == nil, "role", validation.FieldInvalidValue)
+		}
+		if b.Status != nil {
+			status, err = normAssignmentStatus(string(*b.Status))
+			v.Check(err == nil, "status", validation.FieldInvalidValue)
+		}
+		if err := v.Err(); err != nil {
+			return err
+		}
+
+		tx, err := pool.Begin(ctx)
+		if err != nil {
+			return err
 		}
+		defer tx.Rollback(ctx)
 
 		sets := []string{}
 		args := []any{}
@@ -959,12 +1683,23 @@ func UpdateAssignment(pool *pgxpool.Pool) fiber.Handler {
 
 		if b.Role != nil {
 			sets = append(sets, "role=$"+itoa(i)+`::assignment_role`)
-			args = append(args, normAssignmentRole(string(*b.Role)))
+			args = append(args, role)
 			i++
 		}
 		if b.Status != nil {
+			// Locked until commit, so the status can't be cancelled between this check and the update
+			var current string
+			if err := tx.QueryRow(ctx, `SELECT status::text FROM volunteer_assignments WHERE id=$1 FOR UPDATE`, id).Scan(&current); err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					return fiber.NewError(fiber.StatusNotFound, "Assignment not found")
+				}
+				return err
+			}
+			if models.AssignmentStatus(current) == models.StatusCancelled && status != models.StatusCancelled && !c.QueryBool("reinstate") {
+				return fiber.NewError(fiber.StatusConflict, "Assignment is cancelled; pass ?reinstate=true to change its status")
+			}
 			sets = append(sets, "status=$"+itoa(i)+`::assignment_status`)
-			args = append(args, normAssignmentStatus(string(*b.Status)))
+			args = append(args, status)
 			i++
 		}
 		if b.ReportingTime != nil {
@@ -972,9 +1707,27 @@ func UpdateAssignment(pool *pgxpool.Pool) fiber.Handler {
 			args = append(args, *b.ReportingTime)
 			i++
 		}
-		if b.Shift != nil {
-			sets = append(sets, "shift=$"+itoa(i))
-			args = append(args, nullable(strings.TrimSpace(*b.Shift)))
+		if b.ShiftID != nil {
+			// A shift_id wins over the shift text; the shift's name becomes the assignment's shift
+			var eventID int64
+			if err := tx.QueryRow(ctx, `SELECT event_id FROM volunteer_assignments WHERE id=$1`, id).Scan(&eventID); err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					return fiber.NewError(fiber.StatusNotFound, "Assignment not found")
+				}
+				return err
+			}
+			name, err := hShifts.Resolve(ctx, tx, eventID, *b.ShiftID)
+			if err != nil {
+				return err
+			}
+			sets = append(sets, "shift=$"+itoa(i), "shift_id=$"+itoa(i+1))
+			args = append(args, name, *b.ShiftID)
+			i += 2
+		} else if b.Shift != nil {
+			p := "$" + itoa(i)
+			sets = append(sets, "shift="+canonicalShiftSQL("volunteer_assignments.event_id", p, "s.id <> volunteer_assignments.id"),
+				"shift_id="+shiftIDSQL("volunteer_assignments.event_id", p))
+			args = append(args, normShift(*b.Shift))
 			i++
 		}
 		if b.StartTime != nil {
@@ -996,33 +1749,228 @@ func UpdateAssignment(pool *pgxpool.Pool) fiber.Handler {
 		if len(sets) == 0 {
 			return fiber.NewError(fiber.StatusBadRequest, "No fields to update")
 		}
+		if b.ReportingTime != nil || b.StartTime != nil {
+			sets = append(sets, "reminder_sent_at=NULL") // Re-arm the shift reminder for the new time
+		}
 		args = append(args, id)
+		where := ` WHERE id=$` + itoa(i)
+		if b.ExpectedUpdatedAt != nil {
+			i++
+			where += ` AND updated_at=$` + itoa(i)
+			args = append(args, *b.ExpectedUpdatedAt)
+		}
 
-		sqlQuery := `UPDATE volunteer_assignments SET ` + strings.Join(sets, ", ") + ` WHERE id=$` + itoa(i)
-		cmd, err := pool.Exec(c.Context(), sqlQuery, args...)
+		sqlQuery := `UPDATE volunteer_assignments SET ` + strings.Join(sets, ", ") + where
+		cmd, err := tx.Exec(ctx, sqlQuery, args...)
 		if err != nil {
 			return err
 		}
 		if cmd.RowsAffected() == 0 {
+			if b.ExpectedUpdatedAt != nil {
+				return staleUpdate(ctx, c, pool, "volunteer_assignments", id, "Assignment not found")
+			}
 			return fiber.NewError(fiber.StatusNotFound, "Assignment not found")
 		}
+		if err := tx.Commit(ctx); err != nil {
+			return err
+		}
 		return c.SendStatus(fiber.StatusNoContent)
 	}
 }
 
-// DeleteAssignment - DELETE /volunteers/assignments/:id (Admin)
+// ReassignAssignment - PATCH /volunteers/assignments/:id/reassign[?override=true] (Admin)
+// Moves an assignment to committee_id (in event_id, default the current event) keeping its id, notes,
+// times and attendance. Returns 409 with existing_assignment_id when the volunteer already has an
+// assignment there, and 409 when the target shift is at capacity unless override=true.
+func ReassignAssignment(pool *pgxpool.Pool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil || id <= 0 {
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid assignment ID")
+		}
+		var b models.ReassignAssignmentRequest
+		if err := mw.StrictBodyParser(c, &b); err != nil {
+			return err
+		}
+		if b.CommitteeID <= 0 {
+			return fiber.NewError(fiber.StatusBadRequest, "committee_id is required")
+		}
+
+		tx, err := pool.Begin(ctx)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback(ctx)
+
+		var eventID, committeeID, volunteerID int64
+		var status string
+		var shift *string
+		err = tx.QueryRow(ctx, `
+			SELECT event_id, committee_id, volunteer_id, status::text, shift
+			FROM volunteer_assignments WHERE id = $1
+			FOR UPDATE
+		`, id).Scan(&eventID, &committeeID, &volunteerID, &status, &shift)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fiber.NewError(fiber.StatusNotFound, "Assignment not found")
+			}
+			return err
+		}
+		targetEventID := eventID
+		if b.EventID != nil {
+			targetEventID = *b.EventID
+		}
+
+		if targetEventID != eventID || b.CommitteeID != committeeID {
+			var committeeEventID int64
+			err = tx.QueryRow(ctx, `SELECT event_id FROM committees WHERE id = $1`, b.CommitteeID).Scan(&committeeEventID)
+			if err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					return fiber.NewError(fiber.StatusNotFound, "Committee not found")
+				}
+				return err
+			}
+			if committeeEventID != targetEventID {
+				return fiber.NewError(fiber.StatusUnprocessableEntity, "committee_id does not belong to event_id")
+			}
+
+			var existingID int64
+			err = tx.QueryRow(ctx, `
+				SELECT id FROM volunteer_assignments WHERE event_id = $1 AND committee_id = $2 AND volunteer_id = $3
+			`, targetEventID, b.CommitteeID, volunteerID).Scan(&existingID)
+			if err == nil {
+				return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+					"error":                  "volunteer is already assigned to this committee; update or delete that assignment instead",
+					"existing_assignment_id": existingID,
+				})
+			}
+			if !errors.Is(err, sql.ErrNoRows) {
+				return err
+			}
+
+			if models.AssignmentStatus(status) != models.StatusCancelled && !c.QueryBool("override") {
+				if err := checkShiftCapacity(ctx, tx, b.CommitteeID, volunteerID, shift); err != nil {
+					return err
+				}
+			}
+
+			// Re-spell the shift to match the target event's shift names and relink it to that event's shift
+			_, err = tx.Exec(ctx, `
+				UPDATE volunteer_assignments
+				SET event_id = $2, committee_id = $3,
+				    shift = `+canonicalShiftSQL("$2", "volunteer_assignments.shift", "s.id <> volunteer_assignments.id")+`,
+				    shift_id = `+shiftIDSQL("$2", "volunteer_assignments.shift")+`
+				WHERE id = $1
+			`, id, targetEventID, b.CommitteeID)
+			if err != nil {
+				if db.IsUniqueViolation(err, db.ConstraintAssignmentsUnique) {
+					return fiber.NewError(fiber.StatusConflict, "Volunteer is already assigned to this committee for this event")
+				}
+				return err
+			}
+		}
+
+		var a models.VolunteerAssignment
+		if err := scanEnrichedAssignment(tx.QueryRow(ctx, `
+			SELECT `+enrichedAssignmentColumns+enrichedAssignmentFrom("volunteer_assignments")+`
+			WHERE va.id = $1
+		`, id), &a); err != nil {
+			return err
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return err
+		}
+		return c.JSON(a)
+	}
+}
+
+// DeleteAssignment - DELETE /volunteers/assignments/:id[?reassign_to=<assignment_id>|?force=true] (Admin)
+// Assignments with attendance history are not deleted silently (that would cascade the attendance away):
+//   - by default the request is rejected with 409 and the attendance count;
+//   - reassign_to moves the attendance to another assignment of the same volunteer, then deletes;
+//   - force=true soft-deletes instead: the assignment is marked cancelled and its attendance kept.
+//
+// Assignments without attendance are deleted outright.
 func DeleteAssignment(pool *pgxpool.Pool) fiber.Handler {
 	return func(c *fiber.Ctx) error {
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
 		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
 		if err != nil || id <= 0 {
 			return fiber.NewError(fiber.StatusBadRequest, "Invalid assignment ID")
 		}
-		cmd, err := pool.Exec(c.Context(), `DELETE FROM volunteer_assignments WHERE id=$1`, id)
+		var reassignTo int64
+		if v := c.Query("reassign_to"); v != "" {
+			if reassignTo, err = strconv.ParseInt(v, 10, 64); err != nil || reassignTo <= 0 || reassignTo == id {
+				return fiber.NewError(fiber.StatusBadRequest, "Invalid reassign_to")
+			}
+		}
+
+		tx, err := pool.Begin(ctx)
 		if err != nil {
 			return err
 		}
-		if cmd.RowsAffected() == 0 {
-			return fiber.NewError(fiber.StatusNotFound, "Assignment not found")
+		defer tx.Rollback(ctx)
+
+		var volunteerID, attendanceCount int64
+		err = tx.QueryRow(ctx, `
+			SELECT va.volunteer_id, (SELECT COUNT(*) FROM attendance a WHERE a.assignment_id = va.id)
+			FROM volunteer_assignments va
+			WHERE va.id = $1
+			FOR UPDATE
+		`, id).Scan(&volunteerID, &attendanceCount)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fiber.NewError(fiber.StatusNotFound, "Assignment not found")
+			}
+			return err
+		}
+
+		switch {
+		case attendanceCount == 0:
+			// Nothing to preserve
+		case reassignTo > 0:
+			var targetVolunteerID int64
+			err := tx.QueryRow(ctx, `SELECT volunteer_id FROM volunteer_assignments WHERE id = $1`, reassignTo).Scan(&targetVolunteerID)
+			if err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					return fiber.NewError(fiber.StatusUnprocessableEntity, "reassign_to assignment not found")
+				}
+				return err
+			}
+			if targetVolunteerID != volunteerID {
+				return fiber.NewError(fiber.StatusUnprocessableEntity, "reassign_to must belong to the same volunteer")
+			}
+			if _, err := tx.Exec(ctx, `UPDATE attendance SET assignment_id = $2 WHERE assignment_id = $1`, id, reassignTo); err != nil {
+				return err
+			}
+		case c.QueryBool("force"):
+			if _, err := tx.Exec(ctx, `UPDATE volunteer_assignments SET status = 'cancelled' WHERE id = $1`, id); err != nil {
+				return err
+			}
+			if err := tx.Commit(ctx); err != nil {
+				return err
+			}
+			return c.JSON(fiber.Map{"status": "cancelled", "attendance_kept": attendanceCount})
+		default:
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error":      "assignment has attendance records; pass ?reassign_to=<assignment_id> to move them or ?force=true to cancel the assignment instead",
+				"attendance": attendanceCount,
+			})
+		}
+
+		if _, err := tx.Exec(ctx, `DELETE FROM volunteer_assignments WHERE id=$1`, id); err != nil {
+			return err
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return err
+		}
+		if reassignTo > 0 && attendanceCount > 0 {
+			return c.JSON(fiber.Map{"status": "deleted", "attendance_reassigned": attendanceCount, "reassigned_to": reassignTo})
 		}
 		return c.SendStatus(fiber.StatusNoContent)
 	}
@@ -1033,29 +1981,44 @@ func DeleteAssignment(pool *pgxpool.Pool) fiber.Handler {
 // GetMyProfile - GET /volunteers/me (Volunteer)
 func GetMyProfile(pool *pgxpool.Pool) fiber.Handler {
 	return func(c *fiber.Ctx) error {
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
 		volunteerID, err := mw.GetUserIDFromClaims(c)
 		if err != nil {
 			return fiber.NewError(fiber.StatusUnauthorized, "Volunteer ID not found in token")
 		}
 
-		var v models.Volunteer
-		err = pool.QueryRow(c.Context(), `
-			SELECT id, name, email, phone, dept, college_id, created_at
-			FROM volunteers WHERE id = $1
-		`, volunteerID).Scan(&v.ID, &v.Name, &v.Email, &v.Phone, &v.Dept, &v.CollegeID, &v.CreatedAt)
+		v, err := myProfile(ctx, pool, volunteerID)
 		if err != nil {
-			if errors.Is(err, sql.ErrNoRows) {
-				return fiber.NewError(fiber.StatusNotFound, "Your volunteer profile not found")
-			}
 			return err
 		}
 		return c.JSON(v)
 	}
 }
 
+// myProfile loads the logged-in volunteer's own record; 404 if it no longer exists.
+func myProfile(ctx context.Context, pool *pgxpool.Pool, volunteerID int64) (models.Volunteer, error) {
+	var v models.Volunteer
+	err := pool.QueryRow(ctx, `
+		SELECT id, name, email, phone, dept, college_id, photo_url, created_at, updated_at
+		FROM volunteers WHERE id = $1
+	`, volunteerID).Scan(&v.ID, &v.Name, &v.Email, &v.Phone, &v.Dept, &v.CollegeID, &v.PhotoURL, &v.CreatedAt, &v.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return v, fiber.NewError(fiber.StatusNotFound, "Your volunteer profile not found")
+		}
+		return v, err
+	}
+	return v, nil
+}
+
 // SetMyPassword - POST /volunteers/me/set-password (Volunteer)
 func SetMyPassword(pool *pgxpool.Pool) fiber.Handler {
 	return func(c *fiber.Ctx) error {
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
 		volunteerID, err := mw.GetUserIDFromClaims(c)
 		if err != nil {
 			return fiber.NewError(fiber.StatusUnauthorized, "Volunteer ID not found in token")
@@ -1070,7 +2033,7 @@ func SetMyPassword(pool *pgxpool.Pool) fiber.Handler {
 		}
 
 		var currentPasswordHash sql.NullString
-		err = pool.QueryRow(c.Context(), `SELECT password_hash FROM volunteers WHERE id = $1`, volunteerID).Scan(&currentPasswordHash)
+		err = pool.QueryRow(ctx, `SELECT password_hash FROM volunteers WHERE id = $1`, volunteerID).Scan(&currentPasswordHash)
 		if err != nil {
 			if errors.Is(err, sql.ErrNoRows) {
 				return fiber.NewError(fiber.StatusNotFound, "Volunteer not found")
@@ -1096,7 +2059,7 @@ func SetMyPassword(pool *pgxpool.Pool) fiber.Handler {
 			return err
 		}
 
-		cmd, err := pool.Exec(c.Context(), `UPDATE volunteers SET password_hash = $1 WHERE id = $2`, newHash, volunteerID)
+		cmd, err := pool.Exec(ctx, `UPDATE volunteers SET password_hash = $1 WHERE id = $2`, newHash, volunteerID)
 		if err != nil {
 			return err
 		}
@@ -1107,10 +2070,74 @@ func SetMyPassword(pool *pgxpool.Pool) fiber.Handler {
 	}
 }
 
+// UpdateMyPhoto - PATCH /volunteers/me/photo (Volunteer)
+// Lets a volunteer set or clear only their own photo_url.
+func UpdateMyPhoto(pool *pgxpool.Pool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
+		volunteerID, err := mw.GetUserIDFromClaims(c)
+		if err != nil {
+			return fiber.NewError(fiber.StatusUnauthorized, "Volunteer ID not found in token")
+		}
+
+		var b models.UpdateMyPhotoRequest
+		if err := c.BodyParser(&b); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "Bad JSON")
+		}
+		var photoURL *string
+		if b.PhotoURL != nil {
+			if photoURL, err = normalizePhotoURL(*b.PhotoURL); err != nil {
+				return err
+			}
+		}
+
+		cmd, err := pool.Exec(ctx, `UPDATE volunteers SET photo_url = $1 WHERE id = $2`, photoURL, volunteerID)
+		if err != nil {
+			return err
+		}
+		if cmd.RowsAffected() == 0 {
+			return fiber.NewError(fiber.StatusNotFound, "Volunteer not found")
+		}
+		return c.JSON(fiber.Map{"id": volunteerID, "photo_url": photoURL})
+	}
+}
+
+// UpdateMySMSOptOut - PATCH /volunteers/me/sms-opt-out  {opt_out} (Volunteer)
+// Opts the volunteer out of (or back into) SMS/WhatsApp notifications such as urgent announcements.
+func UpdateMySMSOptOut(pool *pgxpool.Pool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
+		volunteerID, err := mw.GetUserIDFromClaims(c)
+		if err != nil {
+			return fiber.NewError(fiber.StatusUnauthorized, "Volunteer ID not found in token")
+		}
+
+		var b models.UpdateSMSOptOutRequest
+		if err := c.BodyParser(&b); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "Bad JSON")
+		}
+		cmd, err := pool.Exec(ctx, `UPDATE volunteers SET sms_opt_out = $1 WHERE id = $2`, b.OptOut, volunteerID)
+		if err != nil {
+			return err
+		}
+		if cmd.RowsAffected() == 0 {
+			return fiber.NewError(fiber.StatusNotFound, "Volunteer not found")
+		}
+		return c.JSON(fiber.Map{"id": volunteerID, "sms_opt_out": b.OptOut})
+	}
+}
+
 // GetMyAssignments - GET /volunteers/me/assignments (Volunteer)
 // Lists all assignments for the logged-in volunteer.
 func GetMyAssignments(pool *pgxpool.Pool) fiber.Handler {
 	return func(c *fiber.Ctx) error {
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
 		volunteerID, err := mw.GetUserIDFromClaims(c)
 		if err != nil {
 			return fiber.NewError(fiber.StatusUnauthorized, "Volunteer ID not found in token")
@@ -1119,63 +2146,187 @@ func GetMyAssignments(pool *pgxpool.Pool) fiber.Handler {
 		limit := clampInt(c.QueryInt("limit", 100), 1, 500)
 		offset := maxInt(c.QueryInt("offset", 0), 0)
 
-		rows, err := pool.Query(c.Context(), `
+		out, err := myAssignments(ctx, pool, volunteerID, false, limit, offset)
+		if err != nil {
+			return err
+		}
+		return c.JSON(out)
+	}
+}
+
+// myAssignment is an assignment as the volunteer sees it, with today's check-in state.
+type myAssignment struct {
+	models.VolunteerAssignment
+	ActiveAttendanceID sql.NullInt64 `json:"active_attendance_id,omitempty"`
+	IsCheckedInToday   bool          `json:"is_checked_in_today"`
+}
+
+// myAssignments lists the volunteer's assignments, newest first. todayOnly keeps the non-cancelled
+// ones whose shift starts today in the event's timezone, ordered by start time instead.
+func myAssignments(ctx context.Context, pool *pgxpool.Pool, volunteerID int64, todayOnly bool, limit, offset int) ([]myAssignment, error) {
+	where := `WHERE va.volunteer_id = $1`
+	order := `ORDER BY va.created_at DESC`
+	if todayOnly {
+		where += ` AND va.status <> 'cancelled'
+			  AND (COALESCE(va.start_time, va.reporting_time) AT TIME ZONE e.tz)::date = (NOW() AT TIME ZONE e.tz)::date`
+		order = `ORDER BY COALESCE(va.start_time, va.reporting_time), va.id`
+	}
+	rows, err := pool.Query(ctx, `
+		SELECT `+enrichedAssignmentColumns+`,
+			-- Check for active attendance today (in the event's timezone) for this assignment
+			(SELECT att.id FROM attendance att
+			 WHERE att.assignment_id = va.id AND att.check_out_time IS NULL
+			   AND (att.check_in_time AT TIME ZONE e.tz)::date = (NOW() AT TIME ZONE e.tz)::date
+			 LIMIT 1) AS active_attendance_id
+		`+enrichedAssignmentFrom("volunteer_assignments")+`
+		`+where+`
+		`+order+`
+		LIMIT $2 OFFSET $3
+	`, volunteerID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := []myAssignment{}
+	for rows.Next() {
+		var a myAssignment
+		var activeAttendanceID sql.NullInt64
+		if err := scanEnrichedAssignment(rows, &a.VolunteerAssignment, &activeAttendanceID); err != nil {
+			return nil, err
+		}
+		a.ActiveAttendanceID = activeAttendanceID
+		a.IsCheckedInToday = activeAttendanceID.Valid // If ID is valid, they are checked in today
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
+// GetMySchedule - GET /volunteers/me/schedule?from=YYYY-MM-DD&to=YYYY-MM-DD (Volunteer)
+// Returns the volunteer's shifts grouped by the day they start (in the event's timezone), sorted by start_time.
+// Defaults to the next 7 days; the range is capped at 62 days. Assignments without a start_time are omitted.
+func GetMySchedule(pool *pgxpool.Pool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
+		volunteerID, err := mw.GetUserIDFromClaims(c)
+		if err != nil {
+			return fiber.NewError(fiber.StatusUnauthorized, "Volunteer ID not found in token")
+		}
+
+		from := time.Now().UTC().Truncate(24 * time.Hour)
+		if v := c.Query("from"); v != "" {
+			if from, err = time.Parse("2006-01-02", v); err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "Invalid 'from' date (YYYY-MM-DD)")
+			}
+		}
+		to := from.AddDate(0, 0, 6)
+		if v := c.Query("to"); v != "" {
+			if to, err = time.Parse("2006-01-02", v); err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "Invalid 'to' date (YYYY-MM-DD)")
+			}
+		}
+		if to.Before(from) {
+			return fiber.NewError(fiber.StatusBadRequest, "'to' must not be before 'from'")
+		}
+		if to.Sub(from) > 62*24*time.Hour {
+			return fiber.NewError(fiber.StatusBadRequest, "Date range cannot exceed 62 days")
+		}
+
+		rows, err := pool.Query(ctx, `
 			SELECT
-				va.id, va.event_id, va.committee_id, va.volunteer_id,
-				va.role::text, va.status::text, va.reporting_time, va.shift, va.start_time, va.end_time, va.notes, va.created_at,
-				v.name AS volunteer_name, v.email AS volunteer_email, v.college_id AS volunteer_college_id, -- NEW
-				c.name AS committee_name,
-				e.name AS event_name,
-				-- Check for active attendance today for this assignment
-				(SELECT att.id FROM attendance att WHERE att.assignment_id = va.id AND DATE(att.check_in_time) = CURRENT_DATE AND att.check_out_time IS NULL LIMIT 1) AS active_attendance_id
+				(va.start_time AT TIME ZONE e.tz)::date AS day,
+				va.id, va.event_id, e.name, va.committee_id, c.name,
+				va.shift, va.reporting_time, va.start_time, va.end_time,
+				att.id, att.check_out_time IS NOT NULL
 			FROM volunteer_assignments va
-			JOIN volunteers v ON v.id = va.volunteer_id
 			JOIN committees c ON c.id = va.committee_id
 			JOIN events e ON e.id = va.event_id
+			LEFT JOIN LATERAL (
+				SELECT a.id, a.check_out_time
+				FROM attendance a
+				WHERE a.assignment_id = va.id
+				  AND (a.check_in_time AT TIME ZONE e.tz)::date = (va.start_time AT TIME ZONE e.tz)::date
+				ORDER BY a.check_in_time DESC
+				LIMIT 1
+			) att ON TRUE
 			WHERE va.volunteer_id = $1
-			ORDER BY va.created_at DESC
-			LIMIT $2 OFFSET $3
-		`, volunteerID, limit, offset)
+			  AND va.status <> 'cancelled'
+			  AND va.start_time IS NOT NULL
+			  AND (va.start_time AT TIME ZONE e.tz)::date BETWEEN $2 AND $3
+			ORDER BY day, va.start_time, va.id
+		`, volunteerID, from, to)
 		if err != nil {
 			return err
 		}
 		defer rows.Close()
 
-		type MyAssignment struct { // Extend the base model for specific view
-			models.VolunteerAssignment
-			ActiveAttendanceID sql.NullInt64 `json:"active_attendance_id,omitempty"`
-			IsCheckedInToday   bool          `json:"is_checked_in_today"`
-		}
-		out := []MyAssignment{}
+		days := []models.ScheduleDay{}
 		for rows.Next() {
-			var a MyAssignment
-			var roleStr, statusStr string
-			var activeAttendanceID sql.NullInt64
-			var volunteerEmail, volunteerCollegeID sql.NullString // NEW
-			if err := rows.Scan(
-				&a.ID, &a.EventID, &a.CommitteeID, &a.VolunteerID,
-				&roleStr, &statusStr, &a.ReportingTime, &a.Shift, &a.StartTime, &a.EndTime, &a.Notes, &a.CreatedAt,
-				&a.VolunteerName, &volunteerEmail, &volunteerCollegeID, &a.CommitteeName, &a.EventName, // NEW
-				&activeAttendanceID,
+			var day time.Time
+			var s models.ScheduleShift
+			var attendanceID sql.NullInt64
+			var checkedOut sql.NullBool
+			if err := rows.Scan(&day,
+				&s.AssignmentID, &s.EventID, &s.EventName, &s.CommitteeID, &s.CommitteeName,
+				&s.Shift, &s.ReportingTime, &s.StartTime, &s.EndTime,
+				&attendanceID, &checkedOut,
 			); err != nil {
 				return err
 			}
-			a.Role = models.AssignmentRole(roleStr)
-			a.Status = models.AssignmentStatus(statusStr)
-			a.VolunteerEmail = derefNullString(volunteerEmail)         // NEW
-			a.VolunteerCollegeID = derefNullString(volunteerCollegeID) // NEW
-			a.ActiveAttendanceID = activeAttendanceID
-			a.IsCheckedInToday = activeAttendanceID.Valid // If ID is valid, they are checked in today
-			out = append(out, a)
+			s.CheckInStatus = "not_checked_in"
+			if attendanceID.Valid {
+				s.AttendanceID = &attendanceID.Int64
+				s.CheckInStatus = "checked_in"
+				if checkedOut.Bool {
+					s.CheckInStatus = "checked_out"
+				}
+			}
+
+			date := day.Format("2006-01-02")
+			if n := len(days); n == 0 || days[n-1].Date != date {
+				days = append(days, models.ScheduleDay{Date: date, Shifts: []models.ScheduleShift{}})
+			}
+			days[len(days)-1].Shifts = append(days[len(days)-1].Shifts, s)
 		}
-		return c.JSON(out)
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		return c.JSON(fiber.Map{"from": from.Format("2006-01-02"), "to": to.Format("2006-01-02"), "days": days})
 	}
 }
 
-// GetMyCommittees - GET /volunteers/me/committees (Volunteer)
-// Lists all committees the logged-in volunteer is assigned to.
-func GetMyCommittees(pool *pgxpool.Pool) fiber.Handler {
+// attendanceHistoryColumns is the SELECT list read by scanAttendanceHistoryRow, over attendanceHistoryFrom.
+const attendanceHistoryColumns = `
+	a.id, a.assignment_id, va.event_id, e.name, va.committee_id, c.name, va.shift,
+	a.check_in_time, a.check_out_time,
+	(EXTRACT(EPOCH FROM (a.check_out_time - a.check_in_time)) / 60)::bigint`
+
+// attendanceHistoryFrom joins attendance a to its assignment va, committee c and event e.
+const attendanceHistoryFrom = `
+	FROM attendance a
+	JOIN volunteer_assignments va ON va.id = a.assignment_id
+	JOIN committees c ON c.id = va.committee_id
+	JOIN events e ON e.id = va.event_id`
+
+// scanAttendanceHistoryRow scans a row selected with attendanceHistoryColumns into r; extra receives
+// any columns the query selects after them.
+func scanAttendanceHistoryRow(row pgx.Row, r *models.AttendanceHistoryRow, extra ...any) error {
+	return row.Scan(append([]any{
+		&r.AttendanceID, &r.AssignmentID, &r.EventID, &r.EventName, &r.CommitteeID, &r.CommitteeName, &r.Shift,
+		&r.CheckInTime, &r.CheckOutTime, &r.DurationMinutes,
+	}, extra...)...)
+}
+
+// GetMyAttendance - GET /volunteers/me/attendance?start_date=YYYY-MM-DD&end_date=YYYY-MM-DD&limit=100&offset=0 (Volunteer)
+// The volunteer's own attendance, newest first, grouped by check-in date (event timezone) with the
+// duration of each closed entry. total_hours covers every record matching the date range, not just this page.
+func GetMyAttendance(pool *pgxpool.Pool) fiber.Handler {
 	return func(c *fiber.Ctx) error {
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
 		volunteerID, err := mw.GetUserIDFromClaims(c)
 		if err != nil {
 			return fiber.NewError(fiber.StatusUnauthorized, "Volunteer ID not found in token")
@@ -1184,47 +2335,184 @@ func GetMyCommittees(pool *pgxpool.Pool) fiber.Handler {
 		limit := clampInt(c.QueryInt("limit", 100), 1, 500)
 		offset := maxInt(c.QueryInt("offset", 0), 0)
 
-		rows, err := pool.Query(c.Context(), `
-			SELECT DISTINCT
-				c.id, c.event_id, c.name, COALESCE(c.description,''), c.created_at, e.name as event_name
-			FROM committees c
-			JOIN volunteer_assignments va ON va.committee_id = c.id
-			JOIN events e ON e.id = c.event_id
-			WHERE va.volunteer_id = $1
-			ORDER BY c.name
-			LIMIT $2 OFFSET $3
-		`, volunteerID, limit, offset)
+		where := []string{"va.volunteer_id = $1"}
+		args := []any{volunteerID}
+		for _, f := range []struct{ param, op string }{{"start_date", ">="}, {"end_date", "<="}} {
+			v := c.Query(f.param)
+			if v == "" {
+				continue
+			}
+			d, err := time.Parse("2006-01-02", v)
+			if err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "Invalid "+f.param+" (YYYY-MM-DD)")
+			}
+			args = append(args, d)
+			where = append(where, "(a.check_in_time AT TIME ZONE e.tz)::date "+f.op+" $"+itoa(len(args)))
+		}
+		whereClause := " WHERE " + strings.Join(where, " AND ")
+		from := attendanceHistoryFrom
+
+		var totalRecords int64
+		var totalMinutes float64
+		err = pool.QueryRow(ctx, `
+			SELECT COUNT(*), COALESCE(SUM(EXTRACT(EPOCH FROM (a.check_out_time - a.check_in_time)) / 60), 0)
+		`+from+whereClause, args...).Scan(&totalRecords, &totalMinutes)
+		if err != nil {
+			return err
+		}
+
+		args = append(args, limit, offset)
+		rows, err := pool.Query(ctx, `
+			SELECT `+attendanceHistoryColumns+`, (a.check_in_time AT TIME ZONE e.tz)::date
+		`+from+whereClause+`
+			ORDER BY a.check_in_time DESC
+			LIMIT $`+itoa(len(args)-1)+` OFFSET $`+itoa(len(args)), args...)
 		if err != nil {
 			return err
 		}
 		defer rows.Close()
 
-		out := make([]models.Committee, 0, limit)
+		days := []models.AttendanceDay{}
 		for rows.Next() {
-			var cm models.Committee
-			if err := rows.Scan(&cm.ID, &cm.EventID, &cm.Name, &cm.Description, &cm.CreatedAt, &cm.EventName); err != nil {
+			var day time.Time
+			var r models.AttendanceHistoryRow
+			if err := scanAttendanceHistoryRow(rows, &r, &day); err != nil {
 				return err
 			}
-			out = append(out, cm)
+			date := day.Format("2006-01-02")
+			if n := len(days); n == 0 || days[n-1].Date != date {
+				days = append(days, models.AttendanceDay{Date: date, Entries: []models.AttendanceHistoryRow{}})
+			}
+			d := &days[len(days)-1]
+			d.Entries = append(d.Entries, r)
+			if r.DurationMinutes != nil {
+				d.Hours += float64(*r.DurationMinutes) / 60
+			}
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		for i := range days {
+			days[i].Hours = math.Round(days[i].Hours*100) / 100
+		}
+
+		return c.JSON(fiber.Map{
+			"days":          days,
+			"total_records": totalRecords,
+			"total_hours":   math.Round(totalMinutes/60*100) / 100,
+			"limit":         limit,
+			"offset":        offset,
+		})
+	}
+}
+
+// GetMyCommittees - GET /volunteers/me/committees (Volunteer)
+// Lists all committees the logged-in volunteer is assigned to.
+func GetMyCommittees(pool *pgxpool.Pool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
+		volunteerID, err := mw.GetUserIDFromClaims(c)
+		if err != nil {
+			return fiber.NewError(fiber.StatusUnauthorized, "Volunteer ID not found in token")
+		}
+
+		limit := clampInt(c.QueryInt("limit", 100), 1, 500)
+		offset := maxInt(c.QueryInt("offset", 0), 0)
+
+		out, err := myCommittees(ctx, pool, volunteerID, limit, offset)
+		if err != nil {
+			return err
 		}
 		return c.JSON(out)
 	}
 }
 
+// myCommittees lists the committees the volunteer has an assignment in, by name.
+func myCommittees(ctx context.Context, pool *pgxpool.Pool, volunteerID int64, limit, offset int) ([]models.Committee, error) {
+	rows, err := pool.Query(ctx, `
+		SELECT DISTINCT
+			c.id, c.event_id, c.name, COALESCE(c.description,''), c.display_order, c.created_at, c.updated_at, e.name as event_name
+		FROM committees c
+		JOIN volunteer_assignments va ON va.committee_id = c.id
+		JOIN events e ON e.id = c.event_id
+		WHERE va.volunteer_id = $1
+		ORDER BY c.display_order, c.name
+		LIMIT $2 OFFSET $3
+	`, volunteerID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]models.Committee, 0, limit)
+	for rows.Next() {
+		var cm models.Committee
+		if err := rows.Scan(&cm.ID, &cm.EventID, &cm.Name, &cm.Description, &cm.DisplayOrder, &cm.CreatedAt, &cm.UpdatedAt, &cm.EventName); err != nil {
+			return nil, err
+		}
+		out = append(out, cm)
+	}
+	return out, rows.Err()
+}
+
+// GetMyDashboard - GET /volunteers/me/dashboard (Volunteer)
+// Everything the app's home screen needs in one round-trip: the profile, today's assignments with
+// check-in status, the volunteer's committees and active announcement counts. The individual
+// /me endpoints remain for clients that fetch them separately.
+func GetMyDashboard(pool *pgxpool.Pool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx, cancel := db.WithTimeout(c.Context())
+		defer cancel()
+
+		volunteerID, err := mw.GetUserIDFromClaims(c)
+		if err != nil {
+			return fiber.NewError(fiber.StatusUnauthorized, "Volunteer ID not found in token")
+		}
+
+		profile, err := myProfile(ctx, pool, volunteerID)
+		if err != nil {
+			return err
+		}
+		today, err := myAssignments(ctx, pool, volunteerID, true, 100, 0)
+		if err != nil {
+			return err
+		}
+		committees, err := myCommittees(ctx, pool, volunteerID, 100, 0)
+		if err != nil {
+			return err
+		}
+		active, unacked, err := hAnnouncements.CountActiveForVolunteer(ctx, pool, volunteerID)
+		if err != nil {
+			return err
+		}
+		return c.JSON(fiber.Map{
+			"profile":               profile,
+			"today_assignments":     today,
+			"committees":            committees,
+			"active_announcements":  active,
+			"unacked_announcements": unacked,
+		})
+	}
+}
+
 // assignmentFilters struct for building dynamic queries
 type assignmentFilters struct {
 	EventID     sql.NullInt64
 	CommitteeID sql.NullInt64
 	VolunteerID sql.NullInt64
+	ShiftID     sql.NullInt64
 	Shift       sql.NullString
+	ExactShift  bool
 	StartDate   sql.NullTime
 	EndDate     sql.NullTime
 	Limit       int
 	Offset      int
 }
 
-// buildAssignmentFilters parses query parameters into an assignmentFilters struct
-func buildAssignmentFilters(c *fiber.Ctx) assignmentFilters {
+// buildAssignmentFilters parses query parameters into an assignmentFilters struct; a malformed shift_id is a 400
+func buildAssignmentFilters(c *fiber.Ctx) (assignmentFilters, error) {
 	filters := assignmentFilters{}
 
 	eventIDStr := c.Query("event_id", "")
@@ -1248,11 +2536,20 @@ func buildAssignmentFilters(c *fiber.Ctx) assignmentFilters {
 		}
 	}
 
-	shiftStr := c.Query("shift", "")
-	if shiftStr != "" {
-		filters.Shift = sql.NullString{String: shiftStr, Valid: true}
+	shiftIDStr := c.Query("shift_id", "")
+	if shiftIDStr != "" {
+		id, err := strconv.ParseInt(shiftIDStr, 10, 64)
+		if err != nil || id <= 0 {
+			return filters, fiber.NewError(fiber.StatusBadRequest, "Invalid shift_id query parameter")
+		}
+		filters.ShiftID = sql.NullInt64{Int64: id, Valid: true}
 	}
 
+	if shift := normShift(c.Query("shift", "")); shift != nil {
+		filters.Shift = sql.NullString{String: *shift, Valid: true}
+	}
+	filters.ExactShift = c.QueryBool("exact_shift")
+
 	startDateStr := c.Query("start_date", "")
 	if startDateStr != "" {
 		if t, err := time.Parse("2006-01-02", startDateStr); err == nil {
@@ -1270,7 +2567,7 @@ func buildAssignmentFilters(c *fiber.Ctx) assignmentFilters {
 	filters.Limit = clampInt(c.QueryInt("limit", 100), 1, 500)
 	filters.Offset = maxInt(c.QueryInt("offset", 0), 0)
 
-	return filters
+	return filters, nil
 }
 
 // --- Helpers ---
@@ -1290,12 +2587,74 @@ func get(rec []string, idx map[string]int, key string) string {
 	return rec[i]
 }
 func trim(s string) string { return strings.TrimSpace(s) }
-func defaultIfEmpty(s, def string) string {
-	if s == "" {
-		return def
+
+// normShift trims a shift name and collapses runs of whitespace, so "Morning  Shift " and
+// "Morning Shift" are stored alike; an empty name means no shift (nil).
+func normShift(s string) *string { return nullable(strings.Join(strings.Fields(s), " ")) }
+
+func normShiftPtr(s *string) *string {
+	if s == nil {
+		return nil
 	}
-	return s
+	return normShift(*s)
+}
+
+// canonicalShiftSQL is an SQL expression for the (normShift'ed) shift parameter p, re-spelled to match
+// a shift defined for the event eventExpr, or else one its assignments already use, case-insensitively,
+// so "morning" joins an existing "Morning" instead of becoming a separate filter value. exclude is a
+// condition on alias s that leaves out the row being written, so it can still change its own casing.
+func canonicalShiftSQL(eventExpr, p, exclude string) string {
+	return `COALESCE((SELECT sh.name FROM shifts sh
+		WHERE sh.event_id = ` + eventExpr + ` AND lower(sh.name) = lower(` + p + `)),
+		(SELECT s.shift FROM volunteer_assignments s
+		WHERE s.event_id = ` + eventExpr + ` AND lower(s.shift) = lower(` + p + `) AND ` + exclude + `
+		ORDER BY s.id LIMIT 1), ` + p + `)`
+}
+
+// shiftIDSQL is an SQL expression for the id of the shift of event eventExpr named p (case-insensitively),
+// or NULL when the event doesn't define one, so assignment writes keep shift_id in step with the shift text.
+func shiftIDSQL(eventExpr, p string) string {
+	return `(SELECT sh.id FROM shifts sh WHERE sh.event_id = ` + eventExpr + ` AND lower(sh.name) = lower(` + p + `))`
 }
+
+// keepCancelledSQL is the status an assignment upsert's DO UPDATE sets: the new one, except that a cancelled
+// assignment stays cancelled unless the boolean parameter reinstate is true (as in UpdateAssignment).
+func keepCancelledSQL(reinstate string) string {
+	return `CASE WHEN volunteer_assignments.status = 'cancelled' AND NOT ` + reinstate + `::boolean
+		THEN volunteer_assignments.status ELSE EXCLUDED.status END`
+}
+
+// checkShiftCapacity returns 409 when committeeID's shift already has as many non-cancelled assignments
+// as its shift_capacities limit. volunteerID's own assignment in the committee is not counted, since the
+// upsert replaces it. The capacity row is locked FOR UPDATE so concurrent writes to a shift queue up
+// behind each other instead of both squeezing into the last slot. Shifts without a limit always pass.
+func checkShiftCapacity(ctx context.Context, tx pgx.Tx, committeeID, volunteerID int64, shift *string) error {
+	if shift == nil {
+		return nil
+	}
+	var capacity int
+	err := tx.QueryRow(ctx, `
+		SELECT capacity FROM shift_capacities WHERE committee_id = $1 AND lower(shift) = lower($2) FOR UPDATE
+	`, committeeID, *shift).Scan(&capacity)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var assigned int
+	if err := tx.QueryRow(ctx, `
+		SELECT COUNT(*) FROM volunteer_assignments
+		WHERE committee_id = $1 AND lower(shift) = lower($2) AND status <> 'cancelled' AND volunteer_id <> $3
+	`, committeeID, *shift, volunteerID).Scan(&assigned); err != nil {
+		return err
+	}
+	if assigned >= capacity {
+		return fiber.NewError(fiber.StatusConflict, fmt.Sprintf("shift %q is full (%d/%d); pass ?override=true to assign anyway", *shift, assigned, capacity))
+	}
+	return nil
+}
+
 func nullable(s string) *string {
 	if s == "" {
 		return nil
@@ -1303,6 +2662,39 @@ func nullable(s string) *string {
 	return &s
 }
 
+// bulkUploadMaxBytes is the CSV size cap for BulkUpload (BULK_UPLOAD_MAX_BYTES, default 5 MiB).
+// It should stay below the app-wide BODY_LIMIT_MB.
+func bulkUploadMaxBytes() int64 { return mw.EnvBytes("BULK_UPLOAD_MAX_BYTES", 5<<20) }
+
+// isCSVUpload accepts text/* and the CSV types browsers actually send; a generic
+// application/octet-stream is allowed only when the file name ends in .csv.
+func isCSVUpload(contentType, filename string) bool {
+	mediaType := strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+	switch {
+	case strings.HasPrefix(mediaType, "text/"):
+		return true
+	case mediaType == "application/csv", mediaType == "application/vnd.ms-excel":
+		return true
+	case mediaType == "application/octet-stream" || mediaType == "":
+		return strings.HasSuffix(strings.ToLower(filename), ".csv")
+	}
+	return false
+}
+
+// normalizePhotoURL trims s and checks it is an absolute http(s) URL.
+// An empty string returns nil so callers can clear the photo.
+func normalizePhotoURL(s string) (*string, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+	u, err := url.Parse(s)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "photo_url must be a valid http(s) URL")
+	}
+	return &s, nil
+}
+
 // derefNullString is a helper to convert sql.NullString to *string.
 // Useful for populating models.VolunteerAssignment.VolunteerEmail and .VolunteerCollegeID.
 func derefNullString(s sql.NullString) *string {
@@ -1329,25 +2721,33 @@ func maxInt(a, b int) int {
 }
 func itoa(i int) string { return strconv.FormatInt(int64(i), 10) }
 
-func normAssignmentRole(r string) models.AssignmentRole {
+// normAssignmentRole parses an assignment role; empty means the default (volunteer).
+// Unknown values are a 422 listing the accepted ones rather than silently becoming the default.
+func normAssignmentRole(r string) (models.AssignmentRole, error) {
 	switch strings.ToLower(strings.TrimSpace(r)) {
+	case "", "volunteer":
+		return models.RoleVolunteer, nil
 	case "lead":
-		return models.RoleLead
+		return models.RoleLead, nil
 	case "support":
-		return models.RoleSupport
+		return models.RoleSupport, nil
 	default:
-		return models.RoleVolunteer // Default
+		return "", fiber.NewError(fiber.StatusUnprocessableEntity, fmt.Sprintf("invalid role %q (accepted values: volunteer, lead, support)", r))
 	}
 }
 
-func normAssignmentStatus(s string) models.AssignmentStatus {
+// normAssignmentStatus parses an assignment status; empty means the default (assigned).
+// Unknown values are a 422 listing the accepted ones so a typo can't un-cancel someone.
+func normAssignmentStatus(s string) (models.AssignmentStatus, error) {
 	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "assigned":
+		return models.StatusAssigned, nil
 	case "standby":
-		return models.StatusStandby
+		return models.StatusStandby, nil
 	case "cancelled":
-		return models.StatusCancelled
+		return models.StatusCancelled, nil
 	default:
-		return models.StatusAssigned // Default
+		return "", fiber.NewError(fiber.StatusUnprocessableEntity, fmt.Sprintf("invalid status %q (accepted values: assigned, standby, cancelled)", s))
 	}
 }
 
@@ -0,0 +1,176 @@
+package volunteers
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"Seva-app-backend/db/dbtest"
+	"Seva-app-backend/models"
+)
+
+// testApp mounts the volunteers handlers with the auth guards replaced by claims for user sub.
+func testApp(pool *pgxpool.Pool, sub int64, role models.UserRole) *fiber.App {
+	app := dbtest.App()
+	Register(app.Group("/volunteers"), pool, dbtest.As(sub, role), dbtest.Pass, dbtest.Pass, dbtest.Pass, dbtest.Pass)
+	return app
+}
+
+func TestEmptyListsAreArrays(t *testing.T) {
+	pool := dbtest.Migrated(t)
+	event := strconv.FormatInt(dbtest.ID(t, pool, `INSERT INTO events (name) VALUES ('Empty event') RETURNING id`), 10)
+	app := testApp(pool, dbtest.ID(t, pool, `INSERT INTO volunteers (name) VALUES ('Asha') RETURNING id`), models.UserRoleVolunteer)
+	for _, p := range []string{
+		"/volunteers/assignments?event_id=" + event,
+		"/volunteers/shifts?event_id=" + event,
+		"/volunteers/me/assignments",
+		"/volunteers/me/committees",
+	} {
+		code, body := dbtest.Do(t, app, "GET", p, "")
+		if code != fiber.StatusOK || strings.TrimSpace(string(body)) != "[]" {
+			t.Errorf("GET %s = %d %s, want 200 []", p, code, body)
+		}
+	}
+}
+
+func TestListAssignmentsRejectsBadShiftID(t *testing.T) {
+	app := testApp(nil, 1, models.UserRoleAdmin) // rejected before any query
+	if code, body := dbtest.Do(t, app, "GET", "/volunteers/assignments?shift_id=abc", ""); code != fiber.StatusBadRequest {
+		t.Fatalf("GET /volunteers/assignments?shift_id=abc = %d %s, want 400", code, body)
+	}
+}
+
+func TestCopyAssignmentsResetsStatusAndTimes(t *testing.T) {
+	pool := dbtest.Migrated(t)
+	app := testApp(pool, 1, models.UserRoleAdmin)
+	from := dbtest.ID(t, pool, `INSERT INTO events (name) VALUES ('Last year') RETURNING id`)
+	to := dbtest.ID(t, pool, `INSERT INTO events (name) VALUES ('This year') RETURNING id`)
+	fromCommittee := dbtest.ID(t, pool, `INSERT INTO committees (event_id, name) VALUES ($1, 'Food') RETURNING id`, from)
+	toCommittee := dbtest.ID(t, pool, `INSERT INTO committees (event_id, name) VALUES ($1, 'Food') RETURNING id`, to)
+	volunteer := dbtest.ID(t, pool, `INSERT INTO volunteers (name) VALUES ('Asha') RETURNING id`)
+	dbtest.Exec(t, pool, `
+		INSERT INTO volunteer_assignments (event_id, committee_id, volunteer_id, role, status, reporting_time, start_time, end_time, notes)
+		VALUES ($1, $2, $3, 'lead', 'standby', '2025-01-10 05:30Z', '2025-01-10 06:00Z', '2025-01-10 12:00Z', 'Kitchen keys')`,
+		from, fromCommittee, volunteer)
+
+	body := `{"from_committee_id": ` + strconv.FormatInt(fromCommittee, 10) +
+		`, "to_committee_id": ` + strconv.FormatInt(toCommittee, 10) +
+		`, "to_event_id": ` + strconv.FormatInt(to, 10) + `}`
+	if code, b := dbtest.Do(t, app, "POST", "/volunteers/assignments/copy", body); code != fiber.StatusOK {
+		t.Fatalf("copy = %d %s", code, b)
+	}
+
+	var role, status, notes string
+	var timesNull bool
+	if err := pool.QueryRow(context.Background(), `
+		SELECT role::text, status::text, notes, reporting_time IS NULL AND start_time IS NULL AND end_time IS NULL
+		FROM volunteer_assignments WHERE committee_id = $1`, toCommittee).Scan(&role, &status, &notes, &timesNull); err != nil {
+		t.Fatal(err)
+	}
+	if role != "lead" || notes != "Kitchen keys" {
+		t.Errorf("role, notes = %q, %q; want them copied", role, notes)
+	}
+	if status != "assigned" || !timesNull {
+		t.Errorf("status = %q, times null = %v; want assigned with no times", status, timesNull)
+	}
+}
+
+func TestCreateAssignmentKeepsCancelledUnlessReinstated(t *testing.T) {
+	pool := dbtest.Migrated(t)
+	app := testApp(pool, 1, models.UserRoleAdmin)
+	eventID := dbtest.ID(t, pool, `INSERT INTO events (name) VALUES ('Test event') RETURNING id`)
+	committeeID := dbtest.ID(t, pool, `INSERT INTO committees (event_id, name) VALUES ($1, 'Food') RETURNING id`, eventID)
+	volunteerID := dbtest.ID(t, pool, `INSERT INTO volunteers (name) VALUES ('Asha') RETURNING id`)
+	dbtest.Exec(t, pool, `
+		INSERT INTO volunteer_assignments (event_id, committee_id, volunteer_id, status) VALUES ($1, $2, $3, 'cancelled')`,
+		eventID, committeeID, volunteerID)
+	body := `{"event_id": ` + strconv.FormatInt(eventID, 10) + `, "committee_id": ` + strconv.FormatInt(committeeID, 10) +
+		`, "volunteer_id": ` + strconv.FormatInt(volunteerID, 10) + `, "status": "assigned", "notes": "Back again"}`
+
+	create := func(query string) models.VolunteerAssignment {
+		t.Helper()
+		code, res := dbtest.Do(t, app, "POST", "/volunteers/assignments"+query, body)
+		if code != fiber.StatusCreated {
+			t.Fatalf("POST /volunteers/assignments%s = %d %s", query, code, res)
+		}
+		var a models.VolunteerAssignment
+		if err := json.Unmarshal(res, &a); err != nil {
+			t.Fatal(err)
+		}
+		return a
+	}
+	if a := create(""); a.Status != models.StatusCancelled || a.Notes == nil || *a.Notes != "Back again" {
+		t.Fatalf("without reinstate: status %q, notes %v; want cancelled with the new notes", a.Status, a.Notes)
+	}
+	if a := create("?reinstate=true"); a.Status != models.StatusAssigned {
+		t.Fatalf("with reinstate=true: status %q, want assigned", a.Status)
+	}
+}
+
+func TestMergeRefusesTwoOpenCheckIns(t *testing.T) {
+	pool := dbtest.Migrated(t)
+	app := testApp(pool, 1, models.UserRoleAdmin)
+	eventID := dbtest.ID(t, pool, `INSERT INTO events (name) VALUES ('Test event') RETURNING id`)
+	committeeID := dbtest.ID(t, pool, `INSERT INTO committees (event_id, name) VALUES ($1, 'Food') RETURNING id`, eventID)
+	keep := dbtest.ID(t, pool, `INSERT INTO volunteers (name) VALUES ('Asha') RETURNING id`)
+	merge := dbtest.ID(t, pool, `INSERT INTO volunteers (name) VALUES ('Asha K') RETURNING id`)
+	checkIn := func(volunteerID int64) int64 {
+		t.Helper()
+		assignmentID := dbtest.ID(t, pool, `
+			INSERT INTO volunteer_assignments (event_id, committee_id, volunteer_id) VALUES ($1, $2, $3) RETURNING id`,
+			eventID, committeeID, volunteerID)
+		return dbtest.ID(t, pool, `INSERT INTO attendance (assignment_id, check_in_time) VALUES ($1, NOW()) RETURNING id`, assignmentID)
+	}
+	kept, merged := checkIn(keep), checkIn(merge)
+	body := `{"keep_id": ` + strconv.FormatInt(keep, 10) + `, "merge_id": ` + strconv.FormatInt(merge, 10) + `}`
+
+	code, res := dbtest.Do(t, app, "POST", "/volunteers/merge", body)
+	if code != fiber.StatusConflict {
+		t.Fatalf("merge with two open check-ins = %d %s, want 409", code, res)
+	}
+	var conflict struct {
+		Conflicts []struct {
+			AttendanceID     int64 `json:"attendance_id"`
+			KeptAttendanceID int64 `json:"kept_attendance_id"`
+		} `json:"conflicts"`
+	}
+	if err := json.Unmarshal(res, &conflict); err != nil {
+		t.Fatal(err)
+	}
+	if len(conflict.Conflicts) != 1 || conflict.Conflicts[0].AttendanceID != merged || conflict.Conflicts[0].KeptAttendanceID != kept {
+		t.Fatalf("conflicts = %+v, want [{%d %d}]", conflict.Conflicts, merged, kept)
+	}
+
+	dbtest.Exec(t, pool, `UPDATE attendance SET check_out_time = NOW() WHERE id = $1`, merged)
+	if code, res := dbtest.Do(t, app, "POST", "/volunteers/merge", body); code != fiber.StatusOK {
+		t.Fatalf("merge after check-out = %d %s", code, res)
+	}
+}
+
+func TestListShiftsIsEventScoped(t *testing.T) {
+	pool := dbtest.Migrated(t)
+	ownID := dbtest.ID(t, pool, `INSERT INTO events (name) VALUES ('Own event') RETURNING id`)
+	other := strconv.FormatInt(dbtest.ID(t, pool, `INSERT INTO events (name) VALUES ('Other event') RETURNING id`), 10)
+	faculty := dbtest.ID(t, pool, `INSERT INTO faculty (name, email) VALUES ('Priya', 'priya@example.com') RETURNING id`)
+	dbtest.Exec(t, pool, `INSERT INTO faculty_event_roles (faculty_id, event_id) VALUES ($1, $2)`, faculty, ownID)
+	own := strconv.FormatInt(ownID, 10)
+	app := testApp(pool, faculty, models.UserRoleFaculty)
+
+	for _, r := range []struct {
+		path string
+		want int
+	}{
+		{"/volunteers/shifts", fiber.StatusBadRequest},
+		{"/volunteers/shifts?event_id=" + other, fiber.StatusForbidden},
+		{"/volunteers/shifts?event_id=" + own, fiber.StatusOK},
+	} {
+		if code, body := dbtest.Do(t, app, "GET", r.path, ""); code != r.want {
+			t.Errorf("GET %s = %d %s, want %d", r.path, code, body, r.want)
+		}
+	}
+}
@@ -1,8 +1,11 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
@@ -11,20 +14,30 @@ import (
 	"github.com/joho/godotenv"
 
 	"Seva-app-backend/db"
+	"Seva-app-backend/email"
 	hAnnounce "Seva-app-backend/handlers/announcements"
 	hAttendance "Seva-app-backend/handlers/attendance"
+	hAudit "Seva-app-backend/handlers/audit"
 	hauth "Seva-app-backend/handlers/auth"
 	hCommittees "Seva-app-backend/handlers/committees"
+	hDepartments "Seva-app-backend/handlers/departments"
+	hDocs "Seva-app-backend/handlers/docs"
+	hEvents "Seva-app-backend/handlers/events"
+	hFaculty "Seva-app-backend/handlers/faculty"
 	"Seva-app-backend/handlers/health"
 	hlocations "Seva-app-backend/handlers/locations"
 	hQuestions "Seva-app-backend/handlers/questions"
+	hShifts "Seva-app-backend/handlers/shifts"
 	hVolunteers "Seva-app-backend/handlers/volunteers"
 	mw "Seva-app-backend/middleware"
 	"Seva-app-backend/models"
+	"Seva-app-backend/notify"
+	"Seva-app-backend/reminders"
 )
 
 func main() {
 	_ = godotenv.Load()
+	models.LoadLimitsFromEnv() // MAX_*_LEN text limits
 
 	addr := os.Getenv("API_ADDR")
 	if addr == "" {
@@ -34,8 +47,44 @@ func main() {
 	pool := db.MustPool()
 	defer pool.Close()
 
-	app := fiber.New()
+	// Apply pending schema migrations (db/migrations; DB_AUTO_MIGRATE=false to skip)
+	if db.AutoMigrateEnabled() {
+		if err := db.Migrate(context.Background(), pool); err != nil {
+			log.Fatalf("Database migration failed: %v", err)
+		}
+	}
+
+	// Seed the first admin from BOOTSTRAP_ADMIN_* on a fresh database (no-op once an admin exists)
+	if err := hauth.BootstrapAdmin(context.Background(), pool); err != nil {
+		log.Printf("admin bootstrap skipped: %v", err)
+	}
+
+	// Outbound notifications (NOTIFY_PROVIDER selects the provider; no-op by default)
+	notifier := notify.NewDispatcher(notify.FromEnv(), notify.WorkersFromEnv(4), 1000)
+
+	// Outbound email (EMAIL_PROVIDER=smtp|log; disabled by default)
+	mailer := email.FromEnv()
+
+	// Pre-shift reminders (REMINDER_LEAD_MINUTES / REMINDER_INTERVAL_SECONDS; REMINDER_LEAD_MINUTES=0 disables)
+	reminderCfg := reminders.ConfigFromEnv()
+	go reminders.Run(context.Background(), pool, notifier, reminderCfg)
+	// Scheduled announcements are pushed when publish_at passes, polled on the same interval
+	go hAnnounce.RunScheduled(context.Background(), pool, notifier, reminderCfg.Interval)
+
+	// Request body cap (BODY_LIMIT_MB, default 8); Fiber rejects larger bodies with 413
+	bodyLimitMB := 8
+	if v, err := strconv.Atoi(os.Getenv("BODY_LIMIT_MB")); err == nil && v > 0 {
+		bodyLimitMB = v
+	}
+
+	appConfig := fiber.Config{
+		ErrorHandler: mw.ErrorHandler, // Logs real errors, returns sanitized messages to clients
+		BodyLimit:    bodyLimitMB * 1024 * 1024,
+	}
+	mw.TrustProxiesFromEnv(&appConfig) // TRUSTED_PROXIES / PROXY_HEADER: real client IPs behind a load balancer
+	app := fiber.New(appConfig)
 	app.Use(recover.New())
+	app.Use(mw.SecurityHeaders()) // HSTS_MAX_AGE=0 disables HSTS for local HTTP
 	app.Use(logger.New())
 	// Optional: Add the custom routing debug middleware again to confirm the fix
 	app.Use(func(c *fiber.Ctx) error {
@@ -44,21 +93,60 @@ func main() {
 	})
 	app.Use(cors.New(cors.Config{
 		AllowOrigins: "*",
-		AllowHeaders: "Origin, Content-Type, Accept, Authorization",
+		AllowHeaders: "Origin, Content-Type, Accept, Authorization, Idempotency-Key",
 		AllowMethods: "GET,POST,HEAD,PUT,DELETE,PATCH",
 	}))
 
 	app.Get("/healthz", health.Health())
 
+	// --- API docs (regenerate with `go generate ./handlers/docs`) ---
+	app.Get("/openapi.json", hDocs.Spec())
+	app.Get("/swagger", hDocs.UI())
+	app.Get("/swagger/:file", hDocs.Asset())
+
 	// JWT Guards and Role Requirements
-	jwtGuard := mw.JwtGuard()
+	jwtGuard := mw.JwtGuard(pool)
 	requireAdmin := mw.RequireRole(string(models.UserRoleAdmin))
 	requireFaculty := mw.RequireRole(string(models.UserRoleFaculty), string(models.UserRoleAdmin))
 	requireVolunteer := mw.RequireRole(string(models.UserRoleVolunteer), string(models.UserRoleAdmin))
+	idempotent := mw.Idempotency(pool)                            // Replays stored responses for retried POSTs with an Idempotency-Key
+	eventScope := mw.RequireEventScope(pool, mw.EventIDFromQuery) // Limits event-scoped faculty to their events (admins bypass)
+	// CSV/GeoJSON imports are held to BULK_UPLOAD_MAX_BYTES (default 5 MiB) rather than the whole BODY_LIMIT_MB
+	bulkUploadLimit := mw.MaxBodySize(mw.EnvBytes("BULK_UPLOAD_MAX_BYTES", 5<<20) + mw.MultipartOverhead)
+
+	// Rate limits (override with RATE_LIMIT_AUTH / RATE_LIMIT_AVAILABILITY / RATE_LIMIT_PUBLIC, e.g. "10/1m" or "off"),
+	// applied per route: authLimiter to the unauthenticated credential endpoints, availabilityLimiter to the
+	// registration form's as-you-type availability check (so it can't use up the login budget), publicLimiter to
+	// anonymous reads. Authenticated routes (refresh, logout, admin writes) are not throttled by any of them.
+	// nil = Fiber's in-memory store; swap in a shared fiber.Storage (e.g. Redis) when running multiple instances.
+	var rateLimitStore fiber.Storage
+	authLimiter := mw.RateLimit("auth", mw.RateLimitConfig{Max: 10, Window: time.Minute}, rateLimitStore)
+	availabilityLimiter := mw.RateLimit("availability", mw.RateLimitConfig{Max: 30, Window: time.Minute}, rateLimitStore)
+	publicLimiter := mw.RateLimit("public", mw.RateLimitConfig{Max: 60, Window: time.Minute}, rateLimitStore)
 
 	// --- Auth routes ---
 	authGroup := app.Group("/auth")
-	hauth.Register(authGroup, pool, jwtGuard, requireAdmin)
+	hauth.Register(authGroup, pool, jwtGuard, requireAdmin, authLimiter, availabilityLimiter, mailer)
+
+	// --- Faculty (admin-only account management) ---
+	fac := app.Group("/faculty")
+	hFaculty.Register(fac, pool, jwtGuard, requireAdmin)
+
+	// --- Audit log (admin-only) ---
+	aud := app.Group("/audit")
+	hAudit.Register(aud, pool, jwtGuard, requireAdmin)
+
+	// --- Departments ---
+	dep := app.Group("/departments")
+	hDepartments.Register(dep, pool, jwtGuard, requireAdmin)
+
+	// --- Events ---
+	evt := app.Group("/events")
+	hEvents.Register(evt, pool, jwtGuard, requireFaculty)
+
+	// --- Shifts ---
+	shf := app.Group("/shifts")
+	hShifts.Register(shf, pool, jwtGuard, requireFaculty, requireAdmin)
 
 	// --- Committees ---
 	comm := app.Group("/committees")
@@ -66,21 +154,32 @@ func main() {
 	comm.Get("/:id", hCommittees.Get(pool))
 	comm.Post("/", jwtGuard, requireAdmin, hCommittees.Create(pool))
 	comm.Put("/:id", jwtGuard, requireAdmin, hCommittees.Update(pool))
+	comm.Patch("/:id/move", jwtGuard, requireAdmin, hCommittees.Move(pool))
 	comm.Delete("/:id", jwtGuard, requireAdmin, hCommittees.Del(pool))
+	comm.Get("/:id/capacities", jwtGuard, requireAdmin, hCommittees.ListCapacities(pool))
+	comm.Put("/:id/capacities", jwtGuard, requireAdmin, hCommittees.SetCapacity(pool))
+	comm.Get("/:id/assignment-stats", jwtGuard, requireAdmin, hCommittees.AssignmentStats(pool))
 
 	// --- Volunteers ---
 	vol := app.Group("/volunteers")
 	// IMPORTANT: Define more specific static routes BEFORE general parameter routes
 	// Admin-only Bulk Operations (static paths)
-	vol.Post("/bulk", jwtGuard, requireAdmin, hVolunteers.BulkUpload(pool))
+	vol.Post("/bulk", jwtGuard, requireAdmin, bulkUploadLimit, hVolunteers.BulkUpload(pool))
+	vol.Get("/bulk/template.csv", jwtGuard, requireAdmin, hVolunteers.BulkUploadTemplate())
+	vol.Post("/batch-delete", jwtGuard, requireAdmin, hVolunteers.BatchDeleteVolunteers(pool))
+	vol.Post("/merge", jwtGuard, requireAdmin, hVolunteers.MergeVolunteers(pool))
 	vol.Get("/export_csv", jwtGuard, requireAdmin, hVolunteers.ExportVolunteersCSV(pool))
 	vol.Get("/assignments/export_csv", jwtGuard, requireAdmin, hVolunteers.ExportAssignmentsCSV(pool))
+	vol.Get("/shifts", jwtGuard, requireFaculty, eventScope, hVolunteers.ListShifts(pool))
+	vol.Get("/unassigned", jwtGuard, requireAdmin, hVolunteers.ListUnassignedVolunteers(pool))
 
 	// Admin-only Assignment Management (static paths, then parameter paths)
-	vol.Post("/assignments", jwtGuard, requireAdmin, hVolunteers.CreateAssignment(pool))
+	vol.Post("/assignments", jwtGuard, requireAdmin, idempotent, hVolunteers.CreateAssignment(pool))
+	vol.Post("/assignments/copy", jwtGuard, requireAdmin, hVolunteers.CopyAssignments(pool))
 	vol.Get("/assignments", jwtGuard, requireAdmin, hVolunteers.ListAssignments(pool))       // This must be BEFORE /:id
 	vol.Get("/assignments/:id", jwtGuard, requireAdmin, hVolunteers.GetAssignmentByID(pool)) // This is specific for /assignments/N
 	vol.Put("/assignments/:id", jwtGuard, requireAdmin, hVolunteers.UpdateAssignment(pool))
+	vol.Patch("/assignments/:id/reassign", jwtGuard, requireAdmin, hVolunteers.ReassignAssignment(pool))
 	vol.Delete("/assignments/:id", jwtGuard, requireAdmin, hVolunteers.DeleteAssignment(pool))
 
 	// General volunteer management (static path for list, then parameter for ID)
@@ -90,39 +189,53 @@ func main() {
 	// Volunteer specific "me" routes (static paths)
 	vol.Get("/me", jwtGuard, requireVolunteer, hVolunteers.GetMyProfile(pool))
 	vol.Post("/me/set-password", jwtGuard, requireVolunteer, hVolunteers.SetMyPassword(pool))
+	vol.Patch("/me/photo", jwtGuard, requireVolunteer, hVolunteers.UpdateMyPhoto(pool))
+	vol.Patch("/me/sms-opt-out", jwtGuard, requireVolunteer, hVolunteers.UpdateMySMSOptOut(pool))
 	vol.Get("/me/assignments", jwtGuard, requireVolunteer, hVolunteers.GetMyAssignments(pool))
+	vol.Get("/me/schedule", jwtGuard, requireVolunteer, hVolunteers.GetMySchedule(pool))
+	vol.Get("/me/attendance", jwtGuard, requireVolunteer, hVolunteers.GetMyAttendance(pool))
 	vol.Get("/me/committees", jwtGuard, requireVolunteer, hVolunteers.GetMyCommittees(pool))
+	vol.Get("/me/dashboard", jwtGuard, requireVolunteer, hVolunteers.GetMyDashboard(pool))
 
 	// FINALLY, the general /:id route for volunteers
 	// This must come AFTER all other static paths like /assignments, /me, /bulk etc.
 	vol.Get("/:id", jwtGuard, requireAdmin, hVolunteers.GetVolunteerByID(pool))
+	vol.Get("/:id/full", jwtGuard, requireAdmin, hVolunteers.GetVolunteerFull(pool))
 	vol.Put("/:id", jwtGuard, requireAdmin, hVolunteers.UpdateVolunteer(pool))
 	vol.Delete("/:id", jwtGuard, requireAdmin, hVolunteers.DeleteVolunteer(pool))
 
 	// --- Attendance ---
 	att := app.Group("/attendance")
-	hAttendance.Register(att, pool, jwtGuard, requireFaculty, requireVolunteer)
+	hAttendance.Register(att, pool, jwtGuard, requireFaculty, requireVolunteer, idempotent)
 
 	// --- Announcements ---
 	ann := app.Group("/announcements")
-	ann.Post("/", jwtGuard, requireAdmin, hAnnounce.Create(pool))
+	ann.Post("/", jwtGuard, requireAdmin, hAnnounce.Create(pool, notifier))
 	ann.Put("/:id", jwtGuard, requireAdmin, hAnnounce.Update(pool))
 	ann.Delete("/:id", jwtGuard, requireAdmin, hAnnounce.Del(pool))
-	ann.Get("/", jwtGuard, requireFaculty, hAnnounce.ListAll(pool))
-	ann.Get("/:id", jwtGuard, requireFaculty, hAnnounce.Get(pool))
+	ann.Get("/", jwtGuard, requireFaculty, eventScope, hAnnounce.ListAll(pool))
+	ann.Get("/:id", jwtGuard, requireFaculty, mw.RequireEventScope(pool, hAnnounce.EventOfAnnouncement(pool)), hAnnounce.Get(pool))
 	ann.Get("/me", jwtGuard, requireVolunteer, hAnnounce.ListForVolunteer(pool))
+	ann.Get("/me/stream", jwtGuard, requireVolunteer, hAnnounce.StreamForVolunteer(notifier))
+	ann.Post("/:id/ack", jwtGuard, requireVolunteer, hAnnounce.Ack(pool))
+	ann.Get("/:id/acks", jwtGuard, requireAdmin, hAnnounce.ListAcks(pool))
 
 	// --- Locations ---
 	loc := app.Group("/locations")
 	loc.Post("/", jwtGuard, requireAdmin, hlocations.CreateLocation(pool))
+	loc.Post("/bulk", jwtGuard, requireAdmin, bulkUploadLimit, hlocations.BulkImportLocations(pool))
 	loc.Put("/:id", jwtGuard, requireAdmin, hlocations.UpdateLocation(pool))
 	loc.Delete("/:id", jwtGuard, requireAdmin, hlocations.DeleteLocation(pool))
-	loc.Get("/", hlocations.ListLocations(pool))
-	loc.Get("/:id", hlocations.GetLocationByID(pool))
+	loc.Get("/", publicLimiter, hlocations.ListLocations(pool))
+	loc.Get("/nearest", publicLimiter, hlocations.NearestLocations(pool)) // Before /:id
+	loc.Get("/geojson", publicLimiter, hlocations.ExportGeoJSON(pool))
+	loc.Get("/types", publicLimiter, hlocations.ListTypes())
+	loc.Get("/bulk/template.csv", jwtGuard, requireAdmin, hlocations.BulkImportTemplate())
+	loc.Get("/:id", publicLimiter, hlocations.GetLocationByID(pool))
 
 	// --- Questions (May I Help You) ---
 	qa := app.Group("/questions")
-	hQuestions.Register(qa, pool, jwtGuard, requireAdmin, requireVolunteer)
+	hQuestions.Register(qa, pool, jwtGuard, requireAdmin, requireFaculty, requireVolunteer, publicLimiter, notifier, mailer)
 
 	log.Printf("listening on %s", addr)
 	log.Fatal(app.Listen(addr))
@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"Seva-app-backend/models"
+)
+
+// blocklistSweepInterval is how often expired entries are deleted from revoked_access_tokens.
+const blocklistSweepInterval = time.Hour
+
+// AccessTokenBlocklistEnabled reports whether admin access tokens carry a jti that logout can revoke.
+// ACCESS_TOKEN_BLOCKLIST defaults to off; set it to "true" (or "1"/"on") to enable. Only admin tokens
+// get a jti, so volunteers and faculty never pay for the extra lookup in JwtGuard.
+func AccessTokenBlocklistEnabled() bool {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv("ACCESS_TOKEN_BLOCKLIST"))) {
+	case "true", "1", "on", "yes":
+		return true
+	}
+	return false
+}
+
+// revocableRole reports whether access tokens for role get a jti (and so can be blocklisted).
+func revocableRole(role models.UserRole) bool {
+	return role == models.UserRoleAdmin
+}
+
+// newJTI returns a random token ID.
+func newJTI() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// RevokeAccessToken blocklists the access token described by cls until it would have expired anyway.
+// Tokens without a jti (blocklist disabled, or a non-admin role) are left alone.
+func RevokeAccessToken(ctx context.Context, pool *pgxpool.Pool, cls *Claims) error {
+	if cls == nil || cls.ID == "" || cls.ExpiresAt == nil {
+		return nil
+	}
+	_, err := pool.Exec(ctx, `
+		INSERT INTO revoked_access_tokens(jti, expires_at) VALUES ($1, $2)
+		ON CONFLICT (jti) DO NOTHING
+	`, cls.ID, cls.ExpiresAt.Time)
+	return err
+}
+
+// accessTokenRevoked reports whether jti has been blocklisted.
+func accessTokenRevoked(ctx context.Context, pool *pgxpool.Pool, jti string) (bool, error) {
+	var revoked bool
+	err := pool.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM revoked_access_tokens WHERE jti = $1)`, jti).Scan(&revoked)
+	return revoked, err
+}
+
+// sweepRevokedAccessTokens periodically deletes blocklist entries whose token has expired,
+// so the table never holds more than one access-token lifetime of logouts.
+func sweepRevokedAccessTokens(pool *pgxpool.Pool) {
+	t := time.NewTicker(blocklistSweepInterval)
+	defer t.Stop()
+	for range t.C {
+		if _, err := pool.Exec(context.Background(), `DELETE FROM revoked_access_tokens WHERE expires_at < NOW()`); err != nil {
+			log.Printf("Access token blocklist sweep failed: %v", err)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// MultipartOverhead is the slack to add on top of a file-size limit when sizing MaxBodySize for a
+// multipart route: boundaries, part headers and the other form fields around the file.
+const MultipartOverhead = 64 << 10
+
+// MaxBodySize rejects requests whose body is larger than max bytes with 413, so an upload route can
+// be held well below the app-wide BODY_LIMIT_MB before its multipart form is parsed. Handlers should
+// still check the file's own size (formFile.Size) against their limit.
+func MaxBodySize(max int64) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if int64(c.Request().Header.ContentLength()) > max || int64(len(c.Request().Body())) > max {
+			return fiber.NewError(fiber.StatusRequestEntityTooLarge, fmt.Sprintf("request body exceeds the %d byte limit for this endpoint", max))
+		}
+		return c.Next()
+	}
+}
+
+// EnvBytes reads a positive byte count from the environment variable name, or returns def.
+func EnvBytes(name string, def int64) int64 {
+	v := os.Getenv(name)
+	if v == "" {
+		return def
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || n <= 0 {
+		log.Printf("Ignoring invalid %s=%q (want a positive byte count); using %d", name, v, def)
+		return def
+	}
+	return n
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"errors"
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+
+	"Seva-app-backend/db"
+	"Seva-app-backend/models"
+	"Seva-app-backend/validation"
+)
+
+// ErrorHandler is the app-wide Fiber error handler.
+// Intentional *fiber.Error values (4xx messages built with fiber.NewError) are passed through
+// unchanged. Anything else is logged with its full detail and the client only sees a
+// sanitized message, so SQL fragments and constraint names never leave the server.
+// Common Postgres integrity errors are mapped to friendly 409/422 responses, using the
+// per-constraint message from db.ConstraintMessage when one is registered.
+// A *validation.ValidationError becomes a 422 JSON body listing the offending fields.
+func ErrorHandler(c *fiber.Ctx, err error) error {
+	code := fiber.StatusInternalServerError
+	msg := "Internal server error"
+
+	var ve *validation.ValidationError
+	var fe *fiber.Error
+	if errors.As(err, &ve) {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(models.ValidationErrorResponse{
+			Error:  "validation_failed",
+			Fields: ve.Fields,
+		})
+	} else if errors.As(err, &fe) {
+		code = fe.Code
+		msg = fe.Message
+	} else if pgErr, ok := db.AsPgError(err); ok {
+		switch pgErr.Code {
+		case db.CodeUniqueViolation:
+			code, msg = fiber.StatusConflict, "Resource already exists"
+		case db.CodeForeignKeyViolation:
+			code, msg = fiber.StatusUnprocessableEntity, "Referenced record does not exist or is still in use"
+		case db.CodeCheckViolation:
+			code, msg = fiber.StatusUnprocessableEntity, "Value is out of the allowed range"
+		}
+		if code != fiber.StatusInternalServerError {
+			if friendly, ok := db.ConstraintMessage(err); ok {
+				msg = friendly
+			}
+		}
+		log.Printf("DB error on %s %s: %v (sqlstate=%s constraint=%s)", c.Method(), c.Path(), err, pgErr.Code, pgErr.ConstraintName)
+	} else {
+		log.Printf("Unhandled error on %s %s: %v", c.Method(), c.Path(), err)
+	}
+
+	c.Set(fiber.HeaderContentType, fiber.MIMETextPlainCharsetUTF8)
+	return c.Status(code).SendString(msg)
+}
@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"Seva-app-backend/models"
+)
+
+// EventIDResolver extracts the event a request targets. ok is false when the request
+// doesn't name an event (e.g. a list without ?event_id=).
+type EventIDResolver func(c *fiber.Ctx) (eventID int64, ok bool, err error)
+
+// EventIDFromQuery resolves the event from the event_id query parameter.
+func EventIDFromQuery(c *fiber.Ctx) (int64, bool, error) {
+	v := c.Query("event_id", "")
+	if v == "" {
+		return 0, false, nil
+	}
+	id, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, false, fiber.NewError(fiber.StatusBadRequest, "invalid event_id")
+	}
+	return id, true, nil
+}
+
+// RequireEventScope restricts faculty to the events they were granted in faculty_event_roles.
+// It must run after JwtGuard. Admins always pass, and so does faculty with no grants at all
+// (unscoped accounts keep global access). Scoped faculty must name an event they hold, otherwise
+// the request is rejected: 400 when no event is given, 403 when it's outside their scope.
+func RequireEventScope(pool *pgxpool.Pool, resolve EventIDResolver) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		cls, ok := c.Locals("claims").(*Claims)
+		if !ok || cls == nil {
+			return fiber.NewError(fiber.StatusUnauthorized, "user claims not found")
+		}
+		if cls.Role != models.UserRoleFaculty {
+			return c.Next()
+		}
+
+		scoped, err := FacultyEventIDs(c.Context(), pool, cls.Sub)
+		if err != nil {
+			return err
+		}
+		if len(scoped) == 0 {
+			return c.Next()
+		}
+
+		eventID, ok, err := resolve(c)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fiber.NewError(fiber.StatusBadRequest, "event_id is required for event-scoped faculty")
+		}
+		for _, id := range scoped {
+			if id == eventID {
+				return c.Next()
+			}
+		}
+		return fiber.NewError(fiber.StatusForbidden, "Not assigned to this event")
+	}
+}
+
+// FacultyEventIDs lists the events a faculty member is scoped to (empty = unscoped).
+func FacultyEventIDs(ctx context.Context, pool *pgxpool.Pool, facultyID int64) ([]int64, error) {
+	rows, err := pool.Query(ctx, `SELECT event_id FROM faculty_event_roles WHERE faculty_id = $1`, facultyID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []int64{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
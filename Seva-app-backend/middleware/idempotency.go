@@ -0,0 +1,143 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"errors"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// IdempotencyHeader is the request header clients use to make a POST safe to retry.
+const IdempotencyHeader = "Idempotency-Key"
+
+const defaultIdempotencyTTL = 24 * time.Hour
+
+// Idempotency returns a middleware that replays the stored response when a request is
+// retried with the same Idempotency-Key header. It must run after JwtGuard: keys are
+// scoped per user (role + id), so two users can never see each other's responses.
+//
+// Requests without the header pass through untouched. The first request with a key claims
+// it, runs the handler, and stores the status and body (5xx and handler errors release the
+// claim so the client can retry). A concurrent repeat gets 409; reusing a key with a
+// different payload gets 422. Keys expire after IDEMPOTENCY_TTL (Go duration, default 24h).
+func Idempotency(pool *pgxpool.Pool) fiber.Handler {
+	ttl := defaultIdempotencyTTL
+	if v := os.Getenv("IDEMPOTENCY_TTL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			ttl = d
+		} else {
+			log.Printf("Invalid IDEMPOTENCY_TTL %q, using %s", v, ttl)
+		}
+	}
+	go sweepIdempotencyKeys(pool, ttl)
+
+	return func(c *fiber.Ctx) error {
+		key := c.Get(IdempotencyHeader)
+		if key == "" {
+			return c.Next()
+		}
+		if len(key) > 255 {
+			return fiber.NewError(fiber.StatusBadRequest, "Idempotency-Key must be at most 255 characters")
+		}
+		cls, ok := c.Locals("claims").(*Claims)
+		if !ok || cls == nil {
+			return fiber.NewError(fiber.StatusUnauthorized, "Authentication required")
+		}
+		scope := string(cls.Role) + ":" + strconv.FormatInt(cls.Sub, 10)
+
+		h := sha256.New()
+		h.Write([]byte(c.Method() + " " + c.Path() + "\n"))
+		h.Write(c.Body())
+		reqHash := h.Sum(nil)
+
+		ctx := c.Context()
+		// Drop an expired entry for this key, then try to claim it.
+		if _, err := pool.Exec(ctx, `DELETE FROM idempotency_keys WHERE user_scope=$1 AND idem_key=$2 AND expires_at < NOW()`, scope, key); err != nil {
+			return err
+		}
+		cmd, err := pool.Exec(ctx, `
+			INSERT INTO idempotency_keys(user_scope, idem_key, request_hash, expires_at)
+			VALUES ($1, $2, $3, NOW() + make_interval(secs => $4))
+			ON CONFLICT (user_scope, idem_key) DO NOTHING
+		`, scope, key, reqHash, ttl.Seconds())
+		if err != nil {
+			return err
+		}
+
+		if cmd.RowsAffected() == 0 {
+			// Key already claimed: replay, or report why we can't.
+			var storedHash, body []byte
+			var status sql.NullInt32
+			var contentType sql.NullString
+			err := pool.QueryRow(ctx, `
+				SELECT request_hash, status_code, content_type, response_body
+				FROM idempotency_keys WHERE user_scope=$1 AND idem_key=$2
+			`, scope, key).Scan(&storedHash, &status, &contentType, &body)
+			if err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					return fiber.NewError(fiber.StatusConflict, "A request with this Idempotency-Key was just released; please retry")
+				}
+				return err
+			}
+			if !bytes.Equal(storedHash, reqHash) {
+				return fiber.NewError(fiber.StatusUnprocessableEntity, "Idempotency-Key was already used for a different request")
+			}
+			if !status.Valid {
+				return fiber.NewError(fiber.StatusConflict, "A request with this Idempotency-Key is still being processed")
+			}
+			if contentType.Valid {
+				c.Set(fiber.HeaderContentType, contentType.String)
+			}
+			c.Set("Idempotent-Replayed", "true")
+			return c.Status(int(status.Int32)).Send(body)
+		}
+
+		release := func() {
+			if _, err := pool.Exec(context.Background(), `DELETE FROM idempotency_keys WHERE user_scope=$1 AND idem_key=$2`, scope, key); err != nil {
+				log.Printf("Failed to release idempotency key for %s: %v", scope, err)
+			}
+		}
+
+		if err := c.Next(); err != nil {
+			release()
+			return err
+		}
+		status := c.Response().StatusCode()
+		if status >= fiber.StatusInternalServerError {
+			release()
+			return nil
+		}
+		if _, err := pool.Exec(ctx, `
+			UPDATE idempotency_keys SET status_code=$3, content_type=$4, response_body=$5
+			WHERE user_scope=$1 AND idem_key=$2
+		`, scope, key, status, string(c.Response().Header.ContentType()), c.Response().Body()); err != nil {
+			// The handler already succeeded; don't fail the request, just lose replay for this key.
+			log.Printf("Failed to store idempotent response for %s: %v", scope, err)
+			release()
+		}
+		return nil
+	}
+}
+
+// sweepIdempotencyKeys periodically deletes expired keys so the table stays small.
+func sweepIdempotencyKeys(pool *pgxpool.Pool, ttl time.Duration) {
+	interval := ttl
+	if interval > time.Hour {
+		interval = time.Hour
+	}
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for range t.C {
+		if _, err := pool.Exec(context.Background(), `DELETE FROM idempotency_keys WHERE expires_at < NOW()`); err != nil {
+			log.Printf("Idempotency key sweep failed: %v", err)
+		}
+	}
+}
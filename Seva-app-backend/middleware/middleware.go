@@ -8,6 +8,7 @@ import (
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/jackc/pgx/v5/pgxpool"
 
 	"Seva-app-backend/models" // Import models package
 )
@@ -20,13 +21,18 @@ type Claims struct {
 }
 
 // JwtGuard is a middleware to validate JWT access tokens.
-func JwtGuard() fiber.Handler {
+// When ACCESS_TOKEN_BLOCKLIST is on, tokens carrying a jti are also checked against revoked_access_tokens.
+func JwtGuard(pool *pgxpool.Pool) fiber.Handler {
 	secret := os.Getenv("JWT_SECRET")
 	if secret == "" {
 		return func(c *fiber.Ctx) error {
 			return fiber.NewError(fiber.StatusInternalServerError, "JWT_SECRET not configured")
 		}
 	}
+	blocklist := AccessTokenBlocklistEnabled()
+	if blocklist {
+		go sweepRevokedAccessTokens(pool)
+	}
 
 	return func(c *fiber.Ctx) error {
 		h := c.Get("Authorization")
@@ -40,7 +46,17 @@ func JwtGuard() fiber.Handler {
 		if err != nil || !tkn.Valid {
 			return fiber.NewError(fiber.StatusUnauthorized, "Invalid token: "+err.Error())
 		}
-		c.Locals("claims", tkn.Claims.(*Claims)) // Store claims in context for downstream handlers
+		cls := tkn.Claims.(*Claims)
+		if blocklist && cls.ID != "" {
+			revoked, err := accessTokenRevoked(c.Context(), pool, cls.ID)
+			if err != nil {
+				return err
+			}
+			if revoked {
+				return fiber.NewError(fiber.StatusUnauthorized, "Token has been revoked")
+			}
+		}
+		c.Locals("claims", cls) // Store claims in context for downstream handlers
 		return c.Next()
 	}
 }
@@ -64,7 +80,7 @@ func RequireRole(roles ...string) fiber.Handler {
 	}
 }
 
-// BuildAccessToken Helper to build JWT access tokens.
+// BuildAccessToken Helper to build JWT access tokens. Admin tokens get a jti when the blocklist is enabled.
 func BuildAccessToken(sub int64, role models.UserRole, ttl time.Duration) (string, error) { // Use models.UserRole
 	secret := os.Getenv("JWT_SECRET")
 	if secret == "" {
@@ -80,6 +96,13 @@ func BuildAccessToken(sub int64, role models.UserRole, ttl time.Duration) (strin
 			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
 		},
 	}
+	if revocableRole(role) && AccessTokenBlocklistEnabled() {
+		jti, err := newJTI()
+		if err != nil {
+			return "", err
+		}
+		claims.ID = jti
+	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString([]byte(secret))
 }
@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/limiter"
+)
+
+// RateLimitConfig is a request budget: at most Max requests per Window.
+type RateLimitConfig struct {
+	Max    int
+	Window time.Duration
+}
+
+// RateLimit returns a sliding-window limiter for a route group.
+//
+// The budget can be overridden with RATE_LIMIT_<GROUP> (e.g. RATE_LIMIT_AUTH=10/1m), or
+// disabled with RATE_LIMIT_<GROUP>=off. Requests are keyed by client IP (see
+// TrustProxiesFromEnv); the limiters run ahead of JwtGuard, so there is no user to key by.
+// Over-limit requests get 429 with a Retry-After header. store is any fiber.Storage (nil
+// uses Fiber's in-memory store), so a shared backend such as Redis can be plugged in when
+// running several instances.
+func RateLimit(group string, def RateLimitConfig, store fiber.Storage) fiber.Handler {
+	cfg, enabled := rateLimitFromEnv(group, def)
+	if !enabled {
+		return func(c *fiber.Ctx) error { return c.Next() }
+	}
+
+	return limiter.New(limiter.Config{
+		Max:               cfg.Max,
+		Expiration:        cfg.Window,
+		Storage:           store,
+		LimiterMiddleware: limiter.SlidingWindow{},
+		KeyGenerator: func(c *fiber.Ctx) string {
+			// Prefix with the group so groups sharing a store keep separate budgets.
+			return group + ":ip:" + c.IP()
+		},
+		LimitReached: func(c *fiber.Ctx) error {
+			return fiber.NewError(fiber.StatusTooManyRequests, "Too many requests, please try again later")
+		},
+	})
+}
+
+// TrustProxiesFromEnv makes c.IP() (and so the per-IP rate limit keys) honor a proxy header, but only for
+// requests arriving from TRUSTED_PROXIES, a comma-separated list of IPs or CIDRs (e.g. "10.0.0.0/8,127.0.0.1").
+// The header is PROXY_HEADER, default X-Forwarded-For; the proxy must overwrite it rather than append to a
+// client-supplied value, or use X-Real-IP. With TRUSTED_PROXIES unset the header is ignored and clients are
+// keyed by the connection's address, so nobody can dodge a limit by sending the header themselves.
+func TrustProxiesFromEnv(cfg *fiber.Config) {
+	var proxies []string
+	for _, p := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			proxies = append(proxies, p)
+		}
+	}
+	cfg.EnableTrustedProxyCheck = true
+	cfg.TrustedProxies = proxies
+	if len(proxies) == 0 {
+		return
+	}
+	cfg.ProxyHeader = strings.TrimSpace(os.Getenv("PROXY_HEADER"))
+	if cfg.ProxyHeader == "" {
+		cfg.ProxyHeader = fiber.HeaderXForwardedFor
+	}
+	cfg.EnableIPValidation = true // First valid IP in the header, not the raw list
+}
+
+// rateLimitFromEnv parses RATE_LIMIT_<GROUP> as "<max>/<duration>", falling back to def.
+func rateLimitFromEnv(group string, def RateLimitConfig) (RateLimitConfig, bool) {
+	name := "RATE_LIMIT_" + strings.ToUpper(group)
+	v := strings.TrimSpace(os.Getenv(name))
+	if v == "" {
+		return def, true
+	}
+	if strings.EqualFold(v, "off") {
+		return def, false
+	}
+	parts := strings.SplitN(v, "/", 2)
+	if len(parts) == 2 {
+		max, err1 := strconv.Atoi(strings.TrimSpace(parts[0]))
+		window, err2 := time.ParseDuration(strings.TrimSpace(parts[1]))
+		if err1 == nil && err2 == nil && max > 0 && window > 0 {
+			return RateLimitConfig{Max: max, Window: window}, true
+		}
+	}
+	log.Printf("Invalid %s %q (want e.g. 10/1m), using %d/%s", name, v, def.Max, def.Window)
+	return def, true
+}
@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// limitedApp serves GET / behind a 1-request-per-minute limiter.
+func limitedApp() *fiber.App {
+	cfg := fiber.Config{ErrorHandler: ErrorHandler}
+	TrustProxiesFromEnv(&cfg)
+	app := fiber.New(cfg)
+	app.Get("/", RateLimit("test", RateLimitConfig{Max: 1, Window: time.Minute}, nil), func(c *fiber.Ctx) error {
+		return c.SendString(c.IP())
+	})
+	return app
+}
+
+func get(t *testing.T, app *fiber.App, forwardedFor string) int {
+	t.Helper()
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set(fiber.HeaderXForwardedFor, forwardedFor)
+	res, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	return res.StatusCode
+}
+
+func TestRateLimitIgnoresForwardedForByDefault(t *testing.T) {
+	t.Setenv("TRUSTED_PROXIES", "")
+	app := limitedApp()
+	if code := get(t, app, "203.0.113.1"); code != fiber.StatusOK {
+		t.Fatalf("first request = %d, want 200", code)
+	}
+	// A client can't get a fresh budget by making up a header
+	if code := get(t, app, "203.0.113.2"); code != fiber.StatusTooManyRequests {
+		t.Fatalf("second request with a new X-Forwarded-For = %d, want 429", code)
+	}
+}
+
+func TestRateLimitKeysOnForwardedForFromTrustedProxy(t *testing.T) {
+	t.Setenv("TRUSTED_PROXIES", "0.0.0.0/0") // app.Test connections come from 0.0.0.0
+	app := limitedApp()
+	for _, ip := range []string{"203.0.113.1", "203.0.113.2"} {
+		if code := get(t, app, ip); code != fiber.StatusOK {
+			t.Fatalf("first request from %s = %d, want 200", ip, code)
+		}
+	}
+	if code := get(t, app, "203.0.113.1"); code != fiber.StatusTooManyRequests {
+		t.Fatalf("second request from 203.0.113.1 = %d, want 429", code)
+	}
+}
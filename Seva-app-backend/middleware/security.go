@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const defaultHSTSMaxAge = 365 * 24 * 60 * 60 // one year, in seconds
+
+// SecurityHeaders sets a conservative default set of response headers for a JSON API.
+// Strict-Transport-Security uses HSTS_MAX_AGE (seconds, default one year); set it to 0 or
+// "off" for local HTTP development, where browsers would otherwise pin the host to HTTPS.
+func SecurityHeaders() fiber.Handler {
+	hsts := "max-age=" + strconv.Itoa(defaultHSTSMaxAge) + "; includeSubDomains"
+	if v := os.Getenv("HSTS_MAX_AGE"); v != "" {
+		if v == "off" {
+			hsts = ""
+		} else if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			hsts = ""
+			if n > 0 {
+				hsts = "max-age=" + strconv.Itoa(n) + "; includeSubDomains"
+			}
+		} else {
+			log.Printf("Invalid HSTS_MAX_AGE %q, using %d", v, defaultHSTSMaxAge)
+		}
+	}
+
+	return func(c *fiber.Ctx) error {
+		c.Set("X-Content-Type-Options", "nosniff")
+		c.Set("X-Frame-Options", "DENY")
+		c.Set("Referrer-Policy", "no-referrer")
+		c.Set("Content-Security-Policy", "default-src 'none'; frame-ancestors 'none'")
+		c.Set("Cross-Origin-Resource-Policy", "cross-origin") // The API is called from other origins (see CORS)
+		if hsts != "" {
+			c.Set("Strict-Transport-Security", hsts)
+		}
+		return c.Next()
+	}
+}
@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// StrictBodyParser is c.BodyParser for JSON bodies that rejects keys the DTO out does not declare,
+// so a typo like {"emial": ...} is a 400 listing the unrecognized fields instead of a silently
+// ignored value. Non-JSON bodies (forms) fall through to c.BodyParser unchanged.
+func StrictBodyParser(c *fiber.Ctx, out any) error {
+	if !strings.HasPrefix(strings.ToLower(c.Get(fiber.HeaderContentType)), fiber.MIMEApplicationJSON) {
+		if err := c.BodyParser(out); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "Bad JSON")
+		}
+		return nil
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(c.Body(), &raw); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Bad JSON")
+	}
+	known := map[string]bool{}
+	jsonFieldNames(reflect.TypeOf(out), known)
+	var unknown []string
+	for k := range raw {
+		// encoding/json matches keys case-insensitively, so do the same here
+		if !known[strings.ToLower(k)] {
+			unknown = append(unknown, k)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fiber.NewError(fiber.StatusBadRequest, "Unrecognized field(s): "+strings.Join(unknown, ", "))
+	}
+	if err := json.Unmarshal(c.Body(), out); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Bad JSON")
+	}
+	return nil
+}
+
+// jsonFieldNames adds the lower-cased JSON keys of struct type t (following pointers and
+// embedded structs) to names.
+func jsonFieldNames(t reflect.Type, names map[string]bool) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" {
+			jsonFieldNames(f.Type, names)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		names[strings.ToLower(name)] = true
+	}
+}
@@ -2,6 +2,10 @@ package models
 
 import (
 	"database/sql"
+	"encoding/json"
+	"log"
+	"os"
+	"strconv"
 	"time"
 )
 
@@ -10,6 +14,49 @@ type ErrorResponse struct {
 	Error string `json:"error"`
 }
 
+// ValidationErrorResponse is the 422 body for request validation failures. Error is always
+// "validation_failed"; Fields maps each offending JSON field to a code such as "required",
+// "invalid_format", "invalid_value", "too_long" or "out_of_range".
+type ValidationErrorResponse struct {
+	Error  string            `json:"error"`
+	Fields map[string]string `json:"fields"`
+}
+
+// Maximum lengths, in characters, of free-text fields. Create/update handlers reject longer
+// values with a 422 "too_long" field error (validation.Validator.MaxLen) so oversized text never reaches
+// the database or list responses. LoadLimitsFromEnv overrides the defaults.
+var (
+	MaxTitleLen       = 200   // announcements.title (MAX_TITLE_LEN)
+	MaxBodyLen        = 10000 // announcements.body (MAX_BODY_LEN)
+	MaxNameLen        = 200   // volunteer, committee and shift names (MAX_NAME_LEN)
+	MaxDescriptionLen = 2000  // committees.description (MAX_DESCRIPTION_LEN)
+	MaxQuestionLen    = 2000  // questions.question_text (MAX_QUESTION_LEN)
+	MaxAnswerLen      = 10000 // questions.answer_text (MAX_ANSWER_LEN)
+)
+
+// LoadLimitsFromEnv sets the Max*Len limits from their environment variables, keeping the default for
+// any that is unset or not a positive integer. main calls it once .env is loaded, before serving.
+func LoadLimitsFromEnv() {
+	for name, limit := range map[string]*int{
+		"MAX_TITLE_LEN":       &MaxTitleLen,
+		"MAX_BODY_LEN":        &MaxBodyLen,
+		"MAX_NAME_LEN":        &MaxNameLen,
+		"MAX_DESCRIPTION_LEN": &MaxDescriptionLen,
+		"MAX_QUESTION_LEN":    &MaxQuestionLen,
+		"MAX_ANSWER_LEN":      &MaxAnswerLen,
+	} {
+		v := os.Getenv(name)
+		if v == "" {
+			continue
+		}
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			*limit = n
+		} else {
+			log.Printf("Ignoring invalid %s=%q (want a positive integer); using %d", name, v, *limit)
+		}
+	}
+}
+
 // Enums (moved or adapted from original files)
 type AnnouncementPriority string
 
@@ -20,6 +67,18 @@ const (
 	PriorityUrgent AnnouncementPriority = "urgent"
 )
 
+type QuestionCategory string
+
+const (
+	CategoryGeneral       QuestionCategory = "general"
+	CategoryLogistics     QuestionCategory = "logistics"
+	CategoryFood          QuestionCategory = "food"
+	CategoryMedical       QuestionCategory = "medical"
+	CategoryAccommodation QuestionCategory = "accommodation"
+	CategoryTransport     QuestionCategory = "transport"
+	CategoryOther         QuestionCategory = "other"
+)
+
 type LocationType string
 
 const (
@@ -32,6 +91,42 @@ const (
 	LocTypePoi      LocationType = "poi"
 )
 
+// LocationTypes lists every valid LocationType (mirrors the location_type enum).
+var LocationTypes = []LocationType{
+	LocTypeStage, LocTypeDining, LocTypeHelpdesk, LocTypeParking, LocTypeWater, LocTypeToilet, LocTypePoi,
+}
+
+// Label is a human-friendly name for map legends and filters.
+func (t LocationType) Label() string {
+	switch t {
+	case LocTypeStage:
+		return "Stage"
+	case LocTypeDining:
+		return "Dining"
+	case LocTypeHelpdesk:
+		return "Help Desk"
+	case LocTypeParking:
+		return "Parking"
+	case LocTypeWater:
+		return "Drinking Water"
+	case LocTypeToilet:
+		return "Toilets"
+	case LocTypePoi:
+		return "Point of Interest"
+	}
+	return string(t)
+}
+
+// ValidLocationType reports whether t is one of LocationTypes.
+func ValidLocationType(t LocationType) bool {
+	for _, v := range LocationTypes {
+		if v == t {
+			return true
+		}
+	}
+	return false
+}
+
 type AssignmentRole string
 
 const (
@@ -69,34 +164,59 @@ type Event struct {
 }
 
 type Committee struct {
-	ID          int64     `json:"id"`
-	EventID     int64     `json:"event_id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	CreatedAt   time.Time `json:"created_at"`
-	EventName   string    `json:"event_name,omitempty"`
+	ID           int64     `json:"id"`
+	EventID      int64     `json:"event_id"`
+	Name         string    `json:"name"`
+	Description  string    `json:"description"`
+	DisplayOrder int       `json:"display_order"` // Lower first; ties are ordered by name
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	EventName    string    `json:"event_name,omitempty"`
 }
 
 type Faculty struct {
-	ID           int64    `json:"id"`
-	Name         string   `json:"name"`
-	Email        *string  `json:"email"`
-	Phone        *string  `json:"phone"`
-	Department   *string  `json:"department"`
-	Role         UserRole `json:"role"` // Uses models.UserRole
-	PasswordHash *string  `json:"-"`    // Don't expose password hash
+	ID           int64      `json:"id"`
+	Name         string     `json:"name"`
+	Email        *string    `json:"email"`
+	Phone        *string    `json:"phone"`
+	Department   *string    `json:"department"`
+	Role         UserRole   `json:"role"`                    // Uses models.UserRole
+	PasswordHash *string    `json:"-"`                       // Don't expose password hash
+	LastLoginAt  *time.Time `json:"last_login_at,omitempty"` // Admin listings only
+}
+
+// FacultyEventRole grants a faculty member access to one event's attendance and announcements.
+type FacultyEventRole struct {
+	FacultyID int64     `json:"faculty_id"`
+	EventID   int64     `json:"event_id"`
+	EventName string    `json:"event_name"`
+	GrantedBy *int64    `json:"granted_by"`
+	GrantedAt time.Time `json:"granted_at"`
+}
+
+type GrantEventScopeRequest struct {
+	EventID int64 `json:"event_id"`
+}
+
+type Department struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
 }
 
 type Volunteer struct {
-	ID           int64     `json:"id"`
-	Name         string    `json:"name"`
-	Email        *string   `json:"email"`
-	Phone        *string   `json:"phone"`
-	Dept         *string   `json:"dept"`
-	CollegeID    *string   `json:"college_id"`
-	PasswordHash *string   `json:"-"`    // For volunteer login
-	Role         UserRole  `json:"role"` // Uses models.UserRole
-	CreatedAt    time.Time `json:"created_at"`
+	ID           int64      `json:"id"`
+	Name         string     `json:"name"`
+	Email        *string    `json:"email"`
+	Phone        *string    `json:"phone"`
+	Dept         *string    `json:"dept"`
+	CollegeID    *string    `json:"college_id"`
+	PasswordHash *string    `json:"-"`    // For volunteer login
+	Role         UserRole   `json:"role"` // Uses models.UserRole
+	PhotoURL     *string    `json:"photo_url"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+	LastLoginAt  *time.Time `json:"last_login_at,omitempty"` // Admin listings only
 }
 
 type VolunteerAssignment struct {
@@ -108,10 +228,12 @@ type VolunteerAssignment struct {
 	Status        AssignmentStatus `json:"status"`
 	ReportingTime *time.Time       `json:"reporting_time"`
 	Shift         *string          `json:"shift"`      // New field
+	ShiftID       *int64           `json:"shift_id"`   // Linked shift definition; Shift mirrors its name
 	StartTime     *time.Time       `json:"start_time"` // New field
 	EndTime       *time.Time       `json:"end_time"`   // New field
 	Notes         *string          `json:"notes"`
 	CreatedAt     time.Time        `json:"created_at"`
+	UpdatedAt     time.Time        `json:"updated_at"`
 
 	// Enriched fields for responses
 	VolunteerName      string  `json:"volunteer_name,omitempty"`
@@ -121,6 +243,48 @@ type VolunteerAssignment struct {
 	EventName          string  `json:"event_name,omitempty"`
 }
 
+// ScheduleDay groups a volunteer's shifts that start on one (event-local) date.
+type ScheduleDay struct {
+	Date   string          `json:"date"` // YYYY-MM-DD
+	Shifts []ScheduleShift `json:"shifts"`
+}
+
+// ScheduleShift is one assignment in a volunteer's schedule, with its check-in status for that day.
+type ScheduleShift struct {
+	AssignmentID  int64      `json:"assignment_id"`
+	EventID       int64      `json:"event_id"`
+	EventName     string     `json:"event_name"`
+	CommitteeID   int64      `json:"committee_id"`
+	CommitteeName string     `json:"committee_name"`
+	Shift         *string    `json:"shift"`
+	ReportingTime *time.Time `json:"reporting_time"`
+	StartTime     time.Time  `json:"start_time"`
+	EndTime       *time.Time `json:"end_time"`
+	CheckInStatus string     `json:"check_in_status"` // not_checked_in | checked_in | checked_out
+	AttendanceID  *int64     `json:"attendance_id"`   // Latest attendance record on that day, if any
+}
+
+// AttendanceDay groups a volunteer's attendance records by (event-local) check-in date.
+type AttendanceDay struct {
+	Date    string                 `json:"date"` // YYYY-MM-DD
+	Hours   float64                `json:"hours"`
+	Entries []AttendanceHistoryRow `json:"entries"`
+}
+
+// AttendanceHistoryRow is one attendance record in a volunteer's own history.
+type AttendanceHistoryRow struct {
+	AttendanceID    int64      `json:"attendance_id"`
+	AssignmentID    int64      `json:"assignment_id"`
+	EventID         int64      `json:"event_id"`
+	EventName       string     `json:"event_name"`
+	CommitteeID     int64      `json:"committee_id"`
+	CommitteeName   string     `json:"committee_name"`
+	Shift           *string    `json:"shift"`
+	CheckInTime     time.Time  `json:"check_in_time"`
+	CheckOutTime    *time.Time `json:"check_out_time"`
+	DurationMinutes *int64     `json:"duration_minutes"` // Null while still checked in
+}
+
 // Updated Attendance struct (no approval fields, added Shift field)
 type Attendance struct {
 	ID           int64      `json:"id"`
@@ -130,6 +294,8 @@ type Attendance struct {
 	Lat          *float64   `json:"lat"`             // Ptr for nullable
 	Lng          *float64   `json:"lng"`             // Ptr for nullable
 	Shift        *string    `json:"shift,omitempty"` // NEW: Added Shift field for context
+	CheckedInBy  *int64     `json:"checked_in_by"`   // Faculty who recorded the check-in (null = volunteer self check-in)
+	CheckedOutBy *int64     `json:"checked_out_by"`  // Faculty who recorded the check-out
 
 	// Enriched fields for responses (assuming these are populated by joins)
 	VolunteerID        int64   `json:"volunteer_id,omitempty"`
@@ -137,6 +303,8 @@ type Attendance struct {
 	EventID            int64   `json:"event_id,omitempty"`
 	VolunteerName      string  `json:"volunteer_name,omitempty"`
 	VolunteerCollegeID *string `json:"volunteer_college_id,omitempty"` // NEW: Added VolunteerCollegeID
+	VolunteerPhone     *string `json:"volunteer_phone,omitempty"`      // Set by the active-checkin listings
+	Suspicious         *bool   `json:"suspicious,omitempty"`           // Set by ListAllAttendance: checked out too soon after checking in
 	CommitteeName      string  `json:"committee_name,omitempty"`
 	EventName          string  `json:"event_name,omitempty"`
 }
@@ -150,11 +318,35 @@ type Announcement struct {
 	Priority    AnnouncementPriority `json:"priority"`
 	CreatedBy   *int64               `json:"created_by"`
 	CreatedAt   time.Time            `json:"created_at"`
+	UpdatedAt   time.Time            `json:"updated_at"`
 	ExpiresAt   *time.Time           `json:"expires_at"`
+	PublishAt   *time.Time           `json:"publish_at"`
+	Scheduled   bool                 `json:"scheduled"` // publish_at is still in the future
+
+	// Optional targeting; when both are empty the announcement is event/committee-wide
+	TargetRole         *AssignmentRole `json:"target_role"`
+	TargetVolunteerIDs []int64         `json:"target_volunteer_ids"`
 
 	// Enriched fields for responses
 	CreatedByName *string `json:"created_by_name,omitempty"`
 	CommitteeName *string `json:"committee_name,omitempty"`
+	Acked         *bool   `json:"acked,omitempty"` // Only set on the volunteer-facing list
+}
+
+type AnnouncementAckStatus struct {
+	VolunteerID int64      `json:"volunteer_id"`
+	Name        string     `json:"name"`
+	CollegeID   *string    `json:"college_id"`
+	Acked       bool       `json:"acked"`
+	AckedAt     *time.Time `json:"acked_at"`
+}
+
+type AnnouncementAcksResponse struct {
+	AnnouncementID int64                   `json:"announcement_id"`
+	Total          int                     `json:"total"`
+	Acked          int                     `json:"acked"`
+	Pending        int                     `json:"pending"`
+	Volunteers     []AnnouncementAckStatus `json:"volunteers"`
 }
 
 type Location struct {
@@ -162,9 +354,34 @@ type Location struct {
 	EventID     int64        `json:"event_id"`
 	Name        string       `json:"name"`
 	Type        LocationType `json:"type"`
-	Description string       `json:"description"`
+	Description string       `json:"description"` // "" when the column is NULL (it can be cleared)
 	Lat         float64      `json:"lat"`
 	Lng         float64      `json:"lng"`
+	DistanceM   *float64     `json:"distance_m,omitempty"` // Only set for near_lat/near_lng searches
+}
+
+// GeoJSON FeatureCollection of locations (RFC 7946), for map frontends
+type LocationFeatureCollection struct {
+	Type     string            `json:"type"` // Always "FeatureCollection"
+	Features []LocationFeature `json:"features"`
+}
+
+type LocationFeature struct {
+	Type       string             `json:"type"` // Always "Feature"
+	Geometry   GeoJSONPoint       `json:"geometry"`
+	Properties LocationProperties `json:"properties"`
+}
+
+type GeoJSONPoint struct {
+	Type        string     `json:"type"`        // Always "Point"
+	Coordinates [2]float64 `json:"coordinates"` // [lng, lat]
+}
+
+type LocationProperties struct {
+	ID          int64        `json:"id"`
+	Name        string       `json:"name"`
+	Type        LocationType `json:"type"`
+	Description *string      `json:"description"`
 }
 
 type CarbonFootprint struct {
@@ -190,30 +407,43 @@ type ApiKey struct {
 }
 
 type AuditLog struct {
-	ID          int64     `json:"id"`
-	ActorType   string    `json:"actor_type"`
-	ActorID     *string   `json:"actor_id"`
-	EventID     *int64    `json:"event_id"`
-	EntityTable string    `json:"entity_table"`
-	EntityID    string    `json:"entity_id"`
-	Action      string    `json:"action"`
-	Diff        []byte    `json:"diff"`
-	CreatedAt   time.Time `json:"created_at"`
+	ID          int64           `json:"id"`
+	ActorType   string          `json:"actor_type"`
+	ActorID     *string         `json:"actor_id"`
+	EventID     *int64          `json:"event_id"`
+	EntityTable string          `json:"entity_table"`
+	EntityID    string          `json:"entity_id"`
+	Action      string          `json:"action"`
+	Diff        json.RawMessage `json:"diff"` // JSONB inlined as-is
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+// Page wraps one page of a list endpoint with the total row count for its filters.
+type Page[T any] struct {
+	Items  []T   `json:"items"`
+	Total  int64 `json:"total"`
+	Limit  int   `json:"limit"`
+	Offset int   `json:"offset"`
 }
 
 // NEW: Question model for "May I Help You"
 type Question struct {
-	ID             int64      `json:"id"`
-	VolunteerID    *int64     `json:"volunteer_id"` // Nullable if anonymous is allowed
-	VolunteerName  *string    `json:"volunteer_name,omitempty"`
-	QuestionText   string     `json:"question_text"`
-	AskedAt        time.Time  `json:"asked_at"`
-	EventID        *int64     `json:"event_id"`     // Optional: event context for the question
-	CommitteeID    *int64     `json:"committee_id"` // Optional: committee context for the question
-	AnsweredBy     *int64     `json:"answered_by"`
-	AnsweredByName *string    `json:"answered_by_name,omitempty"`
-	AnswerText     *string    `json:"answer_text"` // Null if not answered
-	AnsweredAt     *time.Time `json:"answered_at"` // Null if not answered
+	ID             int64            `json:"id"`
+	VolunteerID    *int64           `json:"volunteer_id"` // Nullable if anonymous is allowed
+	VolunteerName  *string          `json:"volunteer_name,omitempty"`
+	QuestionText   string           `json:"question_text"`
+	AskedAt        time.Time        `json:"asked_at"`
+	EventID        *int64           `json:"event_id"`     // Optional: event context for the question
+	CommitteeID    *int64           `json:"committee_id"` // Optional: committee context for the question
+	AnsweredBy     *int64           `json:"answered_by"`
+	AnsweredByName *string          `json:"answered_by_name,omitempty"`
+	AnswerText     *string          `json:"answer_text"` // Null if not answered
+	AnsweredAt     *time.Time       `json:"answered_at"` // Null if not answered
+	Category       QuestionCategory `json:"category"`
+	VoteCount      int              `json:"vote_count"`
+	AssignedTo     *int64           `json:"assigned_to"` // Faculty the question is routed to; null if unassigned
+	AssignedToName *string          `json:"assigned_to_name,omitempty"`
+	AssignedAt     *time.Time       `json:"assigned_at,omitempty"`
 }
 
 // Request DTOs (Data Transfer Objects)
@@ -231,6 +461,12 @@ type LoginResponse struct {
 	UserID       int64    `json:"user_id"`
 }
 
+// AvailabilityResponse answers GET /auth/check-availability; only the fields that were asked about are set.
+type AvailabilityResponse struct {
+	EmailAvailable     *bool `json:"email_available,omitempty"`
+	CollegeIDAvailable *bool `json:"college_id_available,omitempty"`
+}
+
 type RefreshRequest struct {
 	RefreshToken string `json:"refresh_token"`
 }
@@ -242,6 +478,14 @@ type RegisterFacultyRequest struct { // Admin registers faculty
 	Role     *UserRole `json:"role"` // Uses models.UserRole
 }
 
+type UpdateFacultyRequest struct { // Admin updates a faculty/admin account
+	Name       *string   `json:"name"`
+	Email      *string   `json:"email"`
+	Phone      *string   `json:"phone"` // "" clears it
+	Department *string   `json:"department"`
+	Role       *UserRole `json:"role"` // 'admin' or 'faculty'
+}
+
 type RegisterVolunteerRequest struct { // Student self-registers
 	Name      string  `json:"name"`
 	Email     string  `json:"email"`
@@ -262,6 +506,7 @@ type CreateVolunteerRequest struct { // Admin creates volunteer (can omit passwo
 	Phone     *string `json:"phone"`
 	Dept      *string `json:"dept"`
 	CollegeID *string `json:"college_id"`
+	PhotoURL  *string `json:"photo_url"`
 	Password  *string `json:"password,omitempty"` // Admin can set an initial password
 }
 
@@ -271,8 +516,21 @@ type UpdateVolunteerRequest struct {
 	Phone     *string   `json:"phone"`
 	Dept      *string   `json:"dept"`
 	CollegeID *string   `json:"college_id"`
+	PhotoURL  *string   `json:"photo_url"`
 	Password  *string   `json:"password"` // Admin can update password
 	Role      *UserRole `json:"role"`     // Uses models.UserRole
+	// ExpectedUpdatedAt is the updated_at the client last read; when set, the update is
+	// rejected with 409 if the volunteer has been modified since.
+	ExpectedUpdatedAt *time.Time `json:"expected_updated_at"`
+}
+
+// UpdateSMSOptOutRequest lets a volunteer opt out of (or back into) SMS/WhatsApp messages.
+type UpdateSMSOptOutRequest struct {
+	OptOut bool `json:"opt_out"`
+}
+
+type UpdateMyPhotoRequest struct {
+	PhotoURL *string `json:"photo_url"` // Empty string or null clears the photo
 }
 
 type CreateVolunteerAssignmentRequest struct {
@@ -283,26 +541,59 @@ type CreateVolunteerAssignmentRequest struct {
 	Status        AssignmentStatus `json:"status"`
 	ReportingTime *time.Time       `json:"reporting_time"`
 	Shift         *string          `json:"shift"`
+	ShiftID       *int64           `json:"shift_id"` // Takes precedence over shift; must belong to the event
 	StartTime     *time.Time       `json:"start_time"`
 	EndTime       *time.Time       `json:"end_time"`
 	Notes         *string          `json:"notes"`
 }
 
+// CopyAssignmentsRequest duplicates a committee's roster into another committee/event.
+type CopyAssignmentsRequest struct {
+	FromCommitteeID int64   `json:"from_committee_id"`
+	ToCommitteeID   int64   `json:"to_committee_id"`
+	ToEventID       int64   `json:"to_event_id"`
+	NewShift        *string `json:"new_shift,omitempty"` // Overrides the copied shift when set
+}
+
+// ReassignAssignmentRequest moves an assignment to another committee (and optionally event) in place.
+type ReassignAssignmentRequest struct {
+	CommitteeID int64  `json:"committee_id"`
+	EventID     *int64 `json:"event_id,omitempty"` // Defaults to the assignment's current event
+}
+
 type UpdateVolunteerAssignmentRequest struct {
 	Role          *AssignmentRole   `json:"role"`
 	Status        *AssignmentStatus `json:"status"`
 	ReportingTime *time.Time        `json:"reporting_time"`
 	Shift         *string           `json:"shift"`
+	ShiftID       *int64            `json:"shift_id"` // Takes precedence over shift; must belong to the event
 	StartTime     *time.Time        `json:"start_time"`
 	EndTime       *time.Time        `json:"end_time"`
 	Notes         *string           `json:"notes"`
+	// ExpectedUpdatedAt is the updated_at the client last read; when set, the update is
+	// rejected with 409 if the assignment has been modified since.
+	ExpectedUpdatedAt *time.Time `json:"expected_updated_at"`
 }
 
+// CheckInRequest is accepted as JSON or multipart form; a multipart "photo" file part
+// is the alternative to PhotoBase64.
 type CheckInRequest struct {
-	AssignmentID int64    `json:"assignment_id"`
-	Lat          *float64 `json:"lat"`
-	Lng          *float64 `json:"lng"`
-	TimeISO      *string  `json:"time,omitempty"` // RFC3339, defaults to now
+	AssignmentID int64    `json:"assignment_id" form:"assignment_id"`
+	Lat          *float64 `json:"lat" form:"lat"`
+	Lng          *float64 `json:"lng" form:"lng"`
+	TimeISO      *string  `json:"time,omitempty" form:"time"`                 // RFC3339, defaults to now
+	PhotoBase64  *string  `json:"photo_base64,omitempty" form:"photo_base64"` // Optional selfie (raw base64 or data: URL)
+}
+
+// PasswordResetRequest starts the password-reset flow for an account email.
+type PasswordResetRequest struct {
+	Email string `json:"email"`
+}
+
+// PasswordResetConfirmRequest sets a new password using the emailed token.
+type PasswordResetConfirmRequest struct {
+	Token       string `json:"token"`
+	NewPassword string `json:"new_password"`
 }
 
 type CheckOutRequest struct {
@@ -310,6 +601,36 @@ type CheckOutRequest struct {
 	TimeISO      *string `json:"time,omitempty"` // RFC3339, defaults to now
 }
 
+// UpdateAttendanceRequest corrects an attendance record; omitted fields are left unchanged.
+type UpdateAttendanceRequest struct {
+	CheckInTime  *string  `json:"check_in_time,omitempty"`  // RFC3339
+	CheckOutTime *string  `json:"check_out_time,omitempty"` // RFC3339
+	Lat          *float64 `json:"lat,omitempty"`
+	Lng          *float64 `json:"lng,omitempty"`
+}
+
+// BatchDeleteVolunteersRequest removes several volunteers at once.
+// With Soft, volunteers that have attendance history are kept and their assignments cancelled instead.
+type BatchDeleteVolunteersRequest struct {
+	IDs  []int64 `json:"ids"`
+	Soft bool    `json:"soft"`
+}
+
+// MergeVolunteersRequest folds a duplicate volunteer (MergeID) into the record that is kept (KeepID).
+type MergeVolunteersRequest struct {
+	KeepID  int64 `json:"keep_id"`
+	MergeID int64 `json:"merge_id"`
+}
+
+// ManualAttendanceRequest lets faculty record attendance on a volunteer's behalf.
+type ManualAttendanceRequest struct {
+	AssignmentID int64    `json:"assignment_id"`
+	CheckInTime  string   `json:"check_in_time"`            // RFC3339
+	CheckOutTime *string  `json:"check_out_time,omitempty"` // RFC3339, optional
+	Lat          *float64 `json:"lat"`
+	Lng          *float64 `json:"lng"`
+}
+
 type CreateAnnouncementRequest struct {
 	EventID     int64                `json:"event_id"`
 	CommitteeID *int64               `json:"committee_id"`
@@ -317,14 +638,22 @@ type CreateAnnouncementRequest struct {
 	Body        string               `json:"body"`
 	Priority    AnnouncementPriority `json:"priority"`
 	ExpiresAt   *time.Time           `json:"expires_at"`
+	PublishAt   *time.Time           `json:"publish_at"` // Optional; future value schedules the announcement
+
+	TargetRole         *AssignmentRole `json:"target_role"`          // Only volunteers with this assignment role
+	TargetVolunteerIDs []int64         `json:"target_volunteer_ids"` // Plus/or these specific volunteers
 }
 
 type UpdateAnnouncementRequest struct {
-	CommitteeID *int64                `json:"committee_id"`
+	CommitteeID *int64                `json:"committee_id"` // Explicit null makes the announcement event-wide
 	Title       *string               `json:"title"`
 	Body        *string               `json:"body"`
 	Priority    *AnnouncementPriority `json:"priority"`
 	ExpiresAt   *time.Time            `json:"expires_at"`
+	PublishAt   *time.Time            `json:"publish_at"` // Explicit null publishes now
+
+	TargetRole         *AssignmentRole `json:"target_role"`          // "" clears the role filter
+	TargetVolunteerIDs *[]int64        `json:"target_volunteer_ids"` // Replaces the list; [] clears it
 }
 
 type CreateLocationRequest struct {
@@ -332,8 +661,8 @@ type CreateLocationRequest struct {
 	Name        string       `json:"name"`
 	Type        LocationType `json:"type"`
 	Description *string      `json:"description"`
-	Lat         float64      `json:"lat"`
-	Lng         float64      `json:"lng"`
+	Lat         *float64     `json:"lat"` // Pointers so 0 (equator, prime meridian) is told apart from missing
+	Lng         *float64     `json:"lng"`
 }
 
 type UpdateLocationRequest struct {
@@ -363,25 +692,111 @@ type UpdateCarbonFootprintRequest struct {
 
 // NEW: Question DTOs
 type CreateQuestionRequest struct {
-	QuestionText string `json:"question_text"`
-	EventID      *int64 `json:"event_id,omitempty"`
-	CommitteeID  *int64 `json:"committee_id,omitempty"`
+	QuestionText string           `json:"question_text"`
+	EventID      *int64           `json:"event_id,omitempty"`
+	CommitteeID  *int64           `json:"committee_id,omitempty"`
+	Category     QuestionCategory `json:"category,omitempty"` // Defaults to "general"
 }
 
 type AnswerQuestionRequest struct {
 	AnswerText string `json:"answer_text"`
 }
 
+type AssignQuestionRequest struct {
+	FacultyID *int64 `json:"faculty_id"` // null unassigns
+}
+
+type CreateDepartmentRequest struct {
+	Name string `json:"name"`
+}
+
 type CreateCommitteeRequest struct {
-	EventID     int64   `json:"event_id"`    // Required: The event this committee belongs to
-	Name        string  `json:"name"`        // Required: Name of the committee
-	Description *string `json:"description"` // Optional: Description of the committee
+	EventID      int64   `json:"event_id"`      // Required: The event this committee belongs to
+	Name         string  `json:"name"`          // Required: Name of the committee
+	Description  *string `json:"description"`   // Optional: Description of the committee
+	DisplayOrder *int    `json:"display_order"` // Optional: Position in the app's committee list (default 0)
 }
 
 // UpdateCommitteeRequest represents the request body for updating an existing committee.
 type UpdateCommitteeRequest struct {
-	Name        *string `json:"name"`        // Optional: New name for the committee
-	Description *string `json:"description"` // Optional: New description for the committee
+	Name         *string `json:"name"`          // Optional: New name for the committee
+	Description  *string `json:"description"`   // Optional: New description for the committee
+	DisplayOrder *int    `json:"display_order"` // Optional: New position in the app's committee list
+}
+
+// ShiftCapacity is one shift of a committee from GET /committees/:id/capacities.
+// Capacity is null when the shift has assignments but no limit set.
+type ShiftCapacity struct {
+	CommitteeID int64  `json:"committee_id"`
+	Shift       string `json:"shift"`
+	Capacity    *int   `json:"capacity"`
+	Assigned    int    `json:"assigned"` // non-cancelled assignments
+}
+
+// CommitteeAssignmentStats is the staffing breakdown from GET /committees/:id/assignment-stats.
+// ByRole leaves out cancelled assignments, so a cancelled lead doesn't count toward the committee's leads.
+type CommitteeAssignmentStats struct {
+	CommitteeID int64                  `json:"committee_id"`
+	EventID     *int64                 `json:"event_id,omitempty"`
+	Total       int                    `json:"total"`
+	ByRole      AssignmentRoleCounts   `json:"by_role"`
+	ByStatus    AssignmentStatusCounts `json:"by_status"`
+}
+
+// AssignmentRoleCounts counts non-cancelled assignments per AssignmentRole.
+type AssignmentRoleCounts struct {
+	Volunteer int `json:"volunteer"`
+	Lead      int `json:"lead"`
+	Support   int `json:"support"`
+}
+
+// AssignmentStatusCounts counts assignments per AssignmentStatus.
+type AssignmentStatusCounts struct {
+	Assigned  int `json:"assigned"`
+	Standby   int `json:"standby"`
+	Cancelled int `json:"cancelled"`
+}
+
+// SetShiftCapacityRequest sets (or, with a null capacity, removes) the limit for one committee shift.
+type SetShiftCapacityRequest struct {
+	Shift    string `json:"shift"`
+	Capacity *int   `json:"capacity"`
+}
+
+// MoveCommitteeRequest re-homes a committee (and its assignments/announcements/questions) under another event.
+type MoveCommitteeRequest struct {
+	TargetEventID int64 `json:"target_event_id"`
+}
+
+// Shift is a named time slot of an event that assignments link to via shift_id.
+type Shift struct {
+	ID        int64      `json:"id"`
+	EventID   int64      `json:"event_id"`
+	Name      string     `json:"name"`
+	StartTime *time.Time `json:"start_time"`
+	EndTime   *time.Time `json:"end_time"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+type CreateShiftRequest struct {
+	EventID   int64      `json:"event_id"`
+	Name      string     `json:"name"`
+	StartTime *time.Time `json:"start_time"`
+	EndTime   *time.Time `json:"end_time"`
+}
+
+// UpdateShiftRequest changes a shift; renaming it also renames the shift text on its assignments.
+type UpdateShiftRequest struct {
+	Name      *string    `json:"name"`
+	StartTime *time.Time `json:"start_time"`
+	EndTime   *time.Time `json:"end_time"`
+}
+
+// ShiftSummary is one distinct shift name from GET /volunteers/shifts.
+type ShiftSummary struct {
+	Shift           string `json:"shift"`
+	AssignmentCount int    `json:"assignment_count"`
 }
 
 // NEW: Struct for the revised Pending endpoint (now list assignments that *could* have attendance)
@@ -394,6 +809,7 @@ type PendingShiftRow struct {
 	VolunteerID        int64            `json:"volunteer_id"`
 	VolunteerName      string           `json:"volunteer_name"`
 	VolunteerDept      *string          `json:"volunteer_dept,omitempty"`
+	VolunteerPhone     *string          `json:"volunteer_phone,omitempty"`
 	VolunteerCollegeID *string          `json:"volunteer_college_id,omitempty"` // NEW: Added College ID
 	AssignmentRole     AssignmentRole   `json:"assignment_role"`
 	AssignmentStatus   AssignmentStatus `json:"assignment_status"`
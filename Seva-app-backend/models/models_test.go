@@ -0,0 +1,17 @@
+package models
+
+import "testing"
+
+func TestLoadLimitsFromEnv(t *testing.T) {
+	defer func(name, answer int) { MaxNameLen, MaxAnswerLen = name, answer }(MaxNameLen, MaxAnswerLen)
+	t.Setenv("MAX_NAME_LEN", "50")
+	t.Setenv("MAX_ANSWER_LEN", "-3")
+
+	LoadLimitsFromEnv()
+	if MaxNameLen != 50 {
+		t.Errorf("MaxNameLen = %d, want 50", MaxNameLen)
+	}
+	if MaxAnswerLen != 10000 {
+		t.Errorf("MaxAnswerLen = %d, want the default 10000 for an invalid value", MaxAnswerLen)
+	}
+}
@@ -0,0 +1,70 @@
+package notify
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// sendTimeout bounds a single Notify call so a slow provider can't stall a worker forever.
+const sendTimeout = 15 * time.Second
+
+type job struct {
+	r Recipient
+	m Message
+}
+
+// Dispatcher fans notifications out to a fixed pool of workers so HTTP handlers never
+// block on delivery. The queue is bounded; when it is full new jobs are dropped and logged.
+// Hub carries the same messages to live in-process subscribers (SSE streams).
+type Dispatcher struct {
+	Hub *Hub
+
+	n     Notifier
+	queue chan job
+}
+
+// NewDispatcher starts workers goroutines delivering through n with a queue of queueSize jobs.
+func NewDispatcher(n Notifier, workers, queueSize int) *Dispatcher {
+	if workers < 1 {
+		workers = 1
+	}
+	d := &Dispatcher{Hub: NewHub(), n: n, queue: make(chan job, queueSize)}
+	for i := 0; i < workers; i++ {
+		go d.work()
+	}
+	return d
+}
+
+// Dispatch enqueues m for every recipient without blocking.
+func (d *Dispatcher) Dispatch(recipients []Recipient, m Message) {
+	if _, ok := d.n.(Noop); ok {
+		return
+	}
+	for _, r := range recipients {
+		select {
+		case d.queue <- job{r: r, m: m}:
+		default:
+			log.Printf("notify: queue full, dropping %s for volunteer %d", m.ID(), r.VolunteerID)
+		}
+	}
+}
+
+// Publish pushes m to any live subscribers among recipients via the Hub.
+func (d *Dispatcher) Publish(recipients []Recipient, m Message) {
+	ids := make([]int64, len(recipients))
+	for i, r := range recipients {
+		ids[i] = r.VolunteerID
+	}
+	d.Hub.Publish(ids, m)
+}
+
+func (d *Dispatcher) work() {
+	for j := range d.queue {
+		ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+		if err := d.n.Notify(ctx, j.r, j.m); err != nil {
+			log.Printf("notify: failed to notify volunteer %d about %s: %v", j.r.VolunteerID, j.m.ID(), err)
+		}
+		cancel()
+	}
+}
@@ -0,0 +1,66 @@
+package notify
+
+import "sync"
+
+// subscriberBuffer is how many undelivered messages a subscriber may lag behind before
+// new ones are dropped for it.
+const subscriberBuffer = 16
+
+// Hub is an in-process pub/sub keyed by volunteer ID. It backs live streams (SSE) so
+// connected clients get messages as soon as they are published.
+type Hub struct {
+	mu   sync.RWMutex
+	subs map[int64]map[chan Message]struct{}
+}
+
+// NewHub returns an empty Hub.
+func NewHub() *Hub {
+	return &Hub{subs: map[int64]map[chan Message]struct{}{}}
+}
+
+// Subscribe registers a listener for volunteerID. The returned cancel func must be called
+// when the listener goes away; it unregisters and closes the channel.
+func (h *Hub) Subscribe(volunteerID int64) (<-chan Message, func()) {
+	ch := make(chan Message, subscriberBuffer)
+	h.mu.Lock()
+	if h.subs[volunteerID] == nil {
+		h.subs[volunteerID] = map[chan Message]struct{}{}
+	}
+	h.subs[volunteerID][ch] = struct{}{}
+	h.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			h.mu.Lock()
+			delete(h.subs[volunteerID], ch)
+			if len(h.subs[volunteerID]) == 0 {
+				delete(h.subs, volunteerID)
+			}
+			h.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+// Publish delivers m to every current subscriber of the given volunteers without blocking;
+// a subscriber whose buffer is full misses the message.
+func (h *Hub) Publish(volunteerIDs []int64, m Message) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for _, id := range volunteerIDs {
+		for ch := range h.subs[id] {
+			select {
+			case ch <- m:
+			default:
+			}
+		}
+	}
+}
+
+// Len reports how many volunteers currently have at least one subscriber.
+func (h *Hub) Len() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.subs)
+}
@@ -0,0 +1,197 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Recipient is a volunteer to be notified.
+type Recipient struct {
+	VolunteerID int64   `json:"volunteer_id"`
+	Name        string  `json:"name"`
+	Phone       *string `json:"phone,omitempty"`
+	Email       *string `json:"email,omitempty"`
+	SMSOptOut   bool    `json:"sms_opt_out,omitempty"` // Volunteer opted out of SMS/WhatsApp; SMS providers skip them
+}
+
+// Message kinds.
+const (
+	KindAnnouncement     = "announcement"
+	KindQuestionAnswered = "question_answered"
+	KindShiftReminder    = "shift_reminder"
+)
+
+// Message is the content pushed to recipients. Kind says what it is about; the
+// matching ID field (AnnouncementID, QuestionID or AssignmentID) identifies the source record.
+type Message struct {
+	Kind           string `json:"kind"`
+	AnnouncementID int64  `json:"announcement_id,omitempty"`
+	QuestionID     int64  `json:"question_id,omitempty"`
+	AssignmentID   int64  `json:"assignment_id,omitempty"`
+	EventID        int64  `json:"event_id,omitempty"`
+	CommitteeID    *int64 `json:"committee_id,omitempty"`
+	Title          string `json:"title"`
+	Body           string `json:"body"`
+	Priority       string `json:"priority,omitempty"`
+}
+
+// sourceID returns the ID of the record m is about, for logs and SSE ids.
+func (m Message) sourceID() int64 {
+	switch m.Kind {
+	case KindQuestionAnswered:
+		return m.QuestionID
+	case KindShiftReminder:
+		return m.AssignmentID
+	}
+	return m.AnnouncementID
+}
+
+// ID returns a stable identifier for m, e.g. "announcement-12".
+func (m Message) ID() string {
+	return m.Kind + "-" + strconv.FormatInt(m.sourceID(), 10)
+}
+
+// Notifier delivers a message to a single recipient (SMS, push, webhook, ...).
+type Notifier interface {
+	Notify(ctx context.Context, r Recipient, m Message) error
+}
+
+// Noop discards every notification. It is the default when no provider is configured.
+type Noop struct{}
+
+func (Noop) Notify(context.Context, Recipient, Message) error { return nil }
+
+// Webhook POSTs {"recipient":..., "message":...} as JSON to URL, e.g. an SMS/push gateway.
+type Webhook struct {
+	URL    string
+	Token  string // Optional, sent as "Authorization: Bearer <token>"
+	Client *http.Client
+}
+
+func (w *Webhook) Notify(ctx context.Context, r Recipient, m Message) error {
+	payload, err := json.Marshal(map[string]any{"recipient": r, "message": m})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if w.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+w.Token)
+	}
+	resp, err := w.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// Twilio sends the message as an SMS (or WhatsApp message when From is "whatsapp:+...") through
+// Twilio's Messages API. Recipients without a phone number or who opted out are skipped.
+type Twilio struct {
+	AccountSID string
+	AuthToken  string
+	From       string // E.164 number, or "whatsapp:+<number>"
+	Client     *http.Client
+}
+
+func (t *Twilio) Notify(ctx context.Context, r Recipient, m Message) error {
+	if r.SMSOptOut || r.Phone == nil || strings.TrimSpace(*r.Phone) == "" {
+		return nil
+	}
+	to := strings.TrimSpace(*r.Phone)
+	if strings.HasPrefix(t.From, "whatsapp:") && !strings.HasPrefix(to, "whatsapp:") {
+		to = "whatsapp:" + to
+	}
+	form := url.Values{}
+	form.Set("From", t.From)
+	form.Set("To", to)
+	form.Set("Body", smsText(m))
+
+	endpoint := "https://api.twilio.com/2010-04-01/Accounts/" + url.PathEscape(t.AccountSID) + "/Messages.json"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(t.AccountSID, t.AuthToken)
+	resp, err := t.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("twilio returned %s", resp.Status)
+	}
+	return nil
+}
+
+// smsMaxLen keeps a message within a few SMS segments.
+const smsMaxLen = 480
+
+// smsText flattens m to "Title: body", trimmed to smsMaxLen runes.
+func smsText(m Message) string {
+	s := m.Title
+	if m.Body != "" {
+		s += ": " + m.Body
+	}
+	if r := []rune(s); len(r) > smsMaxLen {
+		s = string(r[:smsMaxLen-1]) + "…"
+	}
+	return s
+}
+
+// FromEnv builds the Notifier selected by NOTIFY_PROVIDER.
+//   - "" / "none": Noop
+//   - "webhook":   Webhook using NOTIFY_WEBHOOK_URL (and optional NOTIFY_WEBHOOK_TOKEN)
+//   - "twilio":    Twilio using TWILIO_ACCOUNT_SID, TWILIO_AUTH_TOKEN and TWILIO_FROM
+func FromEnv() Notifier {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv("NOTIFY_PROVIDER"))) {
+	case "", "none", "noop":
+		return Noop{}
+	case "webhook":
+		url := os.Getenv("NOTIFY_WEBHOOK_URL")
+		if url == "" {
+			log.Println("NOTIFY_PROVIDER=webhook but NOTIFY_WEBHOOK_URL is not set; notifications disabled")
+			return Noop{}
+		}
+		return &Webhook{
+			URL:    url,
+			Token:  os.Getenv("NOTIFY_WEBHOOK_TOKEN"),
+			Client: &http.Client{Timeout: 10 * time.Second},
+		}
+	case "twilio":
+		sid, token, from := os.Getenv("TWILIO_ACCOUNT_SID"), os.Getenv("TWILIO_AUTH_TOKEN"), os.Getenv("TWILIO_FROM")
+		if sid == "" || token == "" || from == "" {
+			log.Println("NOTIFY_PROVIDER=twilio but TWILIO_ACCOUNT_SID, TWILIO_AUTH_TOKEN or TWILIO_FROM is not set; notifications disabled")
+			return Noop{}
+		}
+		return &Twilio{AccountSID: sid, AuthToken: token, From: from, Client: &http.Client{Timeout: 10 * time.Second}}
+	default:
+		log.Printf("Unknown NOTIFY_PROVIDER %q; notifications disabled", os.Getenv("NOTIFY_PROVIDER"))
+		return Noop{}
+	}
+}
+
+// WorkersFromEnv reads NOTIFY_WORKERS, defaulting to def.
+func WorkersFromEnv(def int) int {
+	if n, err := strconv.Atoi(os.Getenv("NOTIFY_WORKERS")); err == nil && n > 0 {
+		return n
+	}
+	return def
+}
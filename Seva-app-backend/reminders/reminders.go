@@ -0,0 +1,135 @@
+package reminders
+
+import (
+	"context"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"Seva-app-backend/notify"
+)
+
+// lockKey is the pg_advisory_lock key that keeps a single instance sending reminders.
+const lockKey = 0x5e7a_0001
+
+// Config controls the reminder scheduler.
+type Config struct {
+	Lead     time.Duration // How long before reporting/start time a reminder goes out; 0 disables
+	Interval time.Duration // How often due assignments are polled
+}
+
+// ConfigFromEnv reads REMINDER_LEAD_MINUTES (default 60, 0 disables) and REMINDER_INTERVAL_SECONDS (default 60).
+func ConfigFromEnv() Config {
+	cfg := Config{Lead: time.Hour, Interval: time.Minute}
+	if n, err := strconv.Atoi(os.Getenv("REMINDER_LEAD_MINUTES")); err == nil && n >= 0 {
+		cfg.Lead = time.Duration(n) * time.Minute
+	}
+	if n, err := strconv.Atoi(os.Getenv("REMINDER_INTERVAL_SECONDS")); err == nil && n > 0 {
+		cfg.Interval = time.Duration(n) * time.Second
+	}
+	return cfg
+}
+
+// Run polls for assignments whose reporting time (or start time when none is set) falls within
+// cfg.Lead and notifies their volunteers through d, until ctx is cancelled. Each assignment is
+// claimed by setting reminder_sent_at in the same statement that selects it, so a reminder is sent
+// at most once even if several instances run; the advisory lock just avoids them doing duplicate work.
+func Run(ctx context.Context, pool *pgxpool.Pool, d *notify.Dispatcher, cfg Config) {
+	if cfg.Lead <= 0 {
+		log.Println("reminders: disabled")
+		return
+	}
+	ticker := time.NewTicker(cfg.Interval)
+	defer ticker.Stop()
+	for {
+		if err := tick(ctx, pool, d, cfg.Lead); err != nil {
+			log.Printf("reminders: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func tick(ctx context.Context, pool *pgxpool.Pool, d *notify.Dispatcher, lead time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	var locked bool
+	if err := conn.QueryRow(ctx, `SELECT pg_try_advisory_lock($1)`, lockKey).Scan(&locked); err != nil {
+		return err
+	}
+	if !locked {
+		return nil // Another instance is on it
+	}
+	defer conn.Exec(context.Background(), `SELECT pg_advisory_unlock($1)`, lockKey)
+
+	rows, err := conn.Query(ctx, `
+		UPDATE volunteer_assignments va
+		SET reminder_sent_at = NOW()
+		FROM volunteers v, committees c, events e
+		WHERE v.id = va.volunteer_id AND c.id = va.committee_id AND e.id = va.event_id
+		  AND va.reminder_sent_at IS NULL
+		  AND va.status <> 'cancelled'
+		  AND COALESCE(va.reporting_time, va.start_time) BETWEEN NOW() AND NOW() + make_interval(secs => $1)
+		RETURNING va.id, va.event_id, va.committee_id, va.shift, COALESCE(va.reporting_time, va.start_time), e.tz,
+		          c.name, v.id, v.name, v.phone, v.email, v.sms_opt_out
+	`, lead.Seconds())
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	sent := 0
+	for rows.Next() {
+		var m notify.Message
+		var r notify.Recipient
+		var committeeID int64
+		var shift *string
+		var at time.Time
+		var tz, committeeName string
+		if err := rows.Scan(&m.AssignmentID, &m.EventID, &committeeID, &shift, &at, &tz, &committeeName,
+			&r.VolunteerID, &r.Name, &r.Phone, &r.Email, &r.SMSOptOut); err != nil {
+			return err
+		}
+		m.Kind = notify.KindShiftReminder
+		m.CommitteeID = &committeeID
+		m.Title = "Shift reminder: " + committeeName
+		m.Body = reminderBody(shift, at, tz)
+
+		recipients := []notify.Recipient{r}
+		d.Publish(recipients, m)
+		d.Dispatch(recipients, m)
+		sent++
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if sent > 0 {
+		log.Printf("reminders: sent %d shift reminder(s)", sent)
+	}
+	return nil
+}
+
+// reminderBody renders the reminder text with the time in the event's timezone.
+func reminderBody(shift *string, at time.Time, tz string) string {
+	if loc, err := time.LoadLocation(tz); err == nil {
+		at = at.In(loc)
+	}
+	what := "Your shift"
+	if shift != nil && *shift != "" {
+		what = "Your shift (" + *shift + ")"
+	}
+	return what + " starts at " + at.Format("15:04 on Mon 2 Jan") + ". Please report on time."
+}
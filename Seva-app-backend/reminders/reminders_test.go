@@ -0,0 +1,44 @@
+package reminders
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"Seva-app-backend/db/dbtest"
+	"Seva-app-backend/notify"
+)
+
+func TestTickLeavesUpdatedAtAlone(t *testing.T) {
+	pool := dbtest.Migrated(t)
+	ctx := context.Background()
+	eventID := dbtest.ID(t, pool, `INSERT INTO events (name) VALUES ('Test event') RETURNING id`)
+	committeeID := dbtest.ID(t, pool, `INSERT INTO committees (event_id, name) VALUES ($1, 'Food') RETURNING id`, eventID)
+	volunteerID := dbtest.ID(t, pool, `INSERT INTO volunteers (name) VALUES ('Asha') RETURNING id`)
+	id := dbtest.ID(t, pool, `
+		INSERT INTO volunteer_assignments (event_id, committee_id, volunteer_id, start_time)
+		VALUES ($1, $2, $3, NOW() + INTERVAL '10 minutes') RETURNING id`, eventID, committeeID, volunteerID)
+
+	var before time.Time
+	if err := pool.QueryRow(ctx, `SELECT updated_at FROM volunteer_assignments WHERE id = $1`, id).Scan(&before); err != nil {
+		t.Fatal(err)
+	}
+
+	d := notify.NewDispatcher(notify.Noop{}, 1, 10)
+	if err := tick(ctx, pool, d, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	var after time.Time
+	var sent *time.Time
+	if err := pool.QueryRow(ctx, `SELECT updated_at, reminder_sent_at FROM volunteer_assignments WHERE id = $1`, id).
+		Scan(&after, &sent); err != nil {
+		t.Fatal(err)
+	}
+	if sent == nil {
+		t.Fatal("reminder_sent_at not set; the assignment was not reminded")
+	}
+	if !after.Equal(before) {
+		t.Fatalf("updated_at moved from %v to %v; a reminder must not look like an edit", before, after)
+	}
+}
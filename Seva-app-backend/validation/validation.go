@@ -0,0 +1,86 @@
+// Package validation collects per-field request validation errors for the handlers.
+package validation
+
+import (
+	"net/mail"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Field error codes used in ValidationError.Fields. Frontends key on these, so keep them stable.
+const (
+	FieldRequired      = "required"
+	FieldInvalidFormat = "invalid_format"
+	FieldInvalidValue  = "invalid_value"
+	FieldTooLong       = "too_long"
+	FieldOutOfRange    = "out_of_range"
+)
+
+// ValidationError is a request validation failure naming the offending JSON fields.
+// ErrorHandler renders it as 422 {"error": "validation_failed", "fields": {"name": "required"}}.
+type ValidationError struct {
+	Fields map[string]string
+}
+
+func (e *ValidationError) Error() string {
+	parts := make([]string, 0, len(e.Fields))
+	for field, code := range e.Fields {
+		parts = append(parts, field+"="+code)
+	}
+	sort.Strings(parts)
+	return "validation failed: " + strings.Join(parts, ", ")
+}
+
+// Validator collects field errors for a request body so they are all reported at once.
+// The zero value is ready to use; only the first error recorded for a field is kept.
+type Validator struct {
+	fields map[string]string
+}
+
+// Add records code for field unless the field already has an error.
+func (v *Validator) Add(field, code string) {
+	if v.fields == nil {
+		v.fields = map[string]string{}
+	}
+	if _, ok := v.fields[field]; !ok {
+		v.fields[field] = code
+	}
+}
+
+// Check records code for field when ok is false, and returns ok.
+func (v *Validator) Check(ok bool, field, code string) bool {
+	if !ok {
+		v.Add(field, code)
+	}
+	return ok
+}
+
+// Required records "required" for field when s is blank, and reports whether s is present.
+func (v *Validator) Required(field, s string) bool {
+	return v.Check(strings.TrimSpace(s) != "", field, FieldRequired)
+}
+
+// MaxLen records "too_long" for field when s is longer than max characters (see the models.Max*Len limits).
+func (v *Validator) MaxLen(field, s string, max int) bool {
+	return v.Check(utf8.RuneCountInString(s) <= max, field, FieldTooLong)
+}
+
+// Email records "invalid_format" for field unless s is a bare address like "a@b.c".
+func (v *Validator) Email(field, s string) bool {
+	addr, err := mail.ParseAddress(s)
+	ok := err == nil && addr.Address == s
+	if ok {
+		domain := s[strings.LastIndex(s, "@")+1:]
+		ok = strings.Contains(domain, ".")
+	}
+	return v.Check(ok, field, FieldInvalidFormat)
+}
+
+// Err returns the collected errors as a *ValidationError, or nil when there are none.
+func (v *Validator) Err() error {
+	if len(v.fields) == 0 {
+		return nil
+	}
+	return &ValidationError{Fields: v.fields}
+}